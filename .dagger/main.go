@@ -142,7 +142,7 @@ func (ci *Forwardtests) EndToEndTests(
 	ticks := TicksService(dag, sourceDir, db, temporal, binanceApiKey, binanceSecretKey)
 
 	// Start Forwardtests service and bind it to the test container (uses shared Postgres)
-	forwardtests := Runner(dag, sourceDir, temporal, db)
+	forwardtests := Runner(dag, sourceDir, temporal, db, nil)
 
 	c := dag.Container().From("golang:" + goVersion() + "-alpine")
 	c = ci.withGoCodeAndCacheAsWorkDirectory(c, sourceDir).
@@ -156,6 +156,29 @@ func (ci *Forwardtests) EndToEndTests(
 	return c.WithExec([]string{"go", "test", "-v", "-tags=e2e", "./test"})
 }
 
+// OfflineEndToEndTests runs the end-to-end tests with only the required infrastructure (DB, Temporal),
+// the forwardtests service generating its own ticks and candlesticks instead of using the other services.
+func (ci *Forwardtests) OfflineEndToEndTests(sourceDir *dagger.Directory) *dagger.Container {
+	// Start shared Postgres service
+	db := PostgresService(dag, sourceDir)
+
+	// Start Temporal service (uses shared Postgres)
+	temporal := TemporalService(dag, sourceDir, db)
+
+	// Start Forwardtests service with a local ticks source
+	forwardtests := Runner(dag, sourceDir, temporal, db, map[string]string{
+		"TICKS_SOURCE": "random",
+	})
+
+	c := dag.Container().From("golang:" + goVersion() + "-alpine")
+	c = ci.withGoCodeAndCacheAsWorkDirectory(c, sourceDir).
+		WithServiceBinding("temporal", temporal).
+		WithServiceBinding("forwardtests", forwardtests).
+		WithEnvVariable("TEMPORAL_ADDRESS", "temporal:7233")
+
+	return c.WithExec([]string{"go", "test", "-v", "-tags=e2e", "./test"})
+}
+
 // Container returns a container with the application built in it.
 func (ci *Forwardtests) Container(
 	sourceDir *dagger.Directory,
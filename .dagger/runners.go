@@ -35,12 +35,13 @@ func AvailablePlatforms() []string {
 }
 
 // Runner returns a container running the forwardtests service built from the official Dockerfile,
-// with its own Postgres and a given Temporal service.
+// with its own Postgres and a given Temporal service. Additional env variables can be set.
 func Runner(
 	_ *dagger.Client,
 	sourceDir *dagger.Directory,
 	temporal *dagger.Service,
 	db *dagger.Service,
+	env map[string]string,
 ) *dagger.Service {
 	// Get the OS and architecture of the current machine
 	os := runtime.GOOS
@@ -80,6 +81,11 @@ func Runner(
 	container = container.WithServiceBinding("temporal", temporal)
 	container = container.WithEnvVariable("TEMPORAL_ADDRESS", "temporal:7233")
 
+	// Set additional env variables
+	for name, value := range env {
+		container = container.WithEnvVariable(name, value)
+	}
+
 	// Expose the default port (9000) as in Dockerfile
 	container = container.WithExposedPort(9000)
 
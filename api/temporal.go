@@ -1,6 +1,10 @@
 package api
 
 import (
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/candlestick"
+	"github.com/cryptellation/candlesticks/pkg/period"
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
 	"github.com/cryptellation/runtime"
 	"github.com/cryptellation/runtime/account"
@@ -19,8 +23,19 @@ const CreateForwardtestWorkflowName = "CreateForwardtestWorkflow"
 type (
 	// CreateForwardtestWorkflowParams is the input for the CreateForwardtestWorkflow.
 	CreateForwardtestWorkflowParams struct {
-		Accounts  map[string]account.Account
-		Callbacks runtime.Callbacks
+		Accounts          map[string]account.Account
+		Callbacks         runtime.Callbacks
+		OptionalCallbacks forwardtest.OptionalCallbacks
+		Risk              forwardtest.RiskLimits
+		Delivery          forwardtest.DeliveryPolicy
+		FeedHealth        forwardtest.FeedHealth
+		TickFilter        forwardtest.TickFilter
+		Execution         forwardtest.ExecutionPolicy
+		Failures          forwardtest.FailurePolicy
+		RecordTicks       bool
+		// Preflight checks that a worker is polling the task queue of each
+		// callback before creating the forwardtest.
+		Preflight bool
 	}
 
 	// CreateForwardtestWorkflowResults is the output for the CreateForwardtestWorkflow.
@@ -34,7 +49,10 @@ const ListForwardtestsWorkflowName = "ListForwardtestsWorkflow"
 
 type (
 	// ListForwardtestsWorkflowParams is the input for the ListForwardtestsWorkflow.
-	ListForwardtestsWorkflowParams struct{}
+	ListForwardtestsWorkflowParams struct {
+		// IncludeArchived also lists the archived forwardtests.
+		IncludeArchived bool
+	}
 
 	// ListForwardtestsWorkflowResults is the output for the ListForwardtestsWorkflow.
 	ListForwardtestsWorkflowResults struct {
@@ -42,18 +60,48 @@ type (
 	}
 )
 
+// UnreachableCallbacksErrorType is the type of the non-retryable application
+// error returned by the CreateForwardtestWorkflow and the RunForwardtestWorkflow
+// when the preflight finds callbacks whose task queue has no worker. The
+// error details contain the names of the unreachable callbacks.
+const UnreachableCallbacksErrorType = "UnreachableCallbacks"
+
 // CreateForwardtestOrderWorkflowName is the name of the CreateForwardtestOrderWorkflow.
 const CreateForwardtestOrderWorkflowName = "CreateForwardtestOrderWorkflow"
 
+// RiskRejectedErrorType is the type of the non-retryable application error
+// returned by the CreateForwardtestOrderWorkflow when an order is rejected by
+// the pre-trade risk checks. The error details contain the broken forwardtest.RiskRule.
+const RiskRejectedErrorType = "RiskRejected"
+
+// FeedStaleErrorType is the type of the non-retryable application error
+// returned by the CreateForwardtestOrderWorkflow when an order is placed on a
+// pair whose price feed is stale and the forwardtest blocks orders in this case.
+const FeedStaleErrorType = "FeedStale"
+
+// InsufficientDepthErrorType is the type of the non-retryable application
+// error returned by the CreateForwardtestOrderWorkflow when the order book of
+// the pair has not enough liquidity to fill the order.
+const InsufficientDepthErrorType = "InsufficientDepth"
+
 type (
 	// CreateForwardtestOrderWorkflowParams is the input for the CreateForwardtestOrderWorkflow.
 	CreateForwardtestOrderWorkflowParams struct {
 		ForwardtestID uuid.UUID
-		Order         order.Order
+		// Order is the order to execute. Its ID makes the submission
+		// idempotent: an order whose ID already exists on the forwardtest is
+		// not executed again. A new ID is generated if not set.
+		Order order.Order
 	}
 
 	// CreateForwardtestOrderWorkflowResults is the output for the CreateForwardtestOrderWorkflow.
-	CreateForwardtestOrderWorkflowResults struct{}
+	CreateForwardtestOrderWorkflowResults struct {
+		// Order is the executed order, with its ID, price and execution time.
+		Order order.Order
+		// Duplicate is true if the order had already been executed by a
+		// previous submission.
+		Duplicate bool
+	}
 )
 
 // ListForwardtestAccountsWorkflowName is the name of the ListForwardtestAccountsWorkflow.
@@ -108,6 +156,9 @@ type (
 	// RunForwardtestWorkflowParams is the input for the RunForwardtestWorkflow.
 	RunForwardtestWorkflowParams struct {
 		ForwardtestID uuid.UUID
+		// Preflight checks that a worker is polling the task queue of each
+		// callback before running the forwardtest.
+		Preflight bool
 	}
 
 	// RunForwardtestWorkflowResults is the output for the RunForwardtestWorkflow.
@@ -136,12 +187,83 @@ type (
 	// StopForwardtestWorkflowParams is the input for the StopForwardtestWorkflow.
 	StopForwardtestWorkflowParams struct {
 		ForwardtestID uuid.UUID
+		// Reason is saved as the status reason of the forwardtest, if set.
+		Reason string
 	}
 
 	// StopForwardtestWorkflowResults is the output for the StopForwardtestWorkflow.
 	StopForwardtestWorkflowResults struct{}
 )
 
+// DeleteForwardtestWorkflowName is the name of the DeleteForwardtestWorkflow.
+const DeleteForwardtestWorkflowName = "DeleteForwardtestWorkflow"
+
+type (
+	// DeleteForwardtestWorkflowParams is the input for the DeleteForwardtestWorkflow.
+	DeleteForwardtestWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		// Force deletes the forwardtest even if it is running.
+		Force bool
+	}
+
+	// DeleteForwardtestWorkflowResults is the output for the DeleteForwardtestWorkflow.
+	DeleteForwardtestWorkflowResults struct{}
+)
+
+// ArchiveForwardtestWorkflowName is the name of the ArchiveForwardtestWorkflow.
+const ArchiveForwardtestWorkflowName = "ArchiveForwardtestWorkflow"
+
+type (
+	// ArchiveForwardtestWorkflowParams is the input for the ArchiveForwardtestWorkflow.
+	ArchiveForwardtestWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		// Archived is the archive flag to set on the forwardtest.
+		Archived bool
+	}
+
+	// ArchiveForwardtestWorkflowResults is the output for the ArchiveForwardtestWorkflow.
+	ArchiveForwardtestWorkflowResults struct{}
+)
+
+// CloneForwardtestWorkflowName is the name of the CloneForwardtestWorkflow.
+const CloneForwardtestWorkflowName = "CloneForwardtestWorkflow"
+
+type (
+	// CloneForwardtestWorkflowParams is the input for the CloneForwardtestWorkflow.
+	// Nil fields are copied from the cloned forwardtest.
+	CloneForwardtestWorkflowParams struct {
+		ForwardtestID     uuid.UUID
+		Accounts          map[string]account.Account
+		Callbacks         *runtime.Callbacks
+		OptionalCallbacks *forwardtest.OptionalCallbacks
+		Risk              *forwardtest.RiskLimits
+		Delivery          *forwardtest.DeliveryPolicy
+		FeedHealth        *forwardtest.FeedHealth
+		TickFilter        *forwardtest.TickFilter
+		Execution         *forwardtest.ExecutionPolicy
+		Failures          *forwardtest.FailurePolicy
+		RecordTicks       *bool
+	}
+
+	// CloneForwardtestWorkflowResults is the output for the CloneForwardtestWorkflow.
+	CloneForwardtestWorkflowResults struct {
+		ID uuid.UUID
+	}
+)
+
+// ResetForwardtestWorkflowName is the name of the ResetForwardtestWorkflow.
+const ResetForwardtestWorkflowName = "ResetForwardtestWorkflow"
+
+type (
+	// ResetForwardtestWorkflowParams is the input for the ResetForwardtestWorkflow.
+	ResetForwardtestWorkflowParams struct {
+		ForwardtestID uuid.UUID
+	}
+
+	// ResetForwardtestWorkflowResults is the output for the ResetForwardtestWorkflow.
+	ResetForwardtestWorkflowResults struct{}
+)
+
 // SubscribeToPriceWorkflowName is the name of the SubscribeToPriceWorkflow.
 const SubscribeToPriceWorkflowName = "SubscribeToPriceWorkflow"
 
@@ -152,8 +274,309 @@ type (
 		OnPriceCallback runtime.CallbackWorkflow
 		Exchange        string
 		Pair            string
+		// Period is the period of the closed candlesticks to deliver to the
+		// OnNewCandlestickCallback. If nil, ticks are delivered to the
+		// OnNewPricesCallback of the forwardtest.
+		Period *period.Symbol
+		// OnNewCandlestickCallback is the callback executed with each closed
+		// candlestick. It is required when a period is set.
+		OnNewCandlestickCallback *runtime.CallbackWorkflow
 	}
 
 	// SubscribeToPriceWorkflowResults is the output for the SubscribeToPriceWorkflow.
 	SubscribeToPriceWorkflowResults struct{}
 )
+
+// OnNewCandlestickCallbackWorkflowParams is the input of the
+// OnNewCandlestickCallback workflow, executed at each period boundary of a
+// subscription with a period.
+type OnNewCandlestickCallbackWorkflowParams struct {
+	Context     runtime.Context
+	Exchange    string
+	Pair        string
+	Period      period.Symbol
+	Candlestick candlestick.Candlestick
+}
+
+// OnNewCandlestickCallbackWorkflowResults is the optional output of the
+// OnNewCandlestickCallback workflow.
+type OnNewCandlestickCallbackWorkflowResults struct {
+	// Orders are executed atomically right after the callback, at the close
+	// price of the candlestick.
+	Orders []order.Order
+}
+
+// OnNewPricesCallbackWorkflowResults is the optional output of the
+// OnNewPricesCallback workflow, whose input is the runtime
+// OnNewPricesCallbackWorkflowParams. As runtime.Runnable callbacks only
+// return an error, bots returning orders are registered with
+// clients.RegisterOrdersRunnable.
+type OnNewPricesCallbackWorkflowResults struct {
+	// Orders are executed atomically right after the callback, each one at
+	// the price of the last delivered tick of its pair.
+	Orders []order.Order
+}
+
+// UnsubscribeFromPriceWorkflowName is the name of the UnsubscribeFromPriceWorkflow.
+const UnsubscribeFromPriceWorkflowName = "UnsubscribeFromPriceWorkflow"
+
+type (
+	// UnsubscribeFromPriceWorkflowParams is the input for the UnsubscribeFromPriceWorkflow.
+	UnsubscribeFromPriceWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+	}
+
+	// UnsubscribeFromPriceWorkflowResults is the output for the UnsubscribeFromPriceWorkflow.
+	UnsubscribeFromPriceWorkflowResults struct{}
+)
+
+// OnFeedStaleCallbackWorkflowParams is the input of the OnFeedStaleCallback
+// workflow, executed when the price feed of a subscribed pair becomes stale.
+type OnFeedStaleCallbackWorkflowParams struct {
+	Context    runtime.Context
+	Exchange   string
+	Pair       string
+	LastTickAt *time.Time
+}
+
+// OrderUpdateStatus is the status of an order carried by the
+// OnOrderUpdateCallback workflow.
+type OrderUpdateStatus string
+
+const (
+	// OrderUpdateStatusFilled is the status of a completely filled order.
+	OrderUpdateStatusFilled OrderUpdateStatus = "filled"
+	// OrderUpdateStatusRejected is the status of an order that has not been
+	// executed, because of the risk checks, the price feed or the order book.
+	OrderUpdateStatusRejected OrderUpdateStatus = "rejected"
+)
+
+// String returns the string representation of the order update status.
+func (s OrderUpdateStatus) String() string {
+	return string(s)
+}
+
+// OnOrderUpdateCallbackWorkflowParams is the input of the OnOrderUpdateCallback
+// workflow, executed when the state of an order of the forwardtest changes.
+type OnOrderUpdateCallbackWorkflowParams struct {
+	Context runtime.Context
+	Status  OrderUpdateStatus
+	// Order is the full state of the order. Its price and execution time are
+	// set when it has been filled.
+	Order order.Order
+	// Reason is the reason of a rejection.
+	Reason string
+	// Accounts are the balances of the forwardtest accounts after the update.
+	Accounts map[string]account.Account
+}
+
+// OnErrorCallbackWorkflowParams is the input of the OnErrorCallback workflow,
+// executed when a callback of the forwardtest has failed after its retries.
+type OnErrorCallbackWorkflowParams struct {
+	Context runtime.Context
+	Error   forwardtest.CallbackError
+	// ConsecutiveFailures is the number of consecutive callback failures of
+	// the forwardtest, including this one.
+	ConsecutiveFailures int
+	// Stopping is true if the forwardtest is stopped because of this failure.
+	Stopping bool
+}
+
+// ListForwardtestSubscriptionsWorkflowName is the name of the ListForwardtestSubscriptionsWorkflow.
+const ListForwardtestSubscriptionsWorkflowName = "ListForwardtestSubscriptionsWorkflow"
+
+type (
+	// ListForwardtestSubscriptionsWorkflowParams is the input for the ListForwardtestSubscriptionsWorkflow.
+	ListForwardtestSubscriptionsWorkflowParams struct {
+		ForwardtestID uuid.UUID
+	}
+
+	// ListForwardtestSubscriptionsWorkflowResults is the output for the ListForwardtestSubscriptionsWorkflow.
+	ListForwardtestSubscriptionsWorkflowResults struct {
+		Subscriptions []forwardtest.Subscription
+	}
+)
+
+// UpdateForwardtestOrderBookWorkflowName is the name of the UpdateForwardtestOrderBookWorkflow.
+const UpdateForwardtestOrderBookWorkflowName = "UpdateForwardtestOrderBookWorkflow"
+
+type (
+	// UpdateForwardtestOrderBookWorkflowParams is the input for the UpdateForwardtestOrderBookWorkflow.
+	UpdateForwardtestOrderBookWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+		// OrderBook is the snapshot of the order book. A top-of-book snapshot
+		// has one level on each side.
+		OrderBook forwardtest.OrderBook
+	}
+
+	// UpdateForwardtestOrderBookWorkflowResults is the output for the UpdateForwardtestOrderBookWorkflow.
+	UpdateForwardtestOrderBookWorkflowResults struct {
+		// Updated is false if the pair is not subscribed or if a more recent
+		// snapshot was already saved.
+		Updated bool
+	}
+)
+
+// RegisterForwardtestTimerWorkflowName is the name of the RegisterForwardtestTimerWorkflow.
+const RegisterForwardtestTimerWorkflowName = "RegisterForwardtestTimerWorkflow"
+
+type (
+	// RegisterForwardtestTimerWorkflowParams is the input for the RegisterForwardtestTimerWorkflow.
+	RegisterForwardtestTimerWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		// Name identifies the timer on the forwardtest.
+		Name string
+		// Interval is the duration between two executions of the callback.
+		Interval time.Duration
+		// Cron is the cron schedule of the callback. It is exclusive with Interval.
+		Cron string
+		// Callback is the workflow executed by the timer, with the
+		// OnTimerCallbackWorkflowParams as input.
+		Callback runtime.CallbackWorkflow
+	}
+
+	// RegisterForwardtestTimerWorkflowResults is the output for the RegisterForwardtestTimerWorkflow.
+	RegisterForwardtestTimerWorkflowResults struct{}
+)
+
+// UnregisterForwardtestTimerWorkflowName is the name of the UnregisterForwardtestTimerWorkflow.
+const UnregisterForwardtestTimerWorkflowName = "UnregisterForwardtestTimerWorkflow"
+
+type (
+	// UnregisterForwardtestTimerWorkflowParams is the input for the UnregisterForwardtestTimerWorkflow.
+	UnregisterForwardtestTimerWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		Name          string
+	}
+
+	// UnregisterForwardtestTimerWorkflowResults is the output for the UnregisterForwardtestTimerWorkflow.
+	UnregisterForwardtestTimerWorkflowResults struct{}
+)
+
+// ListForwardtestTimersWorkflowName is the name of the ListForwardtestTimersWorkflow.
+const ListForwardtestTimersWorkflowName = "ListForwardtestTimersWorkflow"
+
+type (
+	// ListForwardtestTimersWorkflowParams is the input for the ListForwardtestTimersWorkflow.
+	ListForwardtestTimersWorkflowParams struct {
+		ForwardtestID uuid.UUID
+	}
+
+	// ListForwardtestTimersWorkflowResults is the output for the ListForwardtestTimersWorkflow.
+	ListForwardtestTimersWorkflowResults struct {
+		Timers []forwardtest.Timer
+	}
+)
+
+// OnTimerCallbackWorkflowParams is the input of the callback workflow of a
+// timer, executed at each interval or cron schedule.
+type OnTimerCallbackWorkflowParams struct {
+	Context runtime.Context
+	// Name is the name of the timer.
+	Name string
+}
+
+// ReplayForwardtestWorkflowName is the name of the ReplayForwardtestWorkflow.
+const ReplayForwardtestWorkflowName = "ReplayForwardtestWorkflow"
+
+type (
+	// ReplayForwardtestWorkflowParams is the input for the ReplayForwardtestWorkflow.
+	ReplayForwardtestWorkflowParams struct {
+		// SourceID is the ID of the forwardtest whose recorded ticks are replayed.
+		SourceID uuid.UUID
+		// Speed is the replay speed: 1 replays the ticks in real time, 10 ten
+		// times faster and 0 as fast as possible.
+		Speed float64
+		// Callbacks overrides the callbacks of the source forwardtest, if set.
+		Callbacks *runtime.Callbacks
+	}
+
+	// ReplayForwardtestWorkflowResults is the output for the ReplayForwardtestWorkflow.
+	ReplayForwardtestWorkflowResults struct {
+		ID uuid.UUID
+	}
+)
+
+// GetForwardtestDiagnosticsWorkflowName is the name of the GetForwardtestDiagnosticsWorkflow.
+const GetForwardtestDiagnosticsWorkflowName = "GetForwardtestDiagnosticsWorkflow"
+
+type (
+	// GetForwardtestDiagnosticsWorkflowParams is the input for the GetForwardtestDiagnosticsWorkflow.
+	GetForwardtestDiagnosticsWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		// QuarantineLimit is the maximum number of quarantined ticks returned,
+		// the most recent first. Zero means the default limit.
+		QuarantineLimit int
+		// CallbackErrorLimit is the maximum number of callback errors returned,
+		// the most recent first. Zero means the default limit.
+		CallbackErrorLimit int
+	}
+
+	// GetForwardtestDiagnosticsWorkflowResults is the output for the GetForwardtestDiagnosticsWorkflow.
+	GetForwardtestDiagnosticsWorkflowResults struct {
+		Subscriptions []forwardtest.Subscription
+		// RejectedTickCount is the number of ticks rejected by the tick filter
+		// on all the subscriptions.
+		RejectedTickCount int64
+		QuarantinedTicks  []forwardtest.QuarantinedTick
+		CallbackErrors    []forwardtest.CallbackError
+		// ConsecutiveCallbackFailures is the number of callback failures since
+		// the last successful callback.
+		ConsecutiveCallbackFailures int
+	}
+)
+
+// ListForwardtestEventsWorkflowName is the name of the ListForwardtestEventsWorkflow.
+const ListForwardtestEventsWorkflowName = "ListForwardtestEventsWorkflow"
+
+type (
+	// ListForwardtestEventsWorkflowParams is the input for the ListForwardtestEventsWorkflow.
+	ListForwardtestEventsWorkflowParams struct {
+		ForwardtestID uuid.UUID
+		// After is the ID of the last event already seen: only the following
+		// events are returned, the oldest first. Zero returns all the events.
+		After int64
+		// Limit is the maximum number of events returned. Zero means the
+		// default limit.
+		Limit int
+	}
+
+	// ListForwardtestEventsWorkflowResults is the output for the ListForwardtestEventsWorkflow.
+	ListForwardtestEventsWorkflowResults struct {
+		Events []forwardtest.Event
+	}
+)
+
+// ReconcileForwardtestsWorkflowName is the name of the ReconcileForwardtestsWorkflow.
+const ReconcileForwardtestsWorkflowName = "ReconcileForwardtestsWorkflow"
+
+type (
+	// ReconcileForwardtestsWorkflowParams is the input for the ReconcileForwardtestsWorkflow.
+	ReconcileForwardtestsWorkflowParams struct {
+		// TickTimeout is the duration without tick after which a subscription
+		// of a running forwardtest is considered stalled. Zero disables the check.
+		TickTimeout time.Duration
+		// ResubscribeAll registers again all subscriptions of the running
+		// forwardtests, not only the stalled ones. It only applies to the
+		// first reconciliation when executed periodically.
+		ResubscribeAll bool
+		// Interval is the duration between two reconciliations. If zero, the
+		// reconciliation is executed only once.
+		Interval time.Duration
+		// EventsRetention is the duration the forwardtest events are kept
+		// after being recorded. Zero keeps them forever.
+		EventsRetention time.Duration
+	}
+
+	// ReconcileForwardtestsWorkflowResults is the output for the ReconcileForwardtestsWorkflow.
+	ReconcileForwardtestsWorkflowResults struct {
+		// Resubscribed is the number of subscriptions registered again on the ticks service.
+		Resubscribed int
+		// Stalled are the IDs of the running forwardtests with a stalled subscription.
+		Stalled []uuid.UUID
+	}
+)
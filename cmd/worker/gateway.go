@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/cryptellation/forwardtests/configs"
+	"github.com/cryptellation/forwardtests/pkg/clients"
+	"github.com/cryptellation/forwardtests/svc/gateway"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// gatewayShutdownTimeout is the maximum duration to wait for the requests in
+// progress when stopping the gateway.
+const gatewayShutdownTimeout = 10 * time.Second
+
+var gatewayCmd = &cobra.Command{
+	Use:     "gateway",
+	Aliases: []string{"g"},
+	Short:   "Launch the HTTP/JSON gateway of the service",
+	RunE:    serveGateway,
+}
+
+func serveGateway(cmd *cobra.Command, _ []string) error {
+	// Set up context that cancels on SIGTERM or SIGINT
+	ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGTERM, syscall.SIGINT)
+	defer stop()
+
+	// Create temporal client
+	temporalClient, err := createTemporalClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer temporalClient.Close()
+
+	// Create HTTP server, whose requests are cancelled with the context
+	server := &http.Server{
+		Addr:              viper.GetString(configs.EnvGatewayAddress),
+		Handler:           gateway.New(clients.New(temporalClient)),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return ctx },
+	}
+
+	// Stop the server with the context
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), gatewayShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	err = server.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
@@ -17,6 +17,7 @@ var rootCmd = &cobra.Command{
 func main() {
 	// Set commands
 	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(gatewayCmd)
 	addDatabaseCommands(rootCmd)
 
 	// Execute command
@@ -3,22 +3,31 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/cenkalti/backoff/v5"
 	"github.com/cryptellation/forwardtests/api"
 	"github.com/cryptellation/forwardtests/configs"
 	"github.com/cryptellation/forwardtests/svc"
 	"github.com/cryptellation/forwardtests/svc/db/sql"
+	"github.com/cryptellation/forwardtests/svc/localticks"
+	"github.com/cryptellation/forwardtests/svc/preflight"
 	"github.com/cryptellation/health"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/client"
 	temporalwk "go.temporal.io/sdk/worker"
 	"golang.org/x/sync/errgroup"
 )
 
+// reconciliationWorkflowID is the ID of the reconciliation workflow, shared
+// between workers so only one reconciliation runs at a time.
+const reconciliationWorkflowID = "forwardtests-reconciliation"
+
 var serveCmd = &cobra.Command{
 	Use:     "serve",
 	Aliases: []string{"s"},
@@ -41,14 +50,19 @@ func serve(cmd *cobra.Command, _ []string) error {
 	}
 
 	// Temporal worker
-	w, workerCleanup, err := setupWorker(ctx, eg)
+	temporalClient, w, workerCleanup, err := setupWorker(ctx, eg)
 	if err != nil {
 		return err
 	}
 	defer workerCleanup()
 
 	// Service
-	if err := setupService(ctx, w); err != nil {
+	if err := setupService(ctx, temporalClient, w); err != nil {
+		return err
+	}
+
+	// Forwardtests reconciliation
+	if err := startReconciliation(ctx, temporalClient); err != nil {
 		return err
 	}
 
@@ -83,12 +97,12 @@ func setupAndStartHealthServer(ctx context.Context, eg *errgroup.Group) (*health
 	return h, nil
 }
 
-// setupWorker creates the temporal client and worker, and returns the worker and a cleanup function.
-func setupWorker(ctx context.Context, eg *errgroup.Group) (temporalwk.Worker, func(), error) {
+// setupWorker creates the temporal client and worker, and returns them with a cleanup function.
+func setupWorker(ctx context.Context, eg *errgroup.Group) (client.Client, temporalwk.Worker, func(), error) {
 	// Create temporal client
 	temporalClient, err := createTemporalClient(ctx)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	// Create temporal worker and add to errgroup
@@ -112,11 +126,12 @@ func setupWorker(ctx context.Context, eg *errgroup.Group) (temporalwk.Worker, fu
 	// Cleanup function
 	cleanup := func() { temporalClient.Close() }
 
-	return w, cleanup, nil
+	return temporalClient, w, cleanup, nil
 }
 
-// setupService creates the db and service and registers them to the worker.
-func setupService(ctx context.Context, w temporalwk.Worker) error {
+// setupService creates the db, the preflight checks and the service and
+// registers them to the worker.
+func setupService(ctx context.Context, temporalClient client.Client, w temporalwk.Worker) error {
 	// Create db client
 	db, err := createDBClient(ctx)
 	if err != nil {
@@ -124,13 +139,82 @@ func setupService(ctx context.Context, w temporalwk.Worker) error {
 	}
 	db.Register(w)
 
+	// Create local ticks source, if any
+	opts, err := setupTicksSource(w)
+	if err != nil {
+		return err
+	}
+
+	// Create preflight checks
+	checks := preflight.New(temporalClient)
+	checks.Register(w)
+	opts = append(opts, svc.WithPreflight(checks))
+
 	// Create service
-	service := svc.New(db)
+	service := svc.New(db, opts...)
 	service.Register(w)
 
 	return nil
 }
 
+// setupTicksSource creates the local ticks source selected by the config and
+// registers it to the worker. No option is returned for the ticks service.
+func setupTicksSource(w temporalwk.Worker) ([]svc.Option, error) {
+	s := viper.GetString(configs.EnvTicksSource)
+	if s == configs.TicksSourceService {
+		return nil, nil
+	}
+
+	start, err := time.Parse(time.RFC3339, viper.GetString(configs.EnvTicksSourceStart))
+	if err != nil {
+		return nil, fmt.Errorf("parsing ticks source start: %w", err)
+	}
+
+	var source localticks.Source
+	switch s {
+	case configs.TicksSourceRandom:
+		source, err = localticks.NewRandomWalk(localticks.RandomWalkParams{
+			Seed:         viper.GetInt64(configs.EnvTicksSourceSeed),
+			Start:        start,
+			Interval:     viper.GetDuration(configs.EnvTicksSourceInterval),
+			InitialPrice: localticks.DefaultInitialPrice,
+			Volatility:   localticks.DefaultVolatility,
+		})
+	case configs.TicksSourceFile:
+		source, err = localticks.LoadFile(viper.GetString(configs.EnvTicksSourceFile), start)
+	default:
+		err = fmt.Errorf("unknown ticks source %q", s)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	local := localticks.New(source, viper.GetDuration(configs.EnvTicksSourceInterval))
+	local.Register(w)
+
+	return []svc.Option{
+		svc.WithTicksClient(local),
+		svc.WithCandlesticksClient(local.CandlesticksClient()),
+	}, nil
+}
+
+// startReconciliation starts the periodic reconciliation of the running
+// forwardtests, replacing the one started by a previous worker. The first
+// reconciliation registers again all subscriptions to the ticks service.
+func startReconciliation(ctx context.Context, temporalClient client.Client) error {
+	_, err := temporalClient.ExecuteWorkflow(ctx, client.StartWorkflowOptions{
+		ID:                       reconciliationWorkflowID,
+		TaskQueue:                api.WorkerTaskQueueName,
+		WorkflowIDConflictPolicy: enums.WORKFLOW_ID_CONFLICT_POLICY_TERMINATE_EXISTING,
+	}, api.ReconcileForwardtestsWorkflowName, api.ReconcileForwardtestsWorkflowParams{
+		TickTimeout:     viper.GetDuration(configs.EnvTickTimeout),
+		ResubscribeAll:  true,
+		Interval:        viper.GetDuration(configs.EnvReconciliationInterval),
+		EventsRetention: viper.GetDuration(configs.EnvEventsRetention),
+	})
+	return err
+}
+
 func createTemporalClient(ctx context.Context) (client.Client, error) {
 	// Set backoff callback
 	callback := func() (client.Client, error) {
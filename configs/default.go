@@ -1,5 +1,7 @@
 package configs
 
+import "time"
+
 const (
 	// DefaultDBDSN is the default database DSN.
 	DefaultDBDSN = "host=localhost " +
@@ -19,4 +21,42 @@ const (
 
 	// DefaultHealthAddress is the default health address.
 	DefaultHealthAddress = ":9000"
+
+	// DefaultGatewayAddress is the default address of the HTTP gateway.
+	DefaultGatewayAddress = ":8080"
+
+	// DefaultReconciliationInterval is the default interval between two
+	// reconciliations of the running forwardtests.
+	DefaultReconciliationInterval = 5 * time.Minute
+
+	// DefaultTickTimeout is the default duration without tick after which a
+	// forwardtest subscription is considered stalled.
+	DefaultTickTimeout = 2 * time.Minute
+
+	// DefaultEventsRetention is the default duration the forwardtest events
+	// are kept.
+	DefaultEventsRetention = 30 * 24 * time.Hour
+
+	// DefaultTicksSource is the default source of the ticks.
+	DefaultTicksSource = TicksSourceService
+
+	// DefaultTicksSourceSeed is the default seed of the random ticks source.
+	DefaultTicksSourceSeed = 0
+
+	// DefaultTicksSourceInterval is the default interval between two ticks of
+	// a local ticks source.
+	DefaultTicksSourceInterval = time.Second
+
+	// DefaultTicksSourceStart is the default RFC3339 start time of a local
+	// ticks source.
+	DefaultTicksSourceStart = "2024-01-01T00:00:00Z"
+)
+
+const (
+	// TicksSourceService gets the ticks from the ticks service.
+	TicksSourceService = "service"
+	// TicksSourceRandom generates the ticks with a seeded random walk.
+	TicksSourceRandom = "random"
+	// TicksSourceFile reads the ticks from a CSV or JSON file.
+	TicksSourceFile = "file"
 )
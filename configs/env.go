@@ -17,6 +17,41 @@ const EnvTemporalAddress = "TEMPORAL_ADDRESS"
 // EnvHealthAddress is the environment variable name for the health address in the config.
 const EnvHealthAddress = "HEALTH_ADDRESS"
 
+// EnvGatewayAddress is the environment variable name for the HTTP gateway address in the config.
+const EnvGatewayAddress = "GATEWAY_ADDRESS"
+
+// EnvReconciliationInterval is the environment variable name for the reconciliation interval in the config.
+const EnvReconciliationInterval = "RECONCILIATION_INTERVAL"
+
+// EnvEventsRetention is the environment variable name for the duration the
+// forwardtest events are kept in the config. Zero keeps them forever.
+const EnvEventsRetention = "EVENTS_RETENTION"
+
+// EnvTickTimeout is the environment variable name for the tick timeout in the config.
+const EnvTickTimeout = "TICK_TIMEOUT"
+
+// EnvTicksSource is the environment variable name for the source of the ticks
+// in the config: "service" for the ticks service, "random" for a seeded random
+// walk or "file" for the ticks of a file.
+const EnvTicksSource = "TICKS_SOURCE"
+
+// EnvTicksSourceFile is the environment variable name for the CSV or JSON file
+// of the "file" ticks source in the config.
+const EnvTicksSourceFile = "TICKS_SOURCE_FILE"
+
+// EnvTicksSourceSeed is the environment variable name for the seed of the
+// "random" ticks source in the config.
+const EnvTicksSourceSeed = "TICKS_SOURCE_SEED"
+
+// EnvTicksSourceInterval is the environment variable name for the interval
+// between two ticks of a local ticks source in the config.
+const EnvTicksSourceInterval = "TICKS_SOURCE_INTERVAL"
+
+// EnvTicksSourceStart is the environment variable name for the RFC3339 start
+// time of a local ticks source in the config. The same start gives the same
+// prices across worker restarts.
+const EnvTicksSourceStart = "TICKS_SOURCE_START"
+
 func init() {
 	// Tell viper to read environment variables
 	viper.AutomaticEnv()
@@ -27,4 +62,12 @@ func init() {
 	viper.SetDefault(EnvBinanceSecretKey, DefaultBinanceSecretKey)
 	viper.SetDefault(EnvTemporalAddress, DefaultTemporalAddress)
 	viper.SetDefault(EnvHealthAddress, DefaultHealthAddress)
+	viper.SetDefault(EnvGatewayAddress, DefaultGatewayAddress)
+	viper.SetDefault(EnvReconciliationInterval, DefaultReconciliationInterval)
+	viper.SetDefault(EnvTickTimeout, DefaultTickTimeout)
+	viper.SetDefault(EnvEventsRetention, DefaultEventsRetention)
+	viper.SetDefault(EnvTicksSource, DefaultTicksSource)
+	viper.SetDefault(EnvTicksSourceSeed, DefaultTicksSourceSeed)
+	viper.SetDefault(EnvTicksSourceInterval, DefaultTicksSourceInterval)
+	viper.SetDefault(EnvTicksSourceStart, DefaultTicksSourceStart)
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/spf13/viper"
 	"github.com/stretchr/testify/suite"
@@ -30,3 +31,40 @@ func (suite *ViperSuite) TestDBDSN() {
 	// Test the overridden value of the database DSN
 	suite.Equal("test", viper.GetString(EnvSQLDSN))
 }
+
+func (suite *ViperSuite) TestReconciliation() {
+	// Test the default values of the reconciliation
+	suite.Equal(DefaultReconciliationInterval, viper.GetDuration(EnvReconciliationInterval))
+	suite.Equal(DefaultTickTimeout, viper.GetDuration(EnvTickTimeout))
+	suite.Equal(DefaultEventsRetention, viper.GetDuration(EnvEventsRetention))
+
+	// Set environment variable for the tick timeout
+	os.Setenv(strings.ToUpper(EnvTickTimeout), "30s")
+
+	// Test the overridden value of the tick timeout
+	suite.Equal(30*time.Second, viper.GetDuration(EnvTickTimeout))
+}
+
+func (suite *ViperSuite) TestTicksSource() {
+	// Test the default values of the ticks source
+	suite.Equal(TicksSourceService, viper.GetString(EnvTicksSource))
+	suite.Equal(DefaultTicksSourceInterval, viper.GetDuration(EnvTicksSourceInterval))
+	suite.Equal(DefaultTicksSourceStart, viper.GetString(EnvTicksSourceStart))
+
+	// Set environment variable for the ticks source
+	os.Setenv(strings.ToUpper(EnvTicksSource), TicksSourceRandom)
+
+	// Test the overridden value of the ticks source
+	suite.Equal(TicksSourceRandom, viper.GetString(EnvTicksSource))
+}
+
+func (suite *ViperSuite) TestGatewayAddress() {
+	// Test the default value of the gateway address
+	suite.Equal(DefaultGatewayAddress, viper.GetString(EnvGatewayAddress))
+
+	// Set environment variable for the gateway address
+	os.Setenv(strings.ToUpper(EnvGatewayAddress), ":8081")
+
+	// Test the overridden value of the gateway address
+	suite.Equal(":8081", viper.GetString(EnvGatewayAddress))
+}
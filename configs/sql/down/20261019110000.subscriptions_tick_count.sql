@@ -0,0 +1,2 @@
+ALTER TABLE forwardtest_subscriptions
+    DROP COLUMN tick_count;
@@ -0,0 +1,3 @@
+ALTER TABLE forwardtest_subscriptions
+    DROP COLUMN period,
+    DROP COLUMN on_new_candlestick_callback;
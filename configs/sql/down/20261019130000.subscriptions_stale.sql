@@ -0,0 +1,2 @@
+ALTER TABLE forwardtest_subscriptions
+    DROP COLUMN stale_since;
@@ -0,0 +1,5 @@
+DROP TABLE forwardtest_quarantined_ticks;
+
+ALTER TABLE forwardtest_subscriptions
+    DROP COLUMN last_price,
+    DROP COLUMN rejected_tick_count;
@@ -0,0 +1 @@
+DROP TABLE forwardtest_ticks;
@@ -0,0 +1,4 @@
+DROP TABLE forwardtest_callback_errors;
+
+ALTER TABLE forwardtests
+    DROP COLUMN consecutive_callback_failures;
@@ -0,0 +1,12 @@
+DROP INDEX idx_forwardtest_events_recorded_at;
+
+CREATE INDEX idx_forwardtest_events_forwardtest
+    ON forwardtest_events (forwardtest_id, id);
+
+ALTER TABLE forwardtest_events
+    DROP CONSTRAINT uq_forwardtest_events_seq,
+    DROP COLUMN seq,
+    DROP COLUMN recorded_at;
+
+ALTER TABLE forwardtests
+    DROP COLUMN event_seq;
@@ -0,0 +1,11 @@
+CREATE TABLE forwardtest_subscriptions
+(
+    forwardtest_id VARCHAR(255) NOT NULL,
+    exchange VARCHAR(255) NOT NULL,
+    pair VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    last_tick_at TIMESTAMP,
+    CONSTRAINT pk_forwardtest_subscriptions PRIMARY KEY (forwardtest_id, exchange, pair),
+    CONSTRAINT fk_forwardtest_subscriptions_forwardtests FOREIGN KEY (forwardtest_id)
+        REFERENCES forwardtests (id) ON DELETE CASCADE
+);
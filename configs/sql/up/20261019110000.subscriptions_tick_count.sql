@@ -0,0 +1,2 @@
+ALTER TABLE forwardtest_subscriptions
+    ADD COLUMN tick_count BIGINT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE forwardtest_subscriptions
+    ADD COLUMN period VARCHAR(8),
+    ADD COLUMN on_new_candlestick_callback JSONB;
@@ -0,0 +1,2 @@
+ALTER TABLE forwardtest_subscriptions
+    ADD COLUMN stale_since TIMESTAMP;
@@ -0,0 +1,22 @@
+ALTER TABLE forwardtest_subscriptions
+    ADD COLUMN last_price DOUBLE PRECISION NOT NULL DEFAULT 0,
+    ADD COLUMN rejected_tick_count BIGINT NOT NULL DEFAULT 0;
+
+CREATE TABLE forwardtest_quarantined_ticks
+(
+    id BIGSERIAL NOT NULL,
+    forwardtest_id VARCHAR(255) NOT NULL,
+    exchange VARCHAR(255) NOT NULL,
+    pair VARCHAR(255) NOT NULL,
+    time TIMESTAMP NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    rule VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    quarantined_at TIMESTAMP NOT NULL,
+    CONSTRAINT pk_forwardtest_quarantined_ticks PRIMARY KEY (id),
+    CONSTRAINT fk_forwardtest_quarantined_ticks_forwardtests FOREIGN KEY (forwardtest_id)
+        REFERENCES forwardtests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_forwardtest_quarantined_ticks_forwardtest
+    ON forwardtest_quarantined_ticks (forwardtest_id, quarantined_at);
@@ -0,0 +1,15 @@
+CREATE TABLE forwardtest_ticks
+(
+    id BIGSERIAL NOT NULL,
+    forwardtest_id VARCHAR(255) NOT NULL,
+    exchange VARCHAR(255) NOT NULL,
+    pair VARCHAR(255) NOT NULL,
+    time TIMESTAMP NOT NULL,
+    price DOUBLE PRECISION NOT NULL,
+    CONSTRAINT pk_forwardtest_ticks PRIMARY KEY (id),
+    CONSTRAINT fk_forwardtest_ticks_forwardtests FOREIGN KEY (forwardtest_id)
+        REFERENCES forwardtests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_forwardtest_ticks_forwardtest
+    ON forwardtest_ticks (forwardtest_id, id);
@@ -0,0 +1,2 @@
+ALTER TABLE forwardtest_subscriptions
+    ADD COLUMN order_book JSONB;
@@ -0,0 +1,12 @@
+CREATE TABLE forwardtest_timers
+(
+    forwardtest_id VARCHAR(255) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    interval BIGINT NOT NULL DEFAULT 0,
+    cron VARCHAR(255) NOT NULL DEFAULT '',
+    callback JSONB NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    CONSTRAINT pk_forwardtest_timers PRIMARY KEY (forwardtest_id, name),
+    CONSTRAINT fk_forwardtest_timers_forwardtests FOREIGN KEY (forwardtest_id)
+        REFERENCES forwardtests (id) ON DELETE CASCADE
+);
@@ -0,0 +1,17 @@
+ALTER TABLE forwardtests
+    ADD COLUMN consecutive_callback_failures INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE forwardtest_callback_errors
+(
+    id BIGSERIAL NOT NULL,
+    forwardtest_id VARCHAR(255) NOT NULL,
+    time TIMESTAMP NOT NULL,
+    callback VARCHAR(255) NOT NULL,
+    error TEXT NOT NULL,
+    CONSTRAINT pk_forwardtest_callback_errors PRIMARY KEY (id),
+    CONSTRAINT fk_forwardtest_callback_errors_forwardtests FOREIGN KEY (forwardtest_id)
+        REFERENCES forwardtests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_forwardtest_callback_errors_forwardtest
+    ON forwardtest_callback_errors (forwardtest_id, time);
@@ -0,0 +1,14 @@
+CREATE TABLE forwardtest_events
+(
+    id BIGSERIAL NOT NULL,
+    forwardtest_id VARCHAR(255) NOT NULL,
+    time TIMESTAMP NOT NULL,
+    type VARCHAR(255) NOT NULL,
+    data JSONB NOT NULL,
+    CONSTRAINT pk_forwardtest_events PRIMARY KEY (id),
+    CONSTRAINT fk_forwardtest_events_forwardtests FOREIGN KEY (forwardtest_id)
+        REFERENCES forwardtests (id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_forwardtest_events_forwardtest
+    ON forwardtest_events (forwardtest_id, id);
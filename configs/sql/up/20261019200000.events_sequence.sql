@@ -0,0 +1,26 @@
+ALTER TABLE forwardtests
+    ADD COLUMN event_seq BIGINT NOT NULL DEFAULT 0;
+
+ALTER TABLE forwardtest_events
+    ADD COLUMN seq BIGINT,
+    ADD COLUMN recorded_at TIMESTAMP NOT NULL DEFAULT (NOW() AT TIME ZONE 'UTC');
+
+UPDATE forwardtest_events AS e
+SET seq = n.seq
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY forwardtest_id ORDER BY id) AS seq
+    FROM forwardtest_events
+) AS n
+WHERE e.id = n.id;
+
+UPDATE forwardtests AS f
+SET event_seq = COALESCE((SELECT MAX(seq) FROM forwardtest_events AS e WHERE e.forwardtest_id = f.id), 0);
+
+ALTER TABLE forwardtest_events
+    ALTER COLUMN seq SET NOT NULL,
+    ADD CONSTRAINT uq_forwardtest_events_seq UNIQUE (forwardtest_id, seq);
+
+DROP INDEX idx_forwardtest_events_forwardtest;
+
+CREATE INDEX idx_forwardtest_events_recorded_at
+    ON forwardtest_events (recorded_at);
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/cryptellation/forwardtests/api"
+	"github.com/google/uuid"
 	temporalclient "go.temporal.io/sdk/client"
 )
 
@@ -13,14 +14,19 @@ type Client interface {
 	NewForwardtest(
 		ctx context.Context,
 		params api.CreateForwardtestWorkflowParams,
+		opts ...Option,
 	) (Forwardtest, error)
+	// Forwardtest returns the forwardtest with the given ID, without checking
+	// that it exists.
+	Forwardtest(id uuid.UUID) Forwardtest
 	// ListForwardtests lists the forwardtests.
 	ListForwardtests(
 		ctx context.Context,
 		params api.ListForwardtestsWorkflowParams,
+		opts ...Option,
 	) ([]Forwardtest, error)
 	// Info calls the service info.
-	Info(ctx context.Context) (api.ServiceInfoResults, error)
+	Info(ctx context.Context, opts ...Option) (api.ServiceInfoResults, error)
 	// RawClient returns the raw client.
 	RawClient() RawClient
 }
@@ -47,8 +53,9 @@ func (c client) RawClient() RawClient {
 func (c client) NewForwardtest(
 	ctx context.Context,
 	params api.CreateForwardtestWorkflowParams,
+	opts ...Option,
 ) (Forwardtest, error) {
-	res, err := c.raw.CreateForwardtest(ctx, params)
+	res, err := c.raw.CreateForwardtest(ctx, params, opts...)
 	return Forwardtest{
 		ID:        res.ID,
 		rawClient: c.raw,
@@ -59,8 +66,9 @@ func (c client) NewForwardtest(
 func (c client) ListForwardtests(
 	ctx context.Context,
 	params api.ListForwardtestsWorkflowParams,
+	opts ...Option,
 ) ([]Forwardtest, error) {
-	res, err := c.raw.ListForwardtests(ctx, params)
+	res, err := c.raw.ListForwardtests(ctx, params, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -76,19 +84,16 @@ func (c client) ListForwardtests(
 	return forwardtests, nil
 }
 
-// Info calls the service info.
-func (c client) Info(ctx context.Context) (res api.ServiceInfoResults, err error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.ServiceInfoWorkflowName)
-	if err != nil {
-		return api.ServiceInfoResults{}, err
+// Forwardtest returns the forwardtest with the given ID, without checking
+// that it exists.
+func (c client) Forwardtest(id uuid.UUID) Forwardtest {
+	return Forwardtest{
+		ID:        id,
+		rawClient: c.raw,
 	}
+}
 
-	// Get result and return
-	err = exec.Get(ctx, &res)
-	return res, err
+// Info calls the service info.
+func (c client) Info(ctx context.Context, opts ...Option) (api.ServiceInfoResults, error) {
+	return execute[api.ServiceInfoResults](ctx, c.temporal, opts, api.ServiceInfoWorkflowName)
 }
@@ -17,33 +17,48 @@ type Forwardtest struct {
 }
 
 // Run runs the forwardtest with the given bot.
-func (ft *Forwardtest) Run(ctx context.Context) error {
+func (ft *Forwardtest) Run(ctx context.Context, opts ...Option) error {
 	// Run forwardtest
 	_, err := ft.rawClient.RunForwardtest(ctx, api.RunForwardtestWorkflowParams{
 		ForwardtestID: ft.ID,
-	})
+	}, opts...)
 
 	return err
 }
 
-// CreateOrder creates an order on the forwardtest.
+// RunWithPreflight runs the forwardtest after checking that a worker is
+// polling the task queue of each of its callbacks. The check fails with an
+// UnreachableCallbacks application error otherwise.
+func (ft *Forwardtest) RunWithPreflight(ctx context.Context, opts ...Option) error {
+	_, err := ft.rawClient.RunForwardtest(ctx, api.RunForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+		Preflight:     true,
+	}, opts...)
+
+	return err
+}
+
+// CreateOrder creates an order on the forwardtest. An order with an ID is
+// only executed once, even when submitted again.
 func (ft Forwardtest) CreateOrder(
 	ctx context.Context,
 	order order.Order,
+	opts ...Option,
 ) (api.CreateForwardtestOrderWorkflowResults, error) {
 	return ft.rawClient.CreateForwardtestOrder(ctx, api.CreateForwardtestOrderWorkflowParams{
 		ForwardtestID: ft.ID,
 		Order:         order,
-	})
+	}, opts...)
 }
 
 // ListAccounts lists the accounts of the forwardtest.
 func (ft Forwardtest) ListAccounts(
 	ctx context.Context,
+	opts ...Option,
 ) (map[string]account.Account, error) {
 	res, err := ft.rawClient.ListForwardtestAccounts(ctx, api.ListForwardtestAccountsWorkflowParams{
 		ForwardtestID: ft.ID,
-	})
+	}, opts...)
 	if err != nil {
 		return nil, err
 	}
@@ -52,10 +67,10 @@ func (ft Forwardtest) ListAccounts(
 }
 
 // Get retrieves the forwardtest data from the database.
-func (ft Forwardtest) Get(ctx context.Context) (forwardtest.Forwardtest, error) {
+func (ft Forwardtest) Get(ctx context.Context, opts ...Option) (forwardtest.Forwardtest, error) {
 	res, err := ft.rawClient.GetForwardtest(ctx, api.GetForwardtestWorkflowParams{
 		ForwardtestID: ft.ID,
-	})
+	}, opts...)
 	if err != nil {
 		return forwardtest.Forwardtest{}, err
 	}
@@ -66,10 +81,11 @@ func (ft Forwardtest) Get(ctx context.Context) (forwardtest.Forwardtest, error)
 // GetBalance gets the balance of the forwardtest.
 func (ft Forwardtest) GetBalance(
 	ctx context.Context,
+	opts ...Option,
 ) (float64, error) {
 	res, err := ft.rawClient.GetForwardtestBalance(ctx, api.GetForwardtestBalanceWorkflowParams{
 		ForwardtestID: ft.ID,
-	})
+	}, opts...)
 	if err != nil {
 		return 0, err
 	}
@@ -78,10 +94,232 @@ func (ft Forwardtest) GetBalance(
 }
 
 // Stop stops the forwardtest by executing the exit callback.
-func (ft Forwardtest) Stop(ctx context.Context) error {
+func (ft Forwardtest) Stop(ctx context.Context, opts ...Option) error {
 	_, err := ft.rawClient.StopForwardtest(ctx, api.StopForwardtestWorkflowParams{
 		ForwardtestID: ft.ID,
-	})
+	}, opts...)
 
 	return err
 }
+
+// Delete deletes the forwardtest. A running forwardtest is only deleted when
+// force is set.
+func (ft Forwardtest) Delete(ctx context.Context, force bool, opts ...Option) error {
+	_, err := ft.rawClient.DeleteForwardtest(ctx, api.DeleteForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+		Force:         force,
+	}, opts...)
+
+	return err
+}
+
+// Archive hides the forwardtest from the default listing while keeping its data.
+func (ft Forwardtest) Archive(ctx context.Context, opts ...Option) error {
+	_, err := ft.rawClient.ArchiveForwardtest(ctx, api.ArchiveForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+		Archived:      true,
+	}, opts...)
+
+	return err
+}
+
+// Unarchive shows the forwardtest again in the default listing.
+func (ft Forwardtest) Unarchive(ctx context.Context, opts ...Option) error {
+	_, err := ft.rawClient.ArchiveForwardtest(ctx, api.ArchiveForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+		Archived:      false,
+	}, opts...)
+
+	return err
+}
+
+// Clone creates a new forwardtest with the same configuration, overriding the
+// non-nil fields of the parameters. The forwardtest ID in parameters is ignored.
+func (ft Forwardtest) Clone(
+	ctx context.Context,
+	params api.CloneForwardtestWorkflowParams,
+	opts ...Option,
+) (Forwardtest, error) {
+	params.ForwardtestID = ft.ID
+	res, err := ft.rawClient.CloneForwardtest(ctx, params, opts...)
+	return Forwardtest{
+		ID:        res.ID,
+		rawClient: ft.rawClient,
+	}, err
+}
+
+// Reset restores the forwardtest to its initial state, keeping its ID.
+func (ft Forwardtest) Reset(ctx context.Context, opts ...Option) error {
+	_, err := ft.rawClient.ResetForwardtest(ctx, api.ResetForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+	}, opts...)
+
+	return err
+}
+
+// ListSubscriptions lists the price subscriptions of the forwardtest.
+func (ft Forwardtest) ListSubscriptions(ctx context.Context, opts ...Option) ([]forwardtest.Subscription, error) {
+	res, err := ft.rawClient.ListForwardtestSubscriptions(ctx, api.ListForwardtestSubscriptionsWorkflowParams{
+		ForwardtestID: ft.ID,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Subscriptions, nil
+}
+
+// UpdateOrderBook saves an order book snapshot of a subscribed pair, used to
+// fill the orders of a forwardtest with bid/ask execution.
+func (ft Forwardtest) UpdateOrderBook(
+	ctx context.Context,
+	exchange, pair string,
+	book forwardtest.OrderBook,
+	opts ...Option,
+) error {
+	_, err := ft.rawClient.UpdateForwardtestOrderBook(ctx, api.UpdateForwardtestOrderBookWorkflowParams{
+		ForwardtestID: ft.ID,
+		Exchange:      exchange,
+		Pair:          pair,
+		OrderBook:     book,
+	}, opts...)
+	return err
+}
+
+// ListTimers lists the recurring timers registered on the forwardtest.
+func (ft Forwardtest) ListTimers(ctx context.Context, opts ...Option) ([]forwardtest.Timer, error) {
+	res, err := ft.rawClient.ListForwardtestTimers(ctx, api.ListForwardtestTimersWorkflowParams{
+		ForwardtestID: ft.ID,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Timers, nil
+}
+
+// GetDiagnostics gets the state of the price feeds of the forwardtest: its
+// subscriptions and the last ticks rejected by its tick filter, along with
+// the last failures of its callbacks.
+func (ft Forwardtest) GetDiagnostics(
+	ctx context.Context,
+	opts ...Option,
+) (api.GetForwardtestDiagnosticsWorkflowResults, error) {
+	return ft.rawClient.GetForwardtestDiagnostics(ctx, api.GetForwardtestDiagnosticsWorkflowParams{
+		ForwardtestID: ft.ID,
+	}, opts...)
+}
+
+// ListEvents lists at most limit events of the forwardtest after the given
+// event ID, the oldest first. A zero ID lists the events from the start and
+// a zero limit uses the default limit of the service.
+func (ft Forwardtest) ListEvents(
+	ctx context.Context,
+	after int64,
+	limit int,
+	opts ...Option,
+) ([]forwardtest.Event, error) {
+	res, err := ft.rawClient.ListForwardtestEvents(ctx, api.ListForwardtestEventsWorkflowParams{
+		ForwardtestID: ft.ID,
+		After:         after,
+		Limit:         limit,
+	}, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return res.Events, nil
+}
+
+// Replay creates a new forwardtest on which the ticks recorded on this
+// forwardtest are replayed at the given speed (0 replays them as fast as
+// possible). The forwardtest must have been created with ticks recording.
+func (ft Forwardtest) Replay(ctx context.Context, speed float64, opts ...Option) (Forwardtest, error) {
+	res, err := ft.rawClient.ReplayForwardtest(ctx, api.ReplayForwardtestWorkflowParams{
+		SourceID: ft.ID,
+		Speed:    speed,
+	}, opts...)
+	return Forwardtest{
+		ID:        res.ID,
+		rawClient: ft.rawClient,
+	}, err
+}
+
+// Subscribe subscribes the forwardtest to the prices of a pair. The
+// forwardtest ID in parameters is ignored.
+func (ft Forwardtest) Subscribe(
+	ctx context.Context,
+	params api.SubscribeToPriceWorkflowParams,
+	opts ...Option,
+) error {
+	params.ForwardtestID = ft.ID
+	_, err := ft.rawClient.SubscribeToPrice(ctx, params, opts...)
+	return err
+}
+
+// Unsubscribe unsubscribes the forwardtest from the prices of a pair.
+func (ft Forwardtest) Unsubscribe(ctx context.Context, exchange, pair string, opts ...Option) error {
+	_, err := ft.rawClient.UnsubscribeFromPrice(ctx, api.UnsubscribeFromPriceWorkflowParams{
+		ForwardtestID: ft.ID,
+		Exchange:      exchange,
+		Pair:          pair,
+	}, opts...)
+	return err
+}
+
+// RegisterTimer registers a recurring timer on the forwardtest. The
+// forwardtest ID in parameters is ignored.
+func (ft Forwardtest) RegisterTimer(
+	ctx context.Context,
+	params api.RegisterForwardtestTimerWorkflowParams,
+	opts ...Option,
+) error {
+	params.ForwardtestID = ft.ID
+	_, err := ft.rawClient.RegisterForwardtestTimer(ctx, params, opts...)
+	return err
+}
+
+// UnregisterTimer cancels a recurring timer of the forwardtest.
+func (ft Forwardtest) UnregisterTimer(ctx context.Context, name string, opts ...Option) error {
+	_, err := ft.rawClient.UnregisterForwardtestTimer(ctx, api.UnregisterForwardtestTimerWorkflowParams{
+		ForwardtestID: ft.ID,
+		Name:          name,
+	}, opts...)
+	return err
+}
+
+// RunAsync starts running the forwardtest and returns a handle on the run
+// without waiting for the OnInit callback to complete.
+func (ft Forwardtest) RunAsync(
+	ctx context.Context,
+	opts ...Option,
+) (Handle[api.RunForwardtestWorkflowResults], error) {
+	return ft.rawClient.RunForwardtestAsync(ctx, api.RunForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+	}, opts...)
+}
+
+// StopAsync starts stopping the forwardtest and returns a handle on the stop
+// without waiting for the exit callback to complete.
+func (ft Forwardtest) StopAsync(
+	ctx context.Context,
+	opts ...Option,
+) (Handle[api.StopForwardtestWorkflowResults], error) {
+	return ft.rawClient.StopForwardtestAsync(ctx, api.StopForwardtestWorkflowParams{
+		ForwardtestID: ft.ID,
+	}, opts...)
+}
+
+// ReplayAsync starts the creation of a forwardtest replaying the ticks
+// recorded on this forwardtest and returns a handle on it. The ID of the new
+// forwardtest is in the results of the handle.
+func (ft Forwardtest) ReplayAsync(
+	ctx context.Context,
+	speed float64,
+	opts ...Option,
+) (Handle[api.ReplayForwardtestWorkflowResults], error) {
+	return ft.rawClient.ReplayForwardtestAsync(ctx, api.ReplayForwardtestWorkflowParams{
+		SourceID: ft.ID,
+		Speed:    speed,
+	}, opts...)
+}
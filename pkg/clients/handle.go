@@ -0,0 +1,81 @@
+package clients
+
+import (
+	"context"
+	"errors"
+
+	"go.temporal.io/api/serviceerror"
+	temporalclient "go.temporal.io/sdk/client"
+)
+
+// Handle is a handle on a workflow of the service started asynchronously,
+// whose results are of type R.
+type Handle[R any] struct {
+	temporal temporalclient.Client
+	run      temporalclient.WorkflowRun
+}
+
+// WorkflowID returns the ID of the workflow.
+func (h Handle[R]) WorkflowID() string {
+	return h.run.GetID()
+}
+
+// RunID returns the ID of the run of the workflow.
+func (h Handle[R]) RunID() string {
+	return h.run.GetRunID()
+}
+
+// Get waits for the workflow to complete and returns its results.
+func (h Handle[R]) Get(ctx context.Context) (R, error) {
+	var res R
+	err := h.run.Get(ctx, &res)
+	return res, err
+}
+
+// Cancel requests the cancellation of the workflow.
+func (h Handle[R]) Cancel(ctx context.Context) error {
+	return h.temporal.CancelWorkflow(ctx, h.run.GetID(), h.run.GetRunID())
+}
+
+// start starts a workflow of the service and returns a handle on it.
+func start[R any](
+	ctx context.Context,
+	cl temporalclient.Client,
+	opts []Option,
+	workflowName string,
+	args ...any,
+) (Handle[R], error) {
+	workflowOptions := startWorkflowOptions(workflowName, opts)
+	run, err := cl.ExecuteWorkflow(ctx, workflowOptions, workflowName, args...)
+
+	// Get the workflow already executed with this ID, if any
+	var alreadyStarted *serviceerror.WorkflowExecutionAlreadyStarted
+	if errors.As(err, &alreadyStarted) && workflowOptions.ID != "" {
+		run, err = cl.GetWorkflow(ctx, workflowOptions.ID, ""), nil
+	}
+	if err != nil {
+		return Handle[R]{}, err
+	}
+
+	return Handle[R]{
+		temporal: cl,
+		run:      run,
+	}, nil
+}
+
+// execute executes a workflow of the service and waits for its results.
+func execute[R any](
+	ctx context.Context,
+	cl temporalclient.Client,
+	opts []Option,
+	workflowName string,
+	args ...any,
+) (R, error) {
+	h, err := start[R](ctx, cl, opts, workflowName, args...)
+	if err != nil {
+		var res R
+		return res, err
+	}
+
+	return h.Get(ctx)
+}
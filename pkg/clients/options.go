@@ -0,0 +1,77 @@
+package clients
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"go.temporal.io/api/enums/v1"
+	temporalclient "go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+)
+
+// callOptions are the options of a call to the service.
+type callOptions struct {
+	workflow       temporalclient.StartWorkflowOptions
+	idempotencyKey string
+}
+
+// Option is an option of a call to the service.
+type Option func(opts *callOptions)
+
+// WithWorkflowID sets the ID of the workflow executed by the call. Calls
+// with the same workflow ID are deduplicated by Temporal while the first one
+// is running. It takes precedence over the idempotency key.
+func WithWorkflowID(id string) Option {
+	return func(opts *callOptions) {
+		opts.workflow.ID = id
+	}
+}
+
+// WithIdempotencyKey makes the call idempotent: the workflow ID is derived
+// from the workflow name and the key, so a call retried with the same key
+// returns the results of the first successful call instead of executing the
+// workflow again. Calls whose workflow failed can be retried with the same key.
+func WithIdempotencyKey(key string) Option {
+	return func(opts *callOptions) {
+		opts.idempotencyKey = key
+	}
+}
+
+// WithTimeout sets the maximum duration of the workflow executed by the
+// call, including its retries.
+func WithTimeout(timeout time.Duration) Option {
+	return func(opts *callOptions) {
+		opts.workflow.WorkflowExecutionTimeout = timeout
+	}
+}
+
+// WithRetryPolicy sets the retry policy of the workflow executed by the call.
+// Without it, the workflow is not retried.
+func WithRetryPolicy(policy temporal.RetryPolicy) Option {
+	return func(opts *callOptions) {
+		opts.workflow.RetryPolicy = &policy
+	}
+}
+
+// startWorkflowOptions returns the options to start a workflow of the
+// service with the given options.
+func startWorkflowOptions(workflowName string, opts []Option) temporalclient.StartWorkflowOptions {
+	co := callOptions{
+		workflow: temporalclient.StartWorkflowOptions{
+			TaskQueue: api.WorkerTaskQueueName,
+		},
+	}
+
+	for _, opt := range opts {
+		opt(&co)
+	}
+
+	// Derive the workflow ID from the idempotency key, if any
+	if co.workflow.ID == "" && co.idempotencyKey != "" {
+		co.workflow.ID = fmt.Sprintf("%s-%s", workflowName, co.idempotencyKey)
+		co.workflow.WorkflowIDReusePolicy = enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE_FAILED_ONLY
+	}
+
+	return co.workflow
+}
@@ -2,8 +2,10 @@ package clients
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/cryptellation/forwardtests/api"
+	"github.com/google/uuid"
 	temporalclient "go.temporal.io/sdk/client"
 )
 
@@ -13,35 +15,131 @@ type RawClient interface {
 	CreateForwardtest(
 		ctx context.Context,
 		params api.CreateForwardtestWorkflowParams,
+		opts ...Option,
 	) (api.CreateForwardtestWorkflowResults, error)
 	GetForwardtest(
 		ctx context.Context,
 		params api.GetForwardtestWorkflowParams,
+		opts ...Option,
 	) (api.GetForwardtestWorkflowResults, error)
 	GetForwardtestBalance(
 		ctx context.Context,
 		params api.GetForwardtestBalanceWorkflowParams,
+		opts ...Option,
 	) (api.GetForwardtestBalanceWorkflowResults, error)
 	ListForwardtests(
 		ctx context.Context,
 		params api.ListForwardtestsWorkflowParams,
+		opts ...Option,
 	) (api.ListForwardtestsWorkflowResults, error)
 	CreateForwardtestOrder(
 		ctx context.Context,
 		params api.CreateForwardtestOrderWorkflowParams,
+		opts ...Option,
 	) (api.CreateForwardtestOrderWorkflowResults, error)
 	ListForwardtestAccounts(
 		ctx context.Context,
 		params api.ListForwardtestAccountsWorkflowParams,
+		opts ...Option,
 	) (api.ListForwardtestAccountsWorkflowResults, error)
 	RunForwardtest(
 		ctx context.Context,
 		params api.RunForwardtestWorkflowParams,
+		opts ...Option,
 	) (api.RunForwardtestWorkflowResults, error)
 	StopForwardtest(
 		ctx context.Context,
 		params api.StopForwardtestWorkflowParams,
+		opts ...Option,
 	) (api.StopForwardtestWorkflowResults, error)
+	DeleteForwardtest(
+		ctx context.Context,
+		params api.DeleteForwardtestWorkflowParams,
+		opts ...Option,
+	) (api.DeleteForwardtestWorkflowResults, error)
+	ArchiveForwardtest(
+		ctx context.Context,
+		params api.ArchiveForwardtestWorkflowParams,
+		opts ...Option,
+	) (api.ArchiveForwardtestWorkflowResults, error)
+	CloneForwardtest(
+		ctx context.Context,
+		params api.CloneForwardtestWorkflowParams,
+		opts ...Option,
+	) (api.CloneForwardtestWorkflowResults, error)
+	ResetForwardtest(
+		ctx context.Context,
+		params api.ResetForwardtestWorkflowParams,
+		opts ...Option,
+	) (api.ResetForwardtestWorkflowResults, error)
+	ListForwardtestSubscriptions(
+		ctx context.Context,
+		params api.ListForwardtestSubscriptionsWorkflowParams,
+		opts ...Option,
+	) (api.ListForwardtestSubscriptionsWorkflowResults, error)
+	UpdateForwardtestOrderBook(
+		ctx context.Context,
+		params api.UpdateForwardtestOrderBookWorkflowParams,
+		opts ...Option,
+	) (api.UpdateForwardtestOrderBookWorkflowResults, error)
+	ListForwardtestTimers(
+		ctx context.Context,
+		params api.ListForwardtestTimersWorkflowParams,
+		opts ...Option,
+	) (api.ListForwardtestTimersWorkflowResults, error)
+	ReplayForwardtest(
+		ctx context.Context,
+		params api.ReplayForwardtestWorkflowParams,
+		opts ...Option,
+	) (api.ReplayForwardtestWorkflowResults, error)
+	GetForwardtestDiagnostics(
+		ctx context.Context,
+		params api.GetForwardtestDiagnosticsWorkflowParams,
+		opts ...Option,
+	) (api.GetForwardtestDiagnosticsWorkflowResults, error)
+	ListForwardtestEvents(
+		ctx context.Context,
+		params api.ListForwardtestEventsWorkflowParams,
+		opts ...Option,
+	) (api.ListForwardtestEventsWorkflowResults, error)
+	SubscribeToPrice(
+		ctx context.Context,
+		params api.SubscribeToPriceWorkflowParams,
+		opts ...Option,
+	) (api.SubscribeToPriceWorkflowResults, error)
+	UnsubscribeFromPrice(
+		ctx context.Context,
+		params api.UnsubscribeFromPriceWorkflowParams,
+		opts ...Option,
+	) (api.UnsubscribeFromPriceWorkflowResults, error)
+	RegisterForwardtestTimer(
+		ctx context.Context,
+		params api.RegisterForwardtestTimerWorkflowParams,
+		opts ...Option,
+	) (api.RegisterForwardtestTimerWorkflowResults, error)
+	UnregisterForwardtestTimer(
+		ctx context.Context,
+		params api.UnregisterForwardtestTimerWorkflowParams,
+		opts ...Option,
+	) (api.UnregisterForwardtestTimerWorkflowResults, error)
+
+	// Async variants start the workflow and return a handle on it without
+	// waiting for its completion.
+	RunForwardtestAsync(
+		ctx context.Context,
+		params api.RunForwardtestWorkflowParams,
+		opts ...Option,
+	) (Handle[api.RunForwardtestWorkflowResults], error)
+	StopForwardtestAsync(
+		ctx context.Context,
+		params api.StopForwardtestWorkflowParams,
+		opts ...Option,
+	) (Handle[api.StopForwardtestWorkflowResults], error)
+	ReplayForwardtestAsync(
+		ctx context.Context,
+		params api.ReplayForwardtestWorkflowParams,
+		opts ...Option,
+	) (Handle[api.ReplayForwardtestWorkflowResults], error)
 }
 
 var _ RawClient = raw{}
@@ -58,167 +156,230 @@ func NewRaw(cl temporalclient.Client) RawClient {
 func (c raw) CreateForwardtest(
 	ctx context.Context,
 	params api.CreateForwardtestWorkflowParams,
+	opts ...Option,
 ) (api.CreateForwardtestWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.CreateForwardtestWorkflowName, params)
-	if err != nil {
-		return api.CreateForwardtestWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.CreateForwardtestWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.CreateForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.CreateForwardtestWorkflowName, params)
 }
 
 func (c raw) GetForwardtest(
 	ctx context.Context,
 	params api.GetForwardtestWorkflowParams,
+	opts ...Option,
 ) (api.GetForwardtestWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.GetForwardtestWorkflowName, params)
-	if err != nil {
-		return api.GetForwardtestWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.GetForwardtestWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.GetForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.GetForwardtestWorkflowName, params)
 }
 
 func (c raw) GetForwardtestBalance(
 	ctx context.Context,
 	params api.GetForwardtestBalanceWorkflowParams,
+	opts ...Option,
 ) (api.GetForwardtestBalanceWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.GetForwardtestBalanceWorkflowName, params)
-	if err != nil {
-		return api.GetForwardtestBalanceWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.GetForwardtestBalanceWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.GetForwardtestBalanceWorkflowResults](
+		ctx, c.temporal, opts, api.GetForwardtestBalanceWorkflowName, params)
 }
 
 func (c raw) ListForwardtests(
 	ctx context.Context,
 	params api.ListForwardtestsWorkflowParams,
+	opts ...Option,
 ) (api.ListForwardtestsWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.ListForwardtestsWorkflowName, params)
-	if err != nil {
-		return api.ListForwardtestsWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.ListForwardtestsWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.ListForwardtestsWorkflowResults](
+		ctx, c.temporal, opts, api.ListForwardtestsWorkflowName, params)
 }
 
 func (c raw) CreateForwardtestOrder(
 	ctx context.Context,
 	params api.CreateForwardtestOrderWorkflowParams,
+	opts ...Option,
 ) (api.CreateForwardtestOrderWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
+	// Submit an order with an ID only once, unless another key is set
+	if params.Order.ID != uuid.Nil {
+		key := fmt.Sprintf("%s-%s", params.ForwardtestID, params.Order.ID)
+		opts = append([]Option{WithIdempotencyKey(key)}, opts...)
 	}
 
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.CreateForwardtestOrderWorkflowName, params)
-	if err != nil {
-		return api.CreateForwardtestOrderWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.CreateForwardtestOrderWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.CreateForwardtestOrderWorkflowResults](
+		ctx, c.temporal, opts, api.CreateForwardtestOrderWorkflowName, params)
 }
 
 func (c raw) ListForwardtestAccounts(
 	ctx context.Context,
 	params api.ListForwardtestAccountsWorkflowParams,
+	opts ...Option,
 ) (api.ListForwardtestAccountsWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.ListForwardtestAccountsWorkflowName, params)
-	if err != nil {
-		return api.ListForwardtestAccountsWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.ListForwardtestAccountsWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.ListForwardtestAccountsWorkflowResults](
+		ctx, c.temporal, opts, api.ListForwardtestAccountsWorkflowName, params)
 }
 
 func (c raw) RunForwardtest(
 	ctx context.Context,
 	params api.RunForwardtestWorkflowParams,
+	opts ...Option,
 ) (api.RunForwardtestWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.RunForwardtestWorkflowName, params)
-	if err != nil {
-		return api.RunForwardtestWorkflowResults{}, err
-	}
-
-	// Get result and return
-	var res api.RunForwardtestWorkflowResults
-	err = exec.Get(ctx, &res)
-
-	return res, err
+	return execute[api.RunForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.RunForwardtestWorkflowName, params)
 }
 
 func (c raw) StopForwardtest(
 	ctx context.Context,
 	params api.StopForwardtestWorkflowParams,
+	opts ...Option,
 ) (api.StopForwardtestWorkflowResults, error) {
-	workflowOptions := temporalclient.StartWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
+	return execute[api.StopForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.StopForwardtestWorkflowName, params)
+}
 
-	// Execute workflow
-	exec, err := c.temporal.ExecuteWorkflow(ctx, workflowOptions, api.StopForwardtestWorkflowName, params)
-	if err != nil {
-		return api.StopForwardtestWorkflowResults{}, err
-	}
+func (c raw) DeleteForwardtest(
+	ctx context.Context,
+	params api.DeleteForwardtestWorkflowParams,
+	opts ...Option,
+) (api.DeleteForwardtestWorkflowResults, error) {
+	return execute[api.DeleteForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.DeleteForwardtestWorkflowName, params)
+}
+
+func (c raw) ArchiveForwardtest(
+	ctx context.Context,
+	params api.ArchiveForwardtestWorkflowParams,
+	opts ...Option,
+) (api.ArchiveForwardtestWorkflowResults, error) {
+	return execute[api.ArchiveForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.ArchiveForwardtestWorkflowName, params)
+}
+
+func (c raw) CloneForwardtest(
+	ctx context.Context,
+	params api.CloneForwardtestWorkflowParams,
+	opts ...Option,
+) (api.CloneForwardtestWorkflowResults, error) {
+	return execute[api.CloneForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.CloneForwardtestWorkflowName, params)
+}
+
+func (c raw) ResetForwardtest(
+	ctx context.Context,
+	params api.ResetForwardtestWorkflowParams,
+	opts ...Option,
+) (api.ResetForwardtestWorkflowResults, error) {
+	return execute[api.ResetForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.ResetForwardtestWorkflowName, params)
+}
+
+func (c raw) ListForwardtestSubscriptions(
+	ctx context.Context,
+	params api.ListForwardtestSubscriptionsWorkflowParams,
+	opts ...Option,
+) (api.ListForwardtestSubscriptionsWorkflowResults, error) {
+	return execute[api.ListForwardtestSubscriptionsWorkflowResults](
+		ctx, c.temporal, opts, api.ListForwardtestSubscriptionsWorkflowName, params)
+}
+
+func (c raw) UpdateForwardtestOrderBook(
+	ctx context.Context,
+	params api.UpdateForwardtestOrderBookWorkflowParams,
+	opts ...Option,
+) (api.UpdateForwardtestOrderBookWorkflowResults, error) {
+	return execute[api.UpdateForwardtestOrderBookWorkflowResults](
+		ctx, c.temporal, opts, api.UpdateForwardtestOrderBookWorkflowName, params)
+}
+
+func (c raw) ListForwardtestTimers(
+	ctx context.Context,
+	params api.ListForwardtestTimersWorkflowParams,
+	opts ...Option,
+) (api.ListForwardtestTimersWorkflowResults, error) {
+	return execute[api.ListForwardtestTimersWorkflowResults](
+		ctx, c.temporal, opts, api.ListForwardtestTimersWorkflowName, params)
+}
+
+func (c raw) ReplayForwardtest(
+	ctx context.Context,
+	params api.ReplayForwardtestWorkflowParams,
+	opts ...Option,
+) (api.ReplayForwardtestWorkflowResults, error) {
+	return execute[api.ReplayForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.ReplayForwardtestWorkflowName, params)
+}
 
-	// Get result and return
-	var res api.StopForwardtestWorkflowResults
-	err = exec.Get(ctx, &res)
+func (c raw) GetForwardtestDiagnostics(
+	ctx context.Context,
+	params api.GetForwardtestDiagnosticsWorkflowParams,
+	opts ...Option,
+) (api.GetForwardtestDiagnosticsWorkflowResults, error) {
+	return execute[api.GetForwardtestDiagnosticsWorkflowResults](
+		ctx, c.temporal, opts, api.GetForwardtestDiagnosticsWorkflowName, params)
+}
+
+func (c raw) ListForwardtestEvents(
+	ctx context.Context,
+	params api.ListForwardtestEventsWorkflowParams,
+	opts ...Option,
+) (api.ListForwardtestEventsWorkflowResults, error) {
+	return execute[api.ListForwardtestEventsWorkflowResults](
+		ctx, c.temporal, opts, api.ListForwardtestEventsWorkflowName, params)
+}
+
+func (c raw) SubscribeToPrice(
+	ctx context.Context,
+	params api.SubscribeToPriceWorkflowParams,
+	opts ...Option,
+) (api.SubscribeToPriceWorkflowResults, error) {
+	return execute[api.SubscribeToPriceWorkflowResults](
+		ctx, c.temporal, opts, api.SubscribeToPriceWorkflowName, params)
+}
+
+func (c raw) UnsubscribeFromPrice(
+	ctx context.Context,
+	params api.UnsubscribeFromPriceWorkflowParams,
+	opts ...Option,
+) (api.UnsubscribeFromPriceWorkflowResults, error) {
+	return execute[api.UnsubscribeFromPriceWorkflowResults](
+		ctx, c.temporal, opts, api.UnsubscribeFromPriceWorkflowName, params)
+}
+
+func (c raw) RegisterForwardtestTimer(
+	ctx context.Context,
+	params api.RegisterForwardtestTimerWorkflowParams,
+	opts ...Option,
+) (api.RegisterForwardtestTimerWorkflowResults, error) {
+	return execute[api.RegisterForwardtestTimerWorkflowResults](
+		ctx, c.temporal, opts, api.RegisterForwardtestTimerWorkflowName, params)
+}
+
+func (c raw) UnregisterForwardtestTimer(
+	ctx context.Context,
+	params api.UnregisterForwardtestTimerWorkflowParams,
+	opts ...Option,
+) (api.UnregisterForwardtestTimerWorkflowResults, error) {
+	return execute[api.UnregisterForwardtestTimerWorkflowResults](
+		ctx, c.temporal, opts, api.UnregisterForwardtestTimerWorkflowName, params)
+}
 
-	return res, err
+func (c raw) RunForwardtestAsync(
+	ctx context.Context,
+	params api.RunForwardtestWorkflowParams,
+	opts ...Option,
+) (Handle[api.RunForwardtestWorkflowResults], error) {
+	return start[api.RunForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.RunForwardtestWorkflowName, params)
+}
+
+func (c raw) StopForwardtestAsync(
+	ctx context.Context,
+	params api.StopForwardtestWorkflowParams,
+	opts ...Option,
+) (Handle[api.StopForwardtestWorkflowResults], error) {
+	return start[api.StopForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.StopForwardtestWorkflowName, params)
+}
+
+func (c raw) ReplayForwardtestAsync(
+	ctx context.Context,
+	params api.ReplayForwardtestWorkflowParams,
+	opts ...Option,
+) (Handle[api.ReplayForwardtestWorkflowResults], error) {
+	return start[api.ReplayForwardtestWorkflowResults](
+		ctx, c.temporal, opts, api.ReplayForwardtestWorkflowName, params)
 }
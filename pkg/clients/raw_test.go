@@ -0,0 +1,143 @@
+//go:build unit
+// +build unit
+
+package clients
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/runtime/order"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/serviceerror"
+	temporalclient "go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestRawSuite(t *testing.T) {
+	suite.Run(t, new(RawSuite))
+}
+
+type RawSuite struct {
+	suite.Suite
+	temporal *mocks.Client
+	run      *mocks.WorkflowRun
+}
+
+func (suite *RawSuite) SetupTest() {
+	suite.temporal = mocks.NewClient(suite.T())
+	suite.run = mocks.NewWorkflowRun(suite.T())
+}
+
+func (suite *RawSuite) TestOptions() {
+	forwardtestID := uuid.New()
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, temporalclient.StartWorkflowOptions{
+		ID:                       "get-balance",
+		TaskQueue:                api.WorkerTaskQueueName,
+		WorkflowExecutionTimeout: time.Minute,
+		RetryPolicy:              &temporal.RetryPolicy{MaximumAttempts: 3},
+	}, api.GetForwardtestBalanceWorkflowName, api.GetForwardtestBalanceWorkflowParams{
+		ForwardtestID: forwardtestID,
+	}).Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.GetForwardtestBalanceWorkflowResults).Balance = 1000
+	}).Return(nil).Once()
+
+	balance, err := New(suite.temporal).Forwardtest(forwardtestID).GetBalance(context.Background(),
+		WithWorkflowID("get-balance"),
+		WithTimeout(time.Minute),
+		WithRetryPolicy(temporal.RetryPolicy{MaximumAttempts: 3}))
+	suite.Require().NoError(err)
+	suite.Require().Equal(1000.0, balance)
+}
+
+func (suite *RawSuite) TestRunAsync() {
+	forwardtestID := uuid.New()
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, temporalclient.StartWorkflowOptions{
+		TaskQueue: api.WorkerTaskQueueName,
+	}, api.RunForwardtestWorkflowName, api.RunForwardtestWorkflowParams{
+		ForwardtestID: forwardtestID,
+	}).Return(suite.run, nil).Once()
+	suite.run.On("GetID").Return("run-forwardtest")
+	suite.run.On("GetRunID").Return("run")
+
+	// The run is not waited for
+	h, err := New(suite.temporal).Forwardtest(forwardtestID).RunAsync(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal("run-forwardtest", h.WorkflowID())
+	suite.Require().Equal("run", h.RunID())
+
+	// Cancel the run
+	suite.temporal.On("CancelWorkflow", mock.Anything, "run-forwardtest", "run").Return(nil).Once()
+	suite.Require().NoError(h.Cancel(context.Background()))
+
+	// Wait for the run
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(nil).Once()
+	_, err = h.Get(context.Background())
+	suite.Require().NoError(err)
+}
+
+func (suite *RawSuite) TestSubscribe() {
+	forwardtestID := uuid.New()
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.SubscribeToPriceWorkflowName, api.SubscribeToPriceWorkflowParams{
+			ForwardtestID: forwardtestID,
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+		}).Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(nil).Once()
+
+	// The forwardtest ID is set from the forwardtest
+	err := New(suite.temporal).Forwardtest(forwardtestID).Subscribe(context.Background(),
+		api.SubscribeToPriceWorkflowParams{
+			ForwardtestID: uuid.New(),
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+		})
+	suite.Require().NoError(err)
+}
+
+func (suite *RawSuite) TestIdempotencyKey() {
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, temporalclient.StartWorkflowOptions{
+		ID:                    api.CreateForwardtestWorkflowName + "-key",
+		TaskQueue:             api.WorkerTaskQueueName,
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE_FAILED_ONLY,
+	}, api.CreateForwardtestWorkflowName, api.CreateForwardtestWorkflowParams{}).
+		Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(nil).Once()
+
+	_, err := New(suite.temporal).NewForwardtest(context.Background(),
+		api.CreateForwardtestWorkflowParams{}, WithIdempotencyKey("key"))
+	suite.Require().NoError(err)
+}
+
+func (suite *RawSuite) TestCreateOrderAlreadyExecuted() {
+	forwardtestID, orderID := uuid.New(), uuid.New()
+	workflowID := fmt.Sprintf("%s-%s-%s", api.CreateForwardtestOrderWorkflowName, forwardtestID, orderID)
+
+	// The workflow ID is derived from the order ID and already used
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.MatchedBy(
+		func(opts temporalclient.StartWorkflowOptions) bool {
+			return opts.ID == workflowID
+		}), api.CreateForwardtestOrderWorkflowName, mock.Anything).
+		Return(nil, serviceerror.NewWorkflowExecutionAlreadyStarted("already started", "", "")).Once()
+
+	// The results of the previous workflow are returned
+	suite.temporal.On("GetWorkflow", mock.Anything, workflowID, "").Return(suite.run).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.CreateForwardtestOrderWorkflowResults).Order.ID = orderID
+	}).Return(nil).Once()
+
+	res, err := New(suite.temporal).Forwardtest(forwardtestID).CreateOrder(context.Background(), order.Order{
+		ID: orderID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(orderID, res.Order.ID)
+}
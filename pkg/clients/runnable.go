@@ -0,0 +1,55 @@
+package clients
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/runtime"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// OrdersRunnable is a runtime.Runnable whose OnNewPrices callback returns the
+// orders to execute right after it, instead of creating them with
+// WfClient.CreateForwardtestOrder. The orders are executed at the price of the
+// last delivered tick of their pair and linked to it.
+type OrdersRunnable interface {
+	Name() string
+	OnInit(ctx workflow.Context, params runtime.OnInitCallbackWorkflowParams) error
+	OnNewPrices(
+		ctx workflow.Context,
+		params runtime.OnNewPricesCallbackWorkflowParams,
+	) (api.OnNewPricesCallbackWorkflowResults, error)
+	OnExit(ctx workflow.Context, params runtime.OnExitCallbackWorkflowParams) error
+}
+
+// RegisterOrdersRunnable registers an OrdersRunnable to a worker and returns
+// the callbacks, named like the ones of runtime.RegisterRunnable.
+func RegisterOrdersRunnable(w worker.WorkflowRegistry, taskQueue string, r OrdersRunnable) runtime.Callbacks {
+	callbacks := runtime.Callbacks{
+		OnInitCallback: runtime.CallbackWorkflow{
+			Name:          fmt.Sprintf("%s-OnInit", r.Name()),
+			TaskQueueName: taskQueue,
+		},
+		OnNewPricesCallback: runtime.CallbackWorkflow{
+			Name:          fmt.Sprintf("%s-OnNewPrices", r.Name()),
+			TaskQueueName: taskQueue,
+		},
+		OnExitCallback: runtime.CallbackWorkflow{
+			Name:          fmt.Sprintf("%s-OnExit", r.Name()),
+			TaskQueueName: taskQueue,
+		},
+	}
+
+	w.RegisterWorkflowWithOptions(r.OnInit, workflow.RegisterOptions{
+		Name: callbacks.OnInitCallback.Name,
+	})
+	w.RegisterWorkflowWithOptions(r.OnNewPrices, workflow.RegisterOptions{
+		Name: callbacks.OnNewPricesCallback.Name,
+	})
+	w.RegisterWorkflowWithOptions(r.OnExit, workflow.RegisterOptions{
+		Name: callbacks.OnExitCallback.Name,
+	})
+
+	return callbacks
+}
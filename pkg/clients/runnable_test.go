@@ -0,0 +1,72 @@
+//go:build unit
+// +build unit
+
+package clients
+
+import (
+	"testing"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestOrdersRunnableSuite(t *testing.T) {
+	suite.Run(t, new(OrdersRunnableSuite))
+}
+
+type OrdersRunnableSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+}
+
+type ordersRunnable struct {
+	Orders []order.Order
+}
+
+func (r ordersRunnable) Name() string {
+	return "bot"
+}
+
+func (r ordersRunnable) OnInit(_ workflow.Context, _ runtime.OnInitCallbackWorkflowParams) error {
+	return nil
+}
+
+func (r ordersRunnable) OnNewPrices(
+	_ workflow.Context,
+	_ runtime.OnNewPricesCallbackWorkflowParams,
+) (api.OnNewPricesCallbackWorkflowResults, error) {
+	return api.OnNewPricesCallbackWorkflowResults{Orders: r.Orders}, nil
+}
+
+func (r ordersRunnable) OnExit(_ workflow.Context, _ runtime.OnExitCallbackWorkflowParams) error {
+	return nil
+}
+
+func (suite *OrdersRunnableSuite) TestRegisterOrdersRunnable() {
+	env := suite.NewTestWorkflowEnvironment()
+	r := ordersRunnable{
+		Orders: []order.Order{{ID: uuid.New(), Exchange: "exchange", Pair: "ETH-USDT", Quantity: 1}},
+	}
+
+	callbacks := RegisterOrdersRunnable(env, "task-queue", r)
+	suite.Require().NoError(callbacks.Validate())
+	suite.Require().Equal("bot-OnNewPrices", callbacks.OnNewPricesCallback.Name)
+	suite.Require().Equal("task-queue", callbacks.OnNewPricesCallback.TaskQueueName)
+
+	// The OnNewPrices callback returns the orders
+	env.ExecuteWorkflow(callbacks.OnNewPricesCallback.Name, runtime.OnNewPricesCallbackWorkflowParams{
+		Ticks: []tick.Tick{{Exchange: "exchange", Pair: "ETH-USDT", Price: 1}},
+	})
+	suite.Require().True(env.IsWorkflowCompleted())
+	suite.Require().NoError(env.GetWorkflowError())
+
+	var res api.OnNewPricesCallbackWorkflowResults
+	suite.Require().NoError(env.GetWorkflowResult(&res))
+	suite.Require().Equal(r.Orders, res.Orders)
+}
@@ -0,0 +1,80 @@
+package clients
+
+import (
+	"context"
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+)
+
+const (
+	// watchInterval is the interval between two polls of the event log when
+	// there is no new event.
+	watchInterval = time.Second
+	// watchPageSize is the maximum number of events read at each poll.
+	watchPageSize = 100
+)
+
+// Watch streams the events of the forwardtest after the given event ID, the
+// oldest first, until the context is cancelled. A zero ID streams the events
+// from the start: to resume after a disconnection, pass the ID of the last
+// event received.
+//
+// Errors while reading the event log are sent on the error channel, without
+// blocking if it is not read, and the reading is retried at the next poll.
+// Both channels are closed when the context is cancelled.
+func (ft Forwardtest) Watch(
+	ctx context.Context,
+	after int64,
+	opts ...Option,
+) (<-chan forwardtest.Event, <-chan error) {
+	return ft.watch(ctx, after, watchInterval, opts)
+}
+
+func (ft Forwardtest) watch(
+	ctx context.Context,
+	after int64,
+	interval time.Duration,
+	opts []Option,
+) (<-chan forwardtest.Event, <-chan error) {
+	events := make(chan forwardtest.Event)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(events)
+		defer close(errs)
+
+		for {
+			page, err := ft.ListEvents(ctx, after, watchPageSize, opts...)
+			if err != nil && ctx.Err() == nil {
+				select {
+				case errs <- err:
+				default:
+				}
+			}
+
+			// Send the events, moving the cursor forward
+			for _, e := range page {
+				select {
+				case events <- e:
+					after = e.ID
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Read the next page right away if this one was full
+			if len(page) == watchPageSize {
+				continue
+			}
+
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, errs
+}
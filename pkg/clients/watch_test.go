@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package clients
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/mocks"
+)
+
+func TestWatchSuite(t *testing.T) {
+	suite.Run(t, new(WatchSuite))
+}
+
+type WatchSuite struct {
+	suite.Suite
+	temporal *mocks.Client
+}
+
+func (suite *WatchSuite) SetupTest() {
+	suite.temporal = mocks.NewClient(suite.T())
+}
+
+// onListEvents mocks the listing of the events after the given cursor.
+func (suite *WatchSuite) onListEvents(forwardtestID uuid.UUID, after int64, events []forwardtest.Event, err error) {
+	run := mocks.NewWorkflowRun(suite.T())
+	run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.ListForwardtestEventsWorkflowResults).Events = events
+	}).Return(err)
+
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.ListForwardtestEventsWorkflowName, api.ListForwardtestEventsWorkflowParams{
+			ForwardtestID: forwardtestID,
+			After:         after,
+			Limit:         watchPageSize,
+		}).Return(run, nil)
+}
+
+func (suite *WatchSuite) TestWatch() {
+	forwardtestID := uuid.New()
+	events := []forwardtest.Event{
+		{ID: 3, ForwardtestID: forwardtestID, Type: forwardtest.EventTypeStatusChanged},
+		{ID: 5, ForwardtestID: forwardtestID, Type: forwardtest.EventTypeOrderFilled},
+	}
+
+	// Events are listed after the given cursor, then after the last one received
+	suite.onListEvents(forwardtestID, 2, events, nil)
+	suite.onListEvents(forwardtestID, 5, nil, errors.New("unavailable"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ft := New(suite.temporal).Forwardtest(forwardtestID)
+	eventsCh, errCh := ft.watch(ctx, 2, time.Millisecond, nil)
+
+	suite.Require().Equal(events[0], <-eventsCh)
+	suite.Require().Equal(events[1], <-eventsCh)
+	suite.Require().ErrorContains(<-errCh, "unavailable")
+
+	// Channels are closed on cancellation
+	cancel()
+	suite.Require().Eventually(func() bool {
+		_, eventsOpen := <-eventsCh
+		return !eventsOpen
+	}, time.Second, time.Millisecond)
+	suite.Require().Eventually(func() bool {
+		_, errsOpen := <-errCh
+		return !errsOpen
+	}, time.Second, time.Millisecond)
+}
@@ -4,16 +4,31 @@ import (
 	"time"
 
 	"github.com/cryptellation/forwardtests/api"
+	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/workflow"
 )
 
+// readTimeout is the default execution timeout of the workflows only
+// reading the forwardtests.
+const readTimeout = 10 * time.Second
+
 // WfClient is a client for the cryptellation forwardtests service from a workflow perspective.
+// Each call executes the corresponding workflow of the service as a child
+// workflow on the service task queue, with a 10 seconds timeout for reads.
+// The child workflow options set on the context take precedence over these
+// defaults.
 type WfClient interface {
-	// CreateForwardtestOrder creates a new order for a forwardtest.
-	CreateForwardtestOrder(
+	// CreateForwardtest creates a new forwardtest.
+	CreateForwardtest(
 		ctx workflow.Context,
-		params api.CreateForwardtestOrderWorkflowParams,
-	) (api.CreateForwardtestOrderWorkflowResults, error)
+		params api.CreateForwardtestWorkflowParams,
+	) (api.CreateForwardtestWorkflowResults, error)
+
+	// ListForwardtests lists the forwardtests.
+	ListForwardtests(
+		ctx workflow.Context,
+		params api.ListForwardtestsWorkflowParams,
+	) (api.ListForwardtestsWorkflowResults, error)
 
 	// GetForwardtest retrieves a forwardtest from the database by its ID.
 	GetForwardtest(
@@ -21,13 +36,125 @@ type WfClient interface {
 		params api.GetForwardtestWorkflowParams,
 	) (api.GetForwardtestWorkflowResults, error)
 
+	// RunForwardtest runs a forwardtest.
+	RunForwardtest(
+		ctx workflow.Context,
+		params api.RunForwardtestWorkflowParams,
+	) (api.RunForwardtestWorkflowResults, error)
+
+	// StopForwardtest stops a forwardtest. The stop is not cancelled when the calling
+	// workflow closes, so a bot can stop its own forwardtest.
+	StopForwardtest(
+		ctx workflow.Context,
+		params api.StopForwardtestWorkflowParams,
+	) (api.StopForwardtestWorkflowResults, error)
+
+	// DeleteForwardtest deletes a forwardtest.
+	DeleteForwardtest(
+		ctx workflow.Context,
+		params api.DeleteForwardtestWorkflowParams,
+	) (api.DeleteForwardtestWorkflowResults, error)
+
+	// ArchiveForwardtest archives or unarchives a forwardtest.
+	ArchiveForwardtest(
+		ctx workflow.Context,
+		params api.ArchiveForwardtestWorkflowParams,
+	) (api.ArchiveForwardtestWorkflowResults, error)
+
+	// CloneForwardtest creates a new forwardtest from an existing one.
+	CloneForwardtest(
+		ctx workflow.Context,
+		params api.CloneForwardtestWorkflowParams,
+	) (api.CloneForwardtestWorkflowResults, error)
+
+	// ResetForwardtest resets a forwardtest to its initial state.
+	ResetForwardtest(
+		ctx workflow.Context,
+		params api.ResetForwardtestWorkflowParams,
+	) (api.ResetForwardtestWorkflowResults, error)
+
+	// ReplayForwardtest replays the recorded ticks of a forwardtest on a new one.
+	ReplayForwardtest(
+		ctx workflow.Context,
+		params api.ReplayForwardtestWorkflowParams,
+	) (api.ReplayForwardtestWorkflowResults, error)
+
+	// CreateForwardtestOrder creates a new order for a forwardtest. An order
+	// with an ID is only executed once, a retry returning it as a duplicate.
+	CreateForwardtestOrder(
+		ctx workflow.Context,
+		params api.CreateForwardtestOrderWorkflowParams,
+	) (api.CreateForwardtestOrderWorkflowResults, error)
+
+	// ListForwardtestAccounts lists the accounts of a forwardtest.
+	ListForwardtestAccounts(
+		ctx workflow.Context,
+		params api.ListForwardtestAccountsWorkflowParams,
+	) (api.ListForwardtestAccountsWorkflowResults, error)
+
+	// GetForwardtestBalance gets the balance of a forwardtest.
+	GetForwardtestBalance(
+		ctx workflow.Context,
+		params api.GetForwardtestBalanceWorkflowParams,
+	) (api.GetForwardtestBalanceWorkflowResults, error)
+
 	// SubscribeToPrice subscribes to specific price updates for a forwardtest.
 	SubscribeToPrice(
 		ctx workflow.Context,
 		params api.SubscribeToPriceWorkflowParams,
 	) (api.SubscribeToPriceWorkflowResults, error)
+
+	// UnsubscribeFromPrice unsubscribes from specific price updates for a forwardtest.
+	UnsubscribeFromPrice(
+		ctx workflow.Context,
+		params api.UnsubscribeFromPriceWorkflowParams,
+	) (api.UnsubscribeFromPriceWorkflowResults, error)
+
+	// ListForwardtestSubscriptions lists the price subscriptions of a forwardtest.
+	ListForwardtestSubscriptions(
+		ctx workflow.Context,
+		params api.ListForwardtestSubscriptionsWorkflowParams,
+	) (api.ListForwardtestSubscriptionsWorkflowResults, error)
+
+	// UpdateForwardtestOrderBook updates the order book snapshot of a pair of a forwardtest.
+	UpdateForwardtestOrderBook(
+		ctx workflow.Context,
+		params api.UpdateForwardtestOrderBookWorkflowParams,
+	) (api.UpdateForwardtestOrderBookWorkflowResults, error)
+
+	// GetForwardtestDiagnostics gets the state of the price feeds of a forwardtest.
+	GetForwardtestDiagnostics(
+		ctx workflow.Context,
+		params api.GetForwardtestDiagnosticsWorkflowParams,
+	) (api.GetForwardtestDiagnosticsWorkflowResults, error)
+
+	// ListForwardtestEvents lists the events of a forwardtest after a cursor.
+	ListForwardtestEvents(
+		ctx workflow.Context,
+		params api.ListForwardtestEventsWorkflowParams,
+	) (api.ListForwardtestEventsWorkflowResults, error)
+
+	// RegisterForwardtestTimer registers a recurring timer on a forwardtest.
+	RegisterForwardtestTimer(
+		ctx workflow.Context,
+		params api.RegisterForwardtestTimerWorkflowParams,
+	) (api.RegisterForwardtestTimerWorkflowResults, error)
+
+	// UnregisterForwardtestTimer cancels a timer of a forwardtest.
+	UnregisterForwardtestTimer(
+		ctx workflow.Context,
+		params api.UnregisterForwardtestTimerWorkflowParams,
+	) (api.UnregisterForwardtestTimerWorkflowResults, error)
+
+	// ListForwardtestTimers lists the timers of a forwardtest.
+	ListForwardtestTimers(
+		ctx workflow.Context,
+		params api.ListForwardtestTimersWorkflowParams,
+	) (api.ListForwardtestTimersWorkflowResults, error)
 }
 
+var _ WfClient = wfClient{}
+
 type wfClient struct{}
 
 // NewWfClient creates a new workflow client.
@@ -37,17 +164,52 @@ func NewWfClient() WfClient {
 	return wfClient{}
 }
 
-// CreateForwardtestOrder creates a new order for a forwardtest.
-func (c wfClient) CreateForwardtestOrder(
+// withChildOptions returns the context to execute a workflow of the service as
+// a child workflow. The child options set by the caller on the context are
+// kept, the defaults only filling the unset ones: the service task queue and
+// the given timeout (0 means no timeout). The options inherited from the
+// calling workflow are considered unset.
+func withChildOptions(ctx workflow.Context, timeout time.Duration) workflow.Context {
+	info := workflow.GetInfo(ctx)
+	opts := workflow.GetChildWorkflowOptions(ctx)
+
+	if opts.TaskQueue == "" || opts.TaskQueue == info.TaskQueueName {
+		opts.TaskQueue = api.WorkerTaskQueueName
+	}
+	if opts.WorkflowExecutionTimeout == 0 || opts.WorkflowExecutionTimeout == info.WorkflowExecutionTimeout {
+		opts.WorkflowExecutionTimeout = timeout
+	}
+	if opts.WorkflowRunTimeout == info.WorkflowRunTimeout {
+		opts.WorkflowRunTimeout = 0
+	}
+	if opts.WorkflowTaskTimeout == info.WorkflowTaskTimeout {
+		opts.WorkflowTaskTimeout = 0
+	}
+
+	return workflow.WithChildOptions(ctx, opts)
+}
+
+// CreateForwardtest creates a new forwardtest.
+func (c wfClient) CreateForwardtest(
 	ctx workflow.Context,
-	params api.CreateForwardtestOrderWorkflowParams,
-) (api.CreateForwardtestOrderWorkflowResults, error) {
-	var res api.CreateForwardtestOrderWorkflowResults
-	err := workflow.ExecuteActivity(
-		ctx,
-		api.CreateForwardtestOrderWorkflowName,
-		params,
-	).Get(ctx, &res)
+	params api.CreateForwardtestWorkflowParams,
+) (api.CreateForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.CreateForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.CreateForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ListForwardtests lists the forwardtests.
+func (c wfClient) ListForwardtests(
+	ctx workflow.Context,
+	params api.ListForwardtestsWorkflowParams,
+) (api.ListForwardtestsWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.ListForwardtestsWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ListForwardtestsWorkflowName, params).Get(ctx, &res)
 	return res, err
 }
 
@@ -56,37 +218,244 @@ func (c wfClient) GetForwardtest(
 	ctx workflow.Context,
 	params api.GetForwardtestWorkflowParams,
 ) (api.GetForwardtestWorkflowResults, error) {
-	// Set child workflow options with timeout
-	childWorkflowOptions := workflow.ChildWorkflowOptions{
-		TaskQueue:                api.WorkerTaskQueueName,
-		WorkflowExecutionTimeout: 10 * time.Second,
-	}
-	ctx = workflow.WithChildOptions(ctx, childWorkflowOptions)
+	ctx = withChildOptions(ctx, readTimeout)
 
-	// Execute the GetForwardtestWorkflow as a child workflow
 	var res api.GetForwardtestWorkflowResults
 	err := workflow.ExecuteChildWorkflow(ctx, api.GetForwardtestWorkflowName, params).Get(ctx, &res)
 	return res, err
 }
 
-// SubscribeToPrice subscribes to price updates for a forwardtest.
+// RunForwardtest runs a forwardtest.
+func (c wfClient) RunForwardtest(
+	ctx workflow.Context,
+	params api.RunForwardtestWorkflowParams,
+) (api.RunForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.RunForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.RunForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// StopForwardtest stops a forwardtest. The stop is not cancelled when the calling
+// workflow closes, so a bot can stop its own forwardtest.
+func (c wfClient) StopForwardtest(
+	ctx workflow.Context,
+	params api.StopForwardtestWorkflowParams,
+) (api.StopForwardtestWorkflowResults, error) {
+	// Keep stopping the forwardtest if the caller is closed by the stop,
+	// unless the caller has set another policy
+	ctx = withChildOptions(ctx, 0)
+	if opts := workflow.GetChildWorkflowOptions(ctx); opts.ParentClosePolicy == enums.PARENT_CLOSE_POLICY_UNSPECIFIED {
+		opts.ParentClosePolicy = enums.PARENT_CLOSE_POLICY_ABANDON
+		ctx = workflow.WithChildOptions(ctx, opts)
+	}
+
+	var res api.StopForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.StopForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// DeleteForwardtest deletes a forwardtest.
+func (c wfClient) DeleteForwardtest(
+	ctx workflow.Context,
+	params api.DeleteForwardtestWorkflowParams,
+) (api.DeleteForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.DeleteForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.DeleteForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ArchiveForwardtest archives or unarchives a forwardtest.
+func (c wfClient) ArchiveForwardtest(
+	ctx workflow.Context,
+	params api.ArchiveForwardtestWorkflowParams,
+) (api.ArchiveForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.ArchiveForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ArchiveForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// CloneForwardtest creates a new forwardtest from an existing one.
+func (c wfClient) CloneForwardtest(
+	ctx workflow.Context,
+	params api.CloneForwardtestWorkflowParams,
+) (api.CloneForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.CloneForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.CloneForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ResetForwardtest resets a forwardtest to its initial state.
+func (c wfClient) ResetForwardtest(
+	ctx workflow.Context,
+	params api.ResetForwardtestWorkflowParams,
+) (api.ResetForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.ResetForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ResetForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ReplayForwardtest replays the recorded ticks of a forwardtest on a new one.
+func (c wfClient) ReplayForwardtest(
+	ctx workflow.Context,
+	params api.ReplayForwardtestWorkflowParams,
+) (api.ReplayForwardtestWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.ReplayForwardtestWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ReplayForwardtestWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// CreateForwardtestOrder creates a new order for a forwardtest.
+func (c wfClient) CreateForwardtestOrder(
+	ctx workflow.Context,
+	params api.CreateForwardtestOrderWorkflowParams,
+) (api.CreateForwardtestOrderWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.CreateForwardtestOrderWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.CreateForwardtestOrderWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ListForwardtestAccounts lists the accounts of a forwardtest.
+func (c wfClient) ListForwardtestAccounts(
+	ctx workflow.Context,
+	params api.ListForwardtestAccountsWorkflowParams,
+) (api.ListForwardtestAccountsWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.ListForwardtestAccountsWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ListForwardtestAccountsWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// GetForwardtestBalance gets the balance of a forwardtest.
+func (c wfClient) GetForwardtestBalance(
+	ctx workflow.Context,
+	params api.GetForwardtestBalanceWorkflowParams,
+) (api.GetForwardtestBalanceWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.GetForwardtestBalanceWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.GetForwardtestBalanceWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// SubscribeToPrice subscribes to specific price updates for a forwardtest.
 func (c wfClient) SubscribeToPrice(
 	ctx workflow.Context,
 	params api.SubscribeToPriceWorkflowParams,
 ) (api.SubscribeToPriceWorkflowResults, error) {
-	// Set child workflow options
-	childWorkflowOptions := workflow.ChildWorkflowOptions{
-		TaskQueue: api.WorkerTaskQueueName,
-	}
-	ctx = workflow.WithChildOptions(ctx, childWorkflowOptions)
+	ctx = withChildOptions(ctx, 0)
 
-	// Execute the SubscribeToPriceWorkflow as a child workflow
 	var res api.SubscribeToPriceWorkflowResults
 	err := workflow.ExecuteChildWorkflow(ctx, api.SubscribeToPriceWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
 
-	if err != nil {
-		return api.SubscribeToPriceWorkflowResults{}, err
-	}
+// UnsubscribeFromPrice unsubscribes from specific price updates for a forwardtest.
+func (c wfClient) UnsubscribeFromPrice(
+	ctx workflow.Context,
+	params api.UnsubscribeFromPriceWorkflowParams,
+) (api.UnsubscribeFromPriceWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.UnsubscribeFromPriceWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.UnsubscribeFromPriceWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ListForwardtestSubscriptions lists the price subscriptions of a forwardtest.
+func (c wfClient) ListForwardtestSubscriptions(
+	ctx workflow.Context,
+	params api.ListForwardtestSubscriptionsWorkflowParams,
+) (api.ListForwardtestSubscriptionsWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.ListForwardtestSubscriptionsWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ListForwardtestSubscriptionsWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// UpdateForwardtestOrderBook updates the order book snapshot of a pair of a forwardtest.
+func (c wfClient) UpdateForwardtestOrderBook(
+	ctx workflow.Context,
+	params api.UpdateForwardtestOrderBookWorkflowParams,
+) (api.UpdateForwardtestOrderBookWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.UpdateForwardtestOrderBookWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.UpdateForwardtestOrderBookWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// GetForwardtestDiagnostics gets the state of the price feeds of a forwardtest.
+func (c wfClient) GetForwardtestDiagnostics(
+	ctx workflow.Context,
+	params api.GetForwardtestDiagnosticsWorkflowParams,
+) (api.GetForwardtestDiagnosticsWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.GetForwardtestDiagnosticsWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.GetForwardtestDiagnosticsWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// ListForwardtestEvents lists the events of a forwardtest after a cursor.
+func (c wfClient) ListForwardtestEvents(
+	ctx workflow.Context,
+	params api.ListForwardtestEventsWorkflowParams,
+) (api.ListForwardtestEventsWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.ListForwardtestEventsWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ListForwardtestEventsWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// RegisterForwardtestTimer registers a recurring timer on a forwardtest.
+func (c wfClient) RegisterForwardtestTimer(
+	ctx workflow.Context,
+	params api.RegisterForwardtestTimerWorkflowParams,
+) (api.RegisterForwardtestTimerWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.RegisterForwardtestTimerWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.RegisterForwardtestTimerWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
+
+// UnregisterForwardtestTimer cancels a timer of a forwardtest.
+func (c wfClient) UnregisterForwardtestTimer(
+	ctx workflow.Context,
+	params api.UnregisterForwardtestTimerWorkflowParams,
+) (api.UnregisterForwardtestTimerWorkflowResults, error) {
+	ctx = withChildOptions(ctx, 0)
+
+	var res api.UnregisterForwardtestTimerWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.UnregisterForwardtestTimerWorkflowName, params).Get(ctx, &res)
+	return res, err
+}
 
-	return res, nil
+// ListForwardtestTimers lists the timers of a forwardtest.
+func (c wfClient) ListForwardtestTimers(
+	ctx workflow.Context,
+	params api.ListForwardtestTimersWorkflowParams,
+) (api.ListForwardtestTimersWorkflowResults, error) {
+	ctx = withChildOptions(ctx, readTimeout)
+
+	var res api.ListForwardtestTimersWorkflowResults
+	err := workflow.ExecuteChildWorkflow(ctx, api.ListForwardtestTimersWorkflowName, params).Get(ctx, &res)
+	return res, err
 }
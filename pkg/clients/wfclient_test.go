@@ -0,0 +1,166 @@
+//go:build unit
+// +build unit
+
+package clients
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+func TestWfClientSuite(t *testing.T) {
+	suite.Run(t, new(WfClientSuite))
+}
+
+type WfClientSuite struct {
+	suite.Suite
+	testsuite.WorkflowTestSuite
+
+	env    *testsuite.TestWorkflowEnvironment
+	client WfClient
+}
+
+func (suite *WfClientSuite) SetupTest() {
+	suite.env = suite.NewTestWorkflowEnvironment()
+	suite.client = NewWfClient()
+}
+
+func (suite *WfClientSuite) AfterTest(_, _ string) {
+	suite.env.AssertExpectations(suite.T())
+}
+
+func (suite *WfClientSuite) TestCreateForwardtestOrder() {
+	forwardtestID := uuid.New()
+	o := order.Order{ID: uuid.New(), Exchange: "exchange", Pair: "ETH-USDT", Quantity: 1}
+
+	var taskQueue string
+	var received api.CreateForwardtestOrderWorkflowParams
+	suite.env.RegisterWorkflowWithOptions(
+		func(ctx workflow.Context, params api.CreateForwardtestOrderWorkflowParams) (
+			api.CreateForwardtestOrderWorkflowResults, error) {
+			taskQueue, received = workflow.GetInfo(ctx).TaskQueueName, params
+			return api.CreateForwardtestOrderWorkflowResults{}, nil
+		}, workflow.RegisterOptions{Name: api.CreateForwardtestOrderWorkflowName})
+
+	suite.env.ExecuteWorkflow(func(ctx workflow.Context) error {
+		_, err := suite.client.CreateForwardtestOrder(ctx, api.CreateForwardtestOrderWorkflowParams{
+			ForwardtestID: forwardtestID,
+			Order:         o,
+		})
+		return err
+	})
+	suite.Require().True(suite.env.IsWorkflowCompleted())
+	suite.Require().NoError(suite.env.GetWorkflowError())
+
+	// The order is created by a child workflow on the service task queue
+	suite.Require().Equal(api.WorkerTaskQueueName, taskQueue)
+	suite.Require().Equal(forwardtestID, received.ForwardtestID)
+	suite.Require().Equal(o, received.Order)
+}
+
+func (suite *WfClientSuite) TestGetForwardtestBalance() {
+	var timeout time.Duration
+	suite.env.RegisterWorkflowWithOptions(
+		func(ctx workflow.Context, _ api.GetForwardtestBalanceWorkflowParams) (
+			api.GetForwardtestBalanceWorkflowResults, error) {
+			timeout = workflow.GetInfo(ctx).WorkflowExecutionTimeout
+			return api.GetForwardtestBalanceWorkflowResults{Balance: 1000}, nil
+		}, workflow.RegisterOptions{Name: api.GetForwardtestBalanceWorkflowName})
+
+	suite.env.ExecuteWorkflow(func(ctx workflow.Context) (float64, error) {
+		res, err := suite.client.GetForwardtestBalance(ctx, api.GetForwardtestBalanceWorkflowParams{
+			ForwardtestID: uuid.New(),
+		})
+		return res.Balance, err
+	})
+	suite.Require().NoError(suite.env.GetWorkflowError())
+
+	var balance float64
+	suite.Require().NoError(suite.env.GetWorkflowResult(&balance))
+	suite.Require().Equal(1000.0, balance)
+
+	// Reads have a timeout
+	suite.Require().Equal(readTimeout, timeout)
+}
+
+func (suite *WfClientSuite) TestCallerChildOptions() {
+	var info workflow.Info
+	suite.env.RegisterWorkflowWithOptions(
+		func(ctx workflow.Context, _ api.GetForwardtestBalanceWorkflowParams) (
+			api.GetForwardtestBalanceWorkflowResults, error) {
+			info = *workflow.GetInfo(ctx)
+			return api.GetForwardtestBalanceWorkflowResults{}, nil
+		}, workflow.RegisterOptions{Name: api.GetForwardtestBalanceWorkflowName})
+
+	suite.env.ExecuteWorkflow(func(ctx workflow.Context) error {
+		ctx = workflow.WithChildOptions(ctx, workflow.ChildWorkflowOptions{
+			WorkflowID:               "custom-id",
+			WorkflowExecutionTimeout: time.Minute,
+		})
+		_, err := suite.client.GetForwardtestBalance(ctx, api.GetForwardtestBalanceWorkflowParams{
+			ForwardtestID: uuid.New(),
+		})
+		return err
+	})
+	suite.Require().NoError(suite.env.GetWorkflowError())
+
+	// The options set by the caller are kept, the unset ones get the defaults
+	suite.Require().Equal("custom-id", info.WorkflowExecution.ID)
+	suite.Require().Equal(time.Minute, info.WorkflowExecutionTimeout)
+	suite.Require().Equal(api.WorkerTaskQueueName, info.TaskQueueName)
+}
+
+func (suite *WfClientSuite) TestListForwardtestAccounts() {
+	suite.env.RegisterWorkflowWithOptions(
+		func(_ workflow.Context, _ api.ListForwardtestAccountsWorkflowParams) (
+			api.ListForwardtestAccountsWorkflowResults, error) {
+			return api.ListForwardtestAccountsWorkflowResults{}, nil
+		}, workflow.RegisterOptions{Name: api.ListForwardtestAccountsWorkflowName})
+	suite.env.OnWorkflow(api.ListForwardtestAccountsWorkflowName, mock.Anything, mock.Anything).
+		Return(api.ListForwardtestAccountsWorkflowResults{
+			Accounts: map[string]account.Account{
+				"exchange": {Balances: map[string]float64{"USDT": 1000}},
+			},
+		}, nil)
+
+	suite.env.ExecuteWorkflow(func(ctx workflow.Context) (float64, error) {
+		res, err := suite.client.ListForwardtestAccounts(ctx, api.ListForwardtestAccountsWorkflowParams{
+			ForwardtestID: uuid.New(),
+		})
+		return res.Accounts["exchange"].Balances["USDT"], err
+	})
+	suite.Require().NoError(suite.env.GetWorkflowError())
+
+	var usdt float64
+	suite.Require().NoError(suite.env.GetWorkflowResult(&usdt))
+	suite.Require().Equal(1000.0, usdt)
+}
+
+func (suite *WfClientSuite) TestStopForwardtestError() {
+	suite.env.RegisterWorkflowWithOptions(
+		func(_ workflow.Context, _ api.StopForwardtestWorkflowParams) (api.StopForwardtestWorkflowResults, error) {
+			return api.StopForwardtestWorkflowResults{}, nil
+		}, workflow.RegisterOptions{Name: api.StopForwardtestWorkflowName})
+	suite.env.OnWorkflow(api.StopForwardtestWorkflowName, mock.Anything, mock.Anything).
+		Return(api.StopForwardtestWorkflowResults{}, errors.New("stop failed"))
+
+	// Errors of the service are returned
+	suite.env.ExecuteWorkflow(func(ctx workflow.Context) error {
+		_, err := suite.client.StopForwardtest(ctx, api.StopForwardtestWorkflowParams{
+			ForwardtestID: uuid.New(),
+		})
+		return err
+	})
+	suite.Require().Error(suite.env.GetWorkflowError())
+	suite.Require().ErrorContains(suite.env.GetWorkflowError(), "stop failed")
+}
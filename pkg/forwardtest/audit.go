@@ -0,0 +1,23 @@
+package forwardtest
+
+import "time"
+
+// AuditAction is an action that changed a forwardtest outside of its normal run.
+type AuditAction string
+
+const (
+	// AuditActionReset is the action of resetting a forwardtest to its initial state.
+	AuditActionReset AuditAction = "reset"
+)
+
+// String returns the string representation of the audit action.
+func (a AuditAction) String() string {
+	return string(a)
+}
+
+// AuditEntry is an entry of the forwardtest audit log.
+type AuditEntry struct {
+	Time    time.Time
+	Action  AuditAction
+	Details string
+}
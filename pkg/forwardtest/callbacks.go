@@ -0,0 +1,77 @@
+package forwardtest
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/runtime"
+)
+
+// OptionalCallbacks are the callbacks that a forwardtest can define in
+// addition to the runtime callbacks. Nil callbacks are not executed.
+type OptionalCallbacks struct {
+	// OnFeedStaleCallback is executed when the price feed of a subscribed
+	// pair becomes stale.
+	OnFeedStaleCallback *runtime.CallbackWorkflow
+	// OnOrderUpdateCallback is executed when an order of the forwardtest is
+	// filled or rejected.
+	OnOrderUpdateCallback *runtime.CallbackWorkflow
+	// OnErrorCallback is executed when a callback of the forwardtest has
+	// failed, after its retries.
+	OnErrorCallback *runtime.CallbackWorkflow
+}
+
+// Validate validates the optional callbacks that are set.
+func (oc OptionalCallbacks) Validate() error {
+	if oc.OnFeedStaleCallback != nil {
+		if err := oc.OnFeedStaleCallback.Validate(); err != nil {
+			return fmt.Errorf("on feed stale callback: %w", err)
+		}
+	}
+
+	if oc.OnOrderUpdateCallback != nil {
+		if err := oc.OnOrderUpdateCallback.Validate(); err != nil {
+			return fmt.Errorf("on order update callback: %w", err)
+		}
+	}
+
+	if oc.OnErrorCallback != nil {
+		if err := oc.OnErrorCallback.Validate(); err != nil {
+			return fmt.Errorf("on error callback: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// NamedCallback is a callback of a forwardtest with the name of its field,
+// like "OnInitCallback".
+type NamedCallback struct {
+	Name     string
+	Callback runtime.CallbackWorkflow
+}
+
+// ListCallbacks returns the runtime callbacks and the optional callbacks that
+// are set, with their names.
+func ListCallbacks(callbacks runtime.Callbacks, optional OptionalCallbacks) []NamedCallback {
+	list := []NamedCallback{
+		{Name: "OnInitCallback", Callback: callbacks.OnInitCallback},
+		{Name: "OnNewPricesCallback", Callback: callbacks.OnNewPricesCallback},
+		{Name: "OnExitCallback", Callback: callbacks.OnExitCallback},
+	}
+
+	optionals := []struct {
+		name     string
+		callback *runtime.CallbackWorkflow
+	}{
+		{name: "OnFeedStaleCallback", callback: optional.OnFeedStaleCallback},
+		{name: "OnOrderUpdateCallback", callback: optional.OnOrderUpdateCallback},
+		{name: "OnErrorCallback", callback: optional.OnErrorCallback},
+	}
+	for _, o := range optionals {
+		if o.callback != nil {
+			list = append(list, NamedCallback{Name: o.name, Callback: *o.callback})
+		}
+	}
+
+	return list
+}
@@ -0,0 +1,37 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+
+	"github.com/cryptellation/runtime"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCallbacksSuite(t *testing.T) {
+	suite.Run(t, new(CallbacksSuite))
+}
+
+type CallbacksSuite struct {
+	suite.Suite
+}
+
+func (suite *CallbacksSuite) TestListCallbacks() {
+	callbacks := runtime.Callbacks{
+		OnInitCallback:      runtime.CallbackWorkflow{Name: "init", TaskQueueName: "queue"},
+		OnNewPricesCallback: runtime.CallbackWorkflow{Name: "prices", TaskQueueName: "queue"},
+		OnExitCallback:      runtime.CallbackWorkflow{Name: "exit", TaskQueueName: "queue"},
+	}
+	onError := runtime.CallbackWorkflow{Name: "error", TaskQueueName: "errors"}
+
+	// Only the optional callbacks that are set are listed
+	list := ListCallbacks(callbacks, OptionalCallbacks{OnErrorCallback: &onError})
+	suite.Require().Equal([]NamedCallback{
+		{Name: "OnInitCallback", Callback: callbacks.OnInitCallback},
+		{Name: "OnNewPricesCallback", Callback: callbacks.OnNewPricesCallback},
+		{Name: "OnExitCallback", Callback: callbacks.OnExitCallback},
+		{Name: "OnErrorCallback", Callback: onError},
+	}, list)
+}
@@ -0,0 +1,51 @@
+package forwardtest
+
+import (
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/candlestick"
+	"github.com/cryptellation/candlesticks/pkg/period"
+	"github.com/cryptellation/ticks/pkg/tick"
+)
+
+// CandlestickBuilder builds the candlesticks of a period from ticks received
+// in chronological order, to deliver closed candlesticks without the
+// candlesticks service.
+type CandlestickBuilder struct {
+	Period period.Symbol
+	// Current is the candlestick being built, nil before the first tick.
+	Current *candlestick.Candlestick
+}
+
+// Add adds a tick to the current candlestick. If the tick opens a new
+// candlestick, the previous one is closed and returned with true. Ticks older
+// than the current candlestick are ignored.
+func (b *CandlestickBuilder) Add(t tick.Tick) (candlestick.Candlestick, bool) {
+	openTime := b.Period.RoundTime(t.Time)
+	switch {
+	case b.Current == nil:
+		b.Current = newTickCandlestick(openTime, t.Price)
+		return candlestick.Candlestick{}, false
+	case openTime.Before(b.Current.Time):
+		return candlestick.Candlestick{}, false
+	case openTime.After(b.Current.Time):
+		closed := *b.Current
+		b.Current = newTickCandlestick(openTime, t.Price)
+		return closed, true
+	}
+
+	b.Current.High = max(b.Current.High, t.Price)
+	b.Current.Low = min(b.Current.Low, t.Price)
+	b.Current.Close = t.Price
+	return candlestick.Candlestick{}, false
+}
+
+func newTickCandlestick(openTime time.Time, price float64) *candlestick.Candlestick {
+	return &candlestick.Candlestick{
+		Time:  openTime,
+		Open:  price,
+		High:  price,
+		Low:   price,
+		Close: price,
+	}
+}
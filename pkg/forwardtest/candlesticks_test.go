@@ -0,0 +1,48 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/period"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestCandlestickBuilderSuite(t *testing.T) {
+	suite.Run(t, new(CandlestickBuilderSuite))
+}
+
+type CandlestickBuilderSuite struct {
+	suite.Suite
+}
+
+func (suite *CandlestickBuilderSuite) TestAdd() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := CandlestickBuilder{Period: period.M1}
+
+	// Ticks of the same minute are gathered
+	for i, price := range []float64{100, 110, 90, 105} {
+		_, closed := b.Add(tick.Tick{Time: start.Add(time.Duration(i) * time.Second), Price: price})
+		suite.Require().False(closed)
+	}
+
+	// Older ticks are ignored
+	_, closed := b.Add(tick.Tick{Time: start.Add(-time.Second), Price: 1})
+	suite.Require().False(closed)
+
+	// A tick of a later minute closes the candlestick
+	cs, closed := b.Add(tick.Tick{Time: start.Add(2 * time.Minute), Price: 120})
+	suite.Require().True(closed)
+	suite.Require().WithinDuration(start, cs.Time, 0)
+	suite.Require().Equal(100.0, cs.Open)
+	suite.Require().Equal(110.0, cs.High)
+	suite.Require().Equal(90.0, cs.Low)
+	suite.Require().Equal(105.0, cs.Close)
+
+	suite.Require().WithinDuration(start.Add(2*time.Minute), b.Current.Time, 0)
+	suite.Require().Equal(120.0, b.Current.Open)
+}
@@ -0,0 +1,235 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+)
+
+// ErrInvalidDeliveryPolicy is returned when the delivery policy is invalid.
+var ErrInvalidDeliveryPolicy = errors.New("invalid delivery policy")
+
+// DefaultQueueSize is the default maximum number of deliveries waiting for
+// the callback with the serial concurrency policy.
+const DefaultQueueSize = 100
+
+// ConcurrencyPolicy defines what happens to new ticks while the
+// OnNewPricesCallback is still running.
+type ConcurrencyPolicy string
+
+const (
+	// ConcurrencyPolicyConcurrent executes callbacks without waiting for the
+	// previous ones. This is the default.
+	ConcurrencyPolicyConcurrent ConcurrencyPolicy = "concurrent"
+	// ConcurrencyPolicySerial executes callbacks one after the other, queuing
+	// the ticks in a bounded queue. The oldest ticks are dropped when full.
+	ConcurrencyPolicySerial ConcurrencyPolicy = "serial"
+	// ConcurrencyPolicyDrop drops the ticks received while a callback is running.
+	ConcurrencyPolicyDrop ConcurrencyPolicy = "drop"
+	// ConcurrencyPolicyCoalesce keeps only the latest tick of each pair
+	// received while a callback is running, and delivers them afterwards.
+	ConcurrencyPolicyCoalesce ConcurrencyPolicy = "coalesce"
+)
+
+// String returns the string representation of the concurrency policy.
+func (cp ConcurrencyPolicy) String() string {
+	return string(cp)
+}
+
+// Validate validates the concurrency policy. The empty policy is valid and
+// means concurrent.
+func (cp ConcurrencyPolicy) Validate() error {
+	switch cp {
+	case "", ConcurrencyPolicyConcurrent, ConcurrencyPolicySerial,
+		ConcurrencyPolicyDrop, ConcurrencyPolicyCoalesce:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown concurrency policy %q", ErrInvalidDeliveryPolicy, cp)
+	}
+}
+
+// IsConcurrent returns true if callbacks are executed without waiting for the
+// previous ones.
+func (cp ConcurrencyPolicy) IsConcurrent() bool {
+	return cp == "" || cp == ConcurrencyPolicyConcurrent
+}
+
+// DeliveryPolicy defines how ticks are delivered to the OnNewPricesCallback.
+// The zero value delivers each tick in its own callback as soon as it is
+// received, without waiting for the previous callbacks.
+type DeliveryPolicy struct {
+	// Window is the duration during which ticks are gathered before being
+	// delivered in a single callback, starting from the first gathered tick.
+	Window time.Duration
+	// LatestOnly only delivers the latest tick of each exchange and pair
+	// gathered during the window or the min interval, which must be set.
+	// Use ConcurrencyPolicyCoalesce to keep the latest ticks received while
+	// a callback is running.
+	LatestOnly bool
+	// MinInterval is the minimum duration between two deliveries.
+	MinInterval time.Duration
+	// Concurrency is the policy applied to ticks received while a callback
+	// is still running.
+	Concurrency ConcurrencyPolicy
+	// QueueSize is the maximum number of deliveries waiting for the callback
+	// with the serial policy. If zero, DefaultQueueSize is used.
+	QueueSize int
+}
+
+// Validate validates the delivery policy.
+func (p DeliveryPolicy) Validate() error {
+	if p.Window < 0 {
+		return fmt.Errorf("%w: negative window", ErrInvalidDeliveryPolicy)
+	}
+
+	if p.MinInterval < 0 {
+		return fmt.Errorf("%w: negative min interval", ErrInvalidDeliveryPolicy)
+	}
+
+	if p.QueueSize < 0 {
+		return fmt.Errorf("%w: negative queue size", ErrInvalidDeliveryPolicy)
+	}
+
+	if p.LatestOnly && p.Window == 0 && p.MinInterval == 0 {
+		return fmt.Errorf("%w: latest only requires a window or a min interval", ErrInvalidDeliveryPolicy)
+	}
+
+	return p.Concurrency.Validate()
+}
+
+// IsImmediate returns true if each tick is delivered as soon as it is
+// received, without waiting for the previous callbacks.
+func (p DeliveryPolicy) IsImmediate() bool {
+	return p.Window == 0 && p.MinInterval == 0 && p.Concurrency.IsConcurrent()
+}
+
+// TickBuffer gathers ticks until they should be delivered, according to a
+// delivery policy. Times are the times at which ticks are received, not the
+// times of the ticks themselves.
+type TickBuffer struct {
+	Policy       DeliveryPolicy
+	Ticks        []tick.Tick
+	OpenedAt     time.Time
+	LastDelivery time.Time
+}
+
+// Add adds a tick received at the given time to the buffer.
+func (b *TickBuffer) Add(t tick.Tick, now time.Time) {
+	if len(b.Ticks) == 0 {
+		b.OpenedAt = now
+	}
+
+	if b.Policy.LatestOnly {
+		for i, bt := range b.Ticks {
+			if bt.Exchange != t.Exchange || bt.Pair != t.Pair {
+				continue
+			}
+
+			// Ignore ticks older than the buffered one
+			if !t.Time.Before(bt.Time) {
+				b.Ticks[i] = t
+			}
+			return
+		}
+	}
+
+	b.Ticks = append(b.Ticks, t)
+}
+
+// DueAt returns the time at which the buffered ticks should be delivered.
+// It returns false if the buffer is empty.
+func (b TickBuffer) DueAt() (time.Time, bool) {
+	if len(b.Ticks) == 0 {
+		return time.Time{}, false
+	}
+
+	due := b.OpenedAt.Add(b.Policy.Window)
+	if !b.LastDelivery.IsZero() {
+		if next := b.LastDelivery.Add(b.Policy.MinInterval); next.After(due) {
+			due = next
+		}
+	}
+
+	return due, true
+}
+
+// Flush returns the buffered ticks sorted by time and empties the buffer, if
+// they are due at the given time. Otherwise, it returns nil.
+func (b *TickBuffer) Flush(now time.Time) []tick.Tick {
+	due, ok := b.DueAt()
+	if !ok || now.Before(due) {
+		return nil
+	}
+
+	ticks := b.Ticks
+	slices.SortStableFunc(ticks, func(a, b tick.Tick) int {
+		return a.Time.Compare(b.Time)
+	})
+
+	b.Ticks = nil
+	b.OpenedAt = time.Time{}
+	b.LastDelivery = now
+
+	return ticks
+}
+
+// DeliveryQueue holds the deliveries waiting for the OnNewPricesCallback to be
+// available, according to the concurrency policy of a delivery policy.
+type DeliveryQueue struct {
+	Policy  DeliveryPolicy
+	Pending [][]tick.Tick
+}
+
+// Push adds ticks to deliver, knowing if a callback is running. It returns
+// the number of ticks that have been dropped and coalesced.
+func (q *DeliveryQueue) Push(ticks []tick.Tick, busy bool) (dropped, coalesced int) {
+	switch q.Policy.Concurrency {
+	case ConcurrencyPolicySerial:
+		q.Pending = append(q.Pending, ticks)
+
+		size := q.Policy.QueueSize
+		if size == 0 {
+			size = DefaultQueueSize
+		}
+		for len(q.Pending) > size {
+			dropped += len(q.Pending[0])
+			q.Pending = q.Pending[1:]
+		}
+	case ConcurrencyPolicyDrop:
+		if busy || len(q.Pending) > 0 {
+			return len(ticks), 0
+		}
+		q.Pending = append(q.Pending, ticks)
+	case ConcurrencyPolicyCoalesce:
+		if len(q.Pending) == 0 {
+			q.Pending = append(q.Pending, ticks)
+			return 0, 0
+		}
+
+		merged := TickBuffer{Policy: DeliveryPolicy{LatestOnly: true}}
+		for _, t := range slices.Concat(q.Pending[0], ticks) {
+			merged.Add(t, time.Time{})
+		}
+		coalesced = len(q.Pending[0]) + len(ticks) - len(merged.Ticks)
+		q.Pending[0] = merged.Flush(time.Time{})
+	default:
+		q.Pending = append(q.Pending, ticks)
+	}
+
+	return dropped, coalesced
+}
+
+// Next returns the next ticks to deliver, knowing if a callback is running.
+// It returns false if nothing should be delivered now.
+func (q *DeliveryQueue) Next(busy bool) ([]tick.Tick, bool) {
+	if len(q.Pending) == 0 || (busy && !q.Policy.Concurrency.IsConcurrent()) {
+		return nil, false
+	}
+
+	ticks := q.Pending[0]
+	q.Pending = q.Pending[1:]
+	return ticks, true
+}
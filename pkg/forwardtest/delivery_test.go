@@ -0,0 +1,184 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestDeliverySuite(t *testing.T) {
+	suite.Run(t, new(DeliverySuite))
+}
+
+type DeliverySuite struct {
+	suite.Suite
+}
+
+func (suite *DeliverySuite) TestValidate() {
+	suite.Require().NoError(DeliveryPolicy{}.Validate())
+	suite.Require().ErrorIs(DeliveryPolicy{Window: -time.Second}.Validate(), ErrInvalidDeliveryPolicy)
+	suite.Require().ErrorIs(DeliveryPolicy{MinInterval: -time.Second}.Validate(), ErrInvalidDeliveryPolicy)
+
+	// Latest only has nothing to gather ticks on without window nor min interval
+	suite.Require().ErrorIs(DeliveryPolicy{LatestOnly: true}.Validate(), ErrInvalidDeliveryPolicy)
+	suite.Require().NoError(DeliveryPolicy{LatestOnly: true, MinInterval: time.Second}.Validate())
+}
+
+func (suite *DeliverySuite) TestIsImmediate() {
+	suite.Require().True(DeliveryPolicy{}.IsImmediate())
+	suite.Require().False(DeliveryPolicy{Window: time.Second}.IsImmediate())
+	suite.Require().False(DeliveryPolicy{MinInterval: time.Second}.IsImmediate())
+}
+
+func (suite *DeliverySuite) TestWindow() {
+	now := time.Unix(0, 0)
+	b := TickBuffer{Policy: DeliveryPolicy{Window: time.Second}}
+
+	// Empty buffer
+	_, ok := b.DueAt()
+	suite.Require().False(ok)
+	suite.Require().Nil(b.Flush(now))
+
+	// Ticks are gathered during the window
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now.Add(2), Price: 2}, now)
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now.Add(1), Price: 1}, now.Add(500*time.Millisecond))
+	due, ok := b.DueAt()
+	suite.Require().True(ok)
+	suite.Require().Equal(now.Add(time.Second), due)
+	suite.Require().Nil(b.Flush(now.Add(999 * time.Millisecond)))
+
+	// Ticks are delivered sorted by time at the end of the window
+	ticks := b.Flush(now.Add(time.Second))
+	suite.Require().Len(ticks, 2)
+	suite.Require().Equal(1.0, ticks[0].Price)
+	suite.Require().Equal(2.0, ticks[1].Price)
+	_, ok = b.DueAt()
+	suite.Require().False(ok)
+}
+
+func (suite *DeliverySuite) TestLatestOnly() {
+	now := time.Unix(0, 0)
+	b := TickBuffer{Policy: DeliveryPolicy{Window: time.Second, LatestOnly: true}}
+
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now.Add(1), Price: 1}, now)
+	b.Add(tick.Tick{Exchange: "binance", Pair: "BTC-USDT", Time: now.Add(1), Price: 10}, now)
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now.Add(3), Price: 3}, now)
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now.Add(2), Price: 2}, now)
+
+	ticks := b.Flush(now.Add(time.Second))
+	suite.Require().Len(ticks, 2)
+	suite.Require().Equal(10.0, ticks[0].Price)
+	suite.Require().Equal(3.0, ticks[1].Price)
+}
+
+func (suite *DeliverySuite) TestMinInterval() {
+	now := time.Unix(0, 0)
+	b := TickBuffer{Policy: DeliveryPolicy{MinInterval: time.Minute}}
+
+	// First tick is delivered immediately
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now, Price: 1}, now)
+	suite.Require().Len(b.Flush(now), 1)
+
+	// Next ticks wait for the interval
+	b.Add(tick.Tick{Exchange: "binance", Pair: "ETH-USDT", Time: now.Add(time.Second), Price: 2}, now.Add(time.Second))
+	due, ok := b.DueAt()
+	suite.Require().True(ok)
+	suite.Require().Equal(now.Add(time.Minute), due)
+	suite.Require().Nil(b.Flush(now.Add(time.Second)))
+	suite.Require().Len(b.Flush(now.Add(time.Minute)), 1)
+}
+
+func (suite *DeliverySuite) TestValidateConcurrency() {
+	suite.Require().NoError(DeliveryPolicy{Concurrency: ConcurrencyPolicySerial, QueueSize: 10}.Validate())
+	suite.Require().ErrorIs(DeliveryPolicy{Concurrency: "unknown"}.Validate(), ErrInvalidDeliveryPolicy)
+	suite.Require().ErrorIs(DeliveryPolicy{QueueSize: -1}.Validate(), ErrInvalidDeliveryPolicy)
+
+	// Only the concurrent policy delivers ticks immediately
+	suite.Require().True(DeliveryPolicy{Concurrency: ConcurrencyPolicyConcurrent}.IsImmediate())
+	suite.Require().False(DeliveryPolicy{Concurrency: ConcurrencyPolicyDrop}.IsImmediate())
+}
+
+func (suite *DeliverySuite) TestQueueConcurrent() {
+	q := DeliveryQueue{}
+
+	dropped, coalesced := q.Push(testTicks(1), true)
+	suite.Require().Zero(dropped)
+	suite.Require().Zero(coalesced)
+
+	// Ticks are delivered even if a callback is running
+	ticks, ok := q.Next(true)
+	suite.Require().True(ok)
+	suite.Require().Len(ticks, 1)
+
+	_, ok = q.Next(false)
+	suite.Require().False(ok)
+}
+
+func (suite *DeliverySuite) TestQueueSerial() {
+	q := DeliveryQueue{Policy: DeliveryPolicy{Concurrency: ConcurrencyPolicySerial, QueueSize: 2}}
+
+	// The oldest ticks are dropped when the queue is full
+	q.Push(testTicks(1), true)
+	q.Push(testTicks(2), true)
+	dropped, _ := q.Push(testTicks(3), true)
+	suite.Require().Equal(1, dropped)
+
+	// Nothing is delivered while a callback is running
+	_, ok := q.Next(true)
+	suite.Require().False(ok)
+
+	// Ticks are delivered in order
+	ticks, ok := q.Next(false)
+	suite.Require().True(ok)
+	suite.Require().Equal(2.0, ticks[0].Price)
+	ticks, ok = q.Next(false)
+	suite.Require().True(ok)
+	suite.Require().Equal(3.0, ticks[0].Price)
+}
+
+func (suite *DeliverySuite) TestQueueDrop() {
+	q := DeliveryQueue{Policy: DeliveryPolicy{Concurrency: ConcurrencyPolicyDrop}}
+
+	// Ticks are dropped while a callback is running
+	dropped, _ := q.Push(testTicks(1), true)
+	suite.Require().Equal(1, dropped)
+	_, ok := q.Next(false)
+	suite.Require().False(ok)
+
+	// Ticks are kept when no callback is running
+	dropped, _ = q.Push(testTicks(2), false)
+	suite.Require().Zero(dropped)
+	ticks, ok := q.Next(false)
+	suite.Require().True(ok)
+	suite.Require().Equal(2.0, ticks[0].Price)
+}
+
+func (suite *DeliverySuite) TestQueueCoalesce() {
+	q := DeliveryQueue{Policy: DeliveryPolicy{Concurrency: ConcurrencyPolicyCoalesce}}
+
+	// Ticks received while busy are coalesced to the latest per pair
+	q.Push(testTicks(1), true)
+	_, coalesced := q.Push(testTicks(2), true)
+	suite.Require().Equal(1, coalesced)
+	_, coalesced = q.Push(testTicks(3), true)
+	suite.Require().Equal(1, coalesced)
+
+	ticks, ok := q.Next(false)
+	suite.Require().True(ok)
+	suite.Require().Len(ticks, 1)
+	suite.Require().Equal(3.0, ticks[0].Price)
+}
+
+func testTicks(price float64) []tick.Tick {
+	return []tick.Tick{{
+		Exchange: "binance",
+		Pair:     "ETH-USDT",
+		Time:     time.Unix(int64(price), 0),
+		Price:    price,
+	}}
+}
@@ -0,0 +1,120 @@
+package forwardtest
+
+import (
+	"errors"
+	"time"
+
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+// EventType is the type of an event of a forwardtest.
+type EventType string
+
+const (
+	// EventTypeStatusChanged is the event of a change of the forwardtest status.
+	EventTypeStatusChanged EventType = "status_changed"
+	// EventTypeOrderFilled is the event of a filled order.
+	EventTypeOrderFilled EventType = "order_filled"
+	// EventTypeOrderRejected is the event of an order that could not be filled.
+	EventTypeOrderRejected EventType = "order_rejected"
+	// EventTypeRiskRejected is the event of an order rejected by a risk rule.
+	EventTypeRiskRejected EventType = "risk_rejected"
+	// EventTypeTicksProcessed is the event of ticks processed by the
+	// OnNewPricesCallback.
+	EventTypeTicksProcessed EventType = "ticks_processed"
+	// EventTypeCallbackError is the event of a failed callback.
+	EventTypeCallbackError EventType = "callback_error"
+)
+
+// String returns the string representation of the event type.
+func (t EventType) String() string {
+	return string(t)
+}
+
+// Event is an event of a forwardtest. Only the fields of its type are set.
+type Event struct {
+	// ID is the cursor of the event, set when it is recorded: the events of
+	// a forwardtest are committed in the order of increasing ID, so resuming
+	// after the last seen ID misses none. A ticks processed event following
+	// another one is merged into it, which then gets a new ID.
+	ID            int64
+	ForwardtestID uuid.UUID
+	Time          time.Time
+	Type          EventType
+	// Status is the new status of the forwardtest, on a status change.
+	Status Status
+	// Reason is the reason of a status change or of an order rejection.
+	Reason string
+	// Order is the filled or rejected order.
+	Order *order.Order
+	// RiskRule is the rule that rejected the order, on a risk rejection.
+	RiskRule RiskRule
+	// Ticks is the number of processed ticks, LastTick the last of them.
+	Ticks    int
+	LastTick *tick.Tick
+	// Callback is the failed callback and Error its error, on a callback error.
+	Callback CallbackKind
+	Error    string
+}
+
+// NewStatusChangedEvent returns the event of the current status of the forwardtest.
+func NewStatusChangedEvent(ft Forwardtest, t time.Time) Event {
+	return Event{
+		ForwardtestID: ft.ID,
+		Time:          t,
+		Type:          EventTypeStatusChanged,
+		Status:        ft.Status,
+		Reason:        ft.StatusReason,
+	}
+}
+
+// NewOrderEvent returns the event of an order of the forwardtest: filled if
+// there is no cause, otherwise rejected for this cause.
+func NewOrderEvent(forwardtestID uuid.UUID, t time.Time, o order.Order, cause error) Event {
+	event := Event{
+		ForwardtestID: forwardtestID,
+		Time:          t,
+		Type:          EventTypeOrderFilled,
+		Order:         &o,
+	}
+	if cause == nil {
+		return event
+	}
+
+	event.Type = EventTypeOrderRejected
+	event.Reason = cause.Error()
+
+	var riskErr *RiskRejectionError
+	if errors.As(cause, &riskErr) {
+		event.Type = EventTypeRiskRejected
+		event.RiskRule = riskErr.Rule
+	}
+
+	return event
+}
+
+// NewTicksProcessedEvent returns the event of ticks, sorted by time,
+// processed by the forwardtest.
+func NewTicksProcessedEvent(forwardtestID uuid.UUID, ticks []tick.Tick) Event {
+	last := ticks[len(ticks)-1]
+	return Event{
+		ForwardtestID: forwardtestID,
+		Time:          last.Time,
+		Type:          EventTypeTicksProcessed,
+		Ticks:         len(ticks),
+		LastTick:      &last,
+	}
+}
+
+// NewCallbackErrorEvent returns the event of a callback error.
+func NewCallbackErrorEvent(ce CallbackError) Event {
+	return Event{
+		ForwardtestID: ce.ForwardtestID,
+		Time:          ce.Time,
+		Type:          EventTypeCallbackError,
+		Callback:      ce.Callback,
+		Error:         ce.Error,
+	}
+}
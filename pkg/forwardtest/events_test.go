@@ -0,0 +1,68 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestEventsSuite(t *testing.T) {
+	suite.Run(t, new(EventsSuite))
+}
+
+type EventsSuite struct {
+	suite.Suite
+}
+
+func (suite *EventsSuite) TestNewOrderEvent() {
+	id, now := uuid.New(), time.Now()
+	o := order.Order{ID: uuid.New()}
+
+	// Filled
+	event := NewOrderEvent(id, now, o, nil)
+	suite.Require().Equal(EventTypeOrderFilled, event.Type)
+	suite.Require().Equal(o, *event.Order)
+	suite.Require().Empty(event.Reason)
+
+	// Rejected
+	event = NewOrderEvent(id, now, o, errors.New("no liquidity"))
+	suite.Require().Equal(EventTypeOrderRejected, event.Type)
+	suite.Require().Equal("no liquidity", event.Reason)
+
+	// Rejected by a risk rule
+	event = NewOrderEvent(id, now, o, &RiskRejectionError{Rule: RiskRuleMaxPosition, Message: "too large"})
+	suite.Require().Equal(EventTypeRiskRejected, event.Type)
+	suite.Require().Equal(RiskRuleMaxPosition, event.RiskRule)
+	suite.Require().NotEmpty(event.Reason)
+}
+
+func (suite *EventsSuite) TestNewTicksProcessedEvent() {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticks := []tick.Tick{
+		{Time: start, Pair: "ETH-USDT", Price: 100},
+		{Time: start.Add(time.Second), Pair: "BTC-USDT", Price: 200},
+	}
+
+	event := NewTicksProcessedEvent(uuid.New(), ticks)
+	suite.Require().Equal(EventTypeTicksProcessed, event.Type)
+	suite.Require().Equal(2, event.Ticks)
+	suite.Require().Equal(ticks[1], *event.LastTick)
+	suite.Require().Equal(ticks[1].Time, event.Time)
+}
+
+func (suite *EventsSuite) TestNewStatusChangedEvent() {
+	ft := Forwardtest{ID: uuid.New(), Status: StatusFinished, StatusReason: StatusReasonCallbackFailures}
+
+	event := NewStatusChangedEvent(ft, time.Now())
+	suite.Require().Equal(EventTypeStatusChanged, event.Type)
+	suite.Require().Equal(StatusFinished, event.Status)
+	suite.Require().Equal(StatusReasonCallbackFailures, event.Reason)
+}
@@ -0,0 +1,200 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/runtime/order"
+)
+
+var (
+	// ErrInvalidExecutionPolicy is returned when the execution policy is invalid.
+	ErrInvalidExecutionPolicy = errors.New("invalid execution policy")
+	// ErrInvalidOrderBook is returned when an order book snapshot is invalid.
+	ErrInvalidOrderBook = errors.New("invalid order book")
+	// ErrInsufficientDepth is returned when the order book has not enough
+	// liquidity to fill an order.
+	ErrInsufficientDepth = errors.New("insufficient order book depth")
+)
+
+// ExecutionMode defines the price at which orders are filled.
+type ExecutionMode string
+
+const (
+	// ExecutionModeLastPrice fills orders at the last price. This is the default.
+	ExecutionModeLastPrice ExecutionMode = "last_price"
+	// ExecutionModeBidAsk fills buy orders at the ask and sell orders at the
+	// bid, walking the order book when one is available.
+	ExecutionModeBidAsk ExecutionMode = "bid_ask"
+)
+
+// String returns the string representation of the execution mode.
+func (m ExecutionMode) String() string {
+	return string(m)
+}
+
+// Validate validates the execution mode. The empty mode is valid and means
+// last price.
+func (m ExecutionMode) Validate() error {
+	switch m {
+	case "", ExecutionModeLastPrice, ExecutionModeBidAsk:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown execution mode %q", ErrInvalidExecutionPolicy, m)
+	}
+}
+
+// ExecutionPolicy defines how orders are filled on a forwardtest. The zero
+// value fills orders at the last price.
+type ExecutionPolicy struct {
+	Mode ExecutionMode
+	// SyntheticSpread is the relative spread (0.001 for 0.1%) applied around
+	// the last price when no order book is available in bid/ask mode.
+	SyntheticSpread float64
+	// MaxBookAge is the age after which an order book snapshot is ignored
+	// (0 means no limit).
+	MaxBookAge time.Duration
+}
+
+// Validate validates the execution policy.
+func (p ExecutionPolicy) Validate() error {
+	if p.SyntheticSpread < 0 || p.SyntheticSpread >= 2 {
+		return fmt.Errorf("%w: invalid synthetic spread %f", ErrInvalidExecutionPolicy, p.SyntheticSpread)
+	}
+
+	if p.MaxBookAge < 0 {
+		return fmt.Errorf("%w: negative max book age", ErrInvalidExecutionPolicy)
+	}
+
+	return p.Mode.Validate()
+}
+
+// UsesOrderBook returns true if orders are filled from the order book when
+// one is available.
+func (p ExecutionPolicy) UsesOrderBook() bool {
+	return p.Mode == ExecutionModeBidAsk
+}
+
+// BookLevel is a price level of an order book.
+type BookLevel struct {
+	Price float64
+	// Quantity is the quantity available at this price, in base asset. Zero
+	// means the quantity is unknown and the level absorbs any quantity, which
+	// is the case for top-of-book only snapshots.
+	Quantity float64
+}
+
+// OrderBook is a snapshot of the order book of a pair. Bids are sorted by
+// descending price and asks by ascending price.
+type OrderBook struct {
+	Time time.Time
+	Bids []BookLevel
+	Asks []BookLevel
+}
+
+// Validate validates the order book snapshot.
+func (b OrderBook) Validate() error {
+	if len(b.Bids) == 0 && len(b.Asks) == 0 {
+		return fmt.Errorf("%w: no bids nor asks", ErrInvalidOrderBook)
+	}
+
+	if err := validateBookSide(b.Bids, func(prev, cur float64) bool { return cur < prev }); err != nil {
+		return fmt.Errorf("%w: bids: %w", ErrInvalidOrderBook, err)
+	}
+
+	if err := validateBookSide(b.Asks, func(prev, cur float64) bool { return cur > prev }); err != nil {
+		return fmt.Errorf("%w: asks: %w", ErrInvalidOrderBook, err)
+	}
+
+	if len(b.Bids) > 0 && len(b.Asks) > 0 && b.Bids[0].Price > b.Asks[0].Price {
+		return fmt.Errorf("%w: crossed book", ErrInvalidOrderBook)
+	}
+
+	return nil
+}
+
+func validateBookSide(levels []BookLevel, ordered func(prev, cur float64) bool) error {
+	for i, l := range levels {
+		if l.Price <= 0 || l.Quantity < 0 {
+			return fmt.Errorf("invalid level %d", i)
+		}
+
+		if i > 0 && !ordered(levels[i-1].Price, l.Price) {
+			return fmt.Errorf("unsorted level %d", i)
+		}
+	}
+
+	return nil
+}
+
+// Quote is the market data available to fill an order.
+type Quote struct {
+	// Last is the last price of the pair.
+	Last float64
+	// Book is the last order book snapshot of the pair, if any.
+	Book *OrderBook
+	// Time is the time at which the order is filled.
+	Time time.Time
+}
+
+// FillPrice returns the average price at which the order is filled with the
+// quote, according to the execution policy.
+func (p ExecutionPolicy) FillPrice(o order.Order, q Quote) (float64, error) {
+	if !p.UsesOrderBook() {
+		return q.Last, nil
+	}
+
+	// Walk the order book side taken by the order, if available
+	levels := p.bookSide(o.Side, q)
+	if len(levels) > 0 {
+		return walkBook(levels, o.Quantity)
+	}
+
+	// Fall back on the synthetic spread around the last price
+	if o.Side == order.SideIsBuy {
+		return q.Last * (1 + p.SyntheticSpread/2), nil
+	}
+	return q.Last * (1 - p.SyntheticSpread/2), nil
+}
+
+// bookSide returns the levels of the book taken by an order on the side,
+// or nil if there is no usable book.
+func (p ExecutionPolicy) bookSide(side order.Side, q Quote) []BookLevel {
+	if q.Book == nil {
+		return nil
+	}
+
+	if p.MaxBookAge > 0 && q.Time.Sub(q.Book.Time) > p.MaxBookAge {
+		return nil
+	}
+
+	if side == order.SideIsBuy {
+		return q.Book.Asks
+	}
+	return q.Book.Bids
+}
+
+// walkBook returns the average price to fill the quantity by consuming the
+// levels in order.
+func walkBook(levels []BookLevel, quantity float64) (float64, error) {
+	if quantity <= 0 {
+		return levels[0].Price, nil
+	}
+
+	remaining, cost := quantity, 0.0
+	for _, l := range levels {
+		taken := remaining
+		if l.Quantity > 0 && l.Quantity < remaining {
+			taken = l.Quantity
+		}
+
+		cost += taken * l.Price
+		remaining -= taken
+		if remaining <= 0 {
+			return cost / quantity, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %f left to fill", ErrInsufficientDepth, remaining)
+}
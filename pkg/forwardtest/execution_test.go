@@ -0,0 +1,175 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestExecutionSuite(t *testing.T) {
+	suite.Run(t, new(ExecutionSuite))
+}
+
+type ExecutionSuite struct {
+	suite.Suite
+}
+
+func (suite *ExecutionSuite) TestValidate() {
+	suite.Require().NoError(ExecutionPolicy{}.Validate())
+	suite.Require().NoError(ExecutionPolicy{Mode: ExecutionModeBidAsk, SyntheticSpread: 0.001}.Validate())
+	suite.Require().ErrorIs(ExecutionPolicy{Mode: "unknown"}.Validate(), ErrInvalidExecutionPolicy)
+	suite.Require().ErrorIs(ExecutionPolicy{SyntheticSpread: -0.1}.Validate(), ErrInvalidExecutionPolicy)
+	suite.Require().ErrorIs(ExecutionPolicy{MaxBookAge: -time.Second}.Validate(), ErrInvalidExecutionPolicy)
+}
+
+func (suite *ExecutionSuite) TestOrderBookValidate() {
+	suite.Require().NoError(OrderBook{
+		Bids: []BookLevel{{Price: 99, Quantity: 1}, {Price: 98, Quantity: 2}},
+		Asks: []BookLevel{{Price: 101, Quantity: 1}, {Price: 102, Quantity: 2}},
+	}.Validate())
+
+	// Empty book
+	suite.Require().ErrorIs(OrderBook{}.Validate(), ErrInvalidOrderBook)
+
+	// Unsorted levels
+	suite.Require().ErrorIs(OrderBook{
+		Bids: []BookLevel{{Price: 98}, {Price: 99}},
+	}.Validate(), ErrInvalidOrderBook)
+	suite.Require().ErrorIs(OrderBook{
+		Asks: []BookLevel{{Price: 102}, {Price: 101}},
+	}.Validate(), ErrInvalidOrderBook)
+
+	// Invalid level
+	suite.Require().ErrorIs(OrderBook{
+		Asks: []BookLevel{{Price: 0}},
+	}.Validate(), ErrInvalidOrderBook)
+
+	// Crossed book
+	suite.Require().ErrorIs(OrderBook{
+		Bids: []BookLevel{{Price: 102}},
+		Asks: []BookLevel{{Price: 101}},
+	}.Validate(), ErrInvalidOrderBook)
+}
+
+func (suite *ExecutionSuite) TestLastPrice() {
+	p := ExecutionPolicy{}
+	price, err := p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 1}, Quote{
+		Last: 100,
+		Book: &OrderBook{Asks: []BookLevel{{Price: 101}}},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(100.0, price)
+}
+
+func (suite *ExecutionSuite) TestSyntheticSpread() {
+	p := ExecutionPolicy{Mode: ExecutionModeBidAsk, SyntheticSpread: 0.02}
+
+	price, err := p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 1}, Quote{Last: 100})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(101.0, price, 1e-9)
+
+	price, err = p.FillPrice(order.Order{Side: order.SideIsSell, Quantity: 1}, Quote{Last: 100})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(99.0, price, 1e-9)
+}
+
+func (suite *ExecutionSuite) TestBookWalk() {
+	p := ExecutionPolicy{Mode: ExecutionModeBidAsk, SyntheticSpread: 0.02}
+	book := &OrderBook{
+		Bids: []BookLevel{{Price: 99, Quantity: 1}, {Price: 98, Quantity: 1}},
+		Asks: []BookLevel{{Price: 101, Quantity: 1}, {Price: 103, Quantity: 1}},
+	}
+
+	// Top of book
+	price, err := p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 0.5}, Quote{Last: 100, Book: book})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(101.0, price, 1e-9)
+
+	// Walk the asks
+	price, err = p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 2}, Quote{Last: 100, Book: book})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(102.0, price, 1e-9)
+
+	// Walk the bids
+	price, err = p.FillPrice(order.Order{Side: order.SideIsSell, Quantity: 1.5}, Quote{Last: 100, Book: book})
+	suite.Require().NoError(err)
+	suite.Require().InDelta((99+0.5*98)/1.5, price, 1e-9)
+
+	// Not enough depth
+	_, err = p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 3}, Quote{Last: 100, Book: book})
+	suite.Require().ErrorIs(err, ErrInsufficientDepth)
+
+	// Top of book with unknown quantity
+	price, err = p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 10}, Quote{
+		Last: 100,
+		Book: &OrderBook{Asks: []BookLevel{{Price: 100.5}}},
+	})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(100.5, price, 1e-9)
+}
+
+func (suite *ExecutionSuite) TestBookFallback() {
+	now := time.Unix(1000, 0)
+	p := ExecutionPolicy{Mode: ExecutionModeBidAsk, SyntheticSpread: 0.02, MaxBookAge: time.Minute}
+
+	// Too old book
+	price, err := p.FillPrice(order.Order{Side: order.SideIsBuy, Quantity: 1}, Quote{
+		Last: 100,
+		Book: &OrderBook{Time: now.Add(-time.Hour), Asks: []BookLevel{{Price: 105}}},
+		Time: now,
+	})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(101.0, price, 1e-9)
+
+	// Missing side
+	price, err = p.FillPrice(order.Order{Side: order.SideIsSell, Quantity: 1}, Quote{
+		Last: 100,
+		Book: &OrderBook{Time: now, Asks: []BookLevel{{Price: 105}}},
+		Time: now,
+	})
+	suite.Require().NoError(err)
+	suite.Require().InDelta(99.0, price, 1e-9)
+}
+
+func (suite *ExecutionSuite) TestExecuteOrder() {
+	ft := Forwardtest{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 1000}},
+		},
+		Execution: ExecutionPolicy{Mode: ExecutionModeBidAsk},
+	}
+
+	err := ft.ExecuteOrder(order.Order{
+		Type:     order.TypeIsMarket,
+		Exchange: "exchange",
+		Pair:     "ETH-USDT",
+		Side:     order.SideIsBuy,
+		Quantity: 1,
+	}, Quote{
+		Last: 100,
+		Book: &OrderBook{Asks: []BookLevel{{Price: 110}}},
+		Time: time.Unix(60, 0),
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(ft.Orders, 1)
+	suite.Require().Equal(110.0, ft.Orders[0].Price)
+	suite.Require().Equal(890.0, ft.Accounts["exchange"].Balances["USDT"])
+	suite.Require().Equal(1.0, ft.Accounts["exchange"].Balances["ETH"])
+
+	// A quote without time is refused
+	err = ft.ExecuteOrder(order.Order{
+		Type:     order.TypeIsMarket,
+		Exchange: "exchange",
+		Pair:     "ETH-USDT",
+		Side:     order.SideIsBuy,
+		Quantity: 1,
+	}, Quote{Last: 100})
+	suite.Require().ErrorIs(err, ErrNoQuoteTime)
+	suite.Require().Len(ft.Orders, 1)
+}
@@ -0,0 +1,134 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidFailurePolicy is returned when the callback failure policy is invalid.
+	ErrInvalidFailurePolicy = errors.New("invalid callback failure policy")
+)
+
+// StatusReasonCallbackFailures is the status reason of a forwardtest stopped
+// because of too many consecutive callback failures.
+const StatusReasonCallbackFailures = "callback failures"
+
+// CallbackKind identifies a callback executed while a forwardtest is running.
+type CallbackKind string
+
+const (
+	// CallbackKindOnNewPrices is the OnNewPricesCallback of the forwardtest.
+	CallbackKindOnNewPrices CallbackKind = "on_new_prices"
+	// CallbackKindOnNewCandlestick is the OnNewCandlestickCallback of a subscription.
+	CallbackKindOnNewCandlestick CallbackKind = "on_new_candlestick"
+	// CallbackKindOnTimer is the callback of a timer.
+	CallbackKindOnTimer CallbackKind = "on_timer"
+)
+
+// String returns the string representation of the callback kind.
+func (k CallbackKind) String() string {
+	return string(k)
+}
+
+// Validate validates the callback kind.
+func (k CallbackKind) Validate() error {
+	switch k {
+	case CallbackKindOnNewPrices, CallbackKindOnNewCandlestick, CallbackKindOnTimer:
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown callback %q", ErrInvalidFailurePolicy, k)
+	}
+}
+
+// RetryPolicy defines how a failed callback is retried before being
+// considered as failed.
+type RetryPolicy struct {
+	// MaxAttempts is the maximum number of executions of the callback,
+	// including the first one. Zero or one means no retry.
+	MaxAttempts int
+	// InitialInterval is the delay before the first retry. Zero means one second.
+	InitialInterval time.Duration
+	// BackoffCoefficient multiplies the delay after each retry. Zero means 2.
+	BackoffCoefficient float64
+	// MaxInterval is the maximum delay between two retries (0 means 100 times
+	// the initial interval).
+	MaxInterval time.Duration
+}
+
+// Validate validates the retry policy.
+func (rp RetryPolicy) Validate() error {
+	if rp.MaxAttempts < 0 {
+		return fmt.Errorf("%w: negative max attempts", ErrInvalidFailurePolicy)
+	}
+
+	if rp.InitialInterval < 0 || rp.MaxInterval < 0 {
+		return fmt.Errorf("%w: negative retry interval", ErrInvalidFailurePolicy)
+	}
+
+	if rp.BackoffCoefficient != 0 && rp.BackoffCoefficient < 1 {
+		return fmt.Errorf("%w: backoff coefficient %f is below 1", ErrInvalidFailurePolicy, rp.BackoffCoefficient)
+	}
+
+	return nil
+}
+
+// FailurePolicy defines what happens when a callback of a running forwardtest
+// fails. The zero value does not retry callbacks and never stops the
+// forwardtest.
+type FailurePolicy struct {
+	// Retries are the retry policies of the callbacks. Callbacks without
+	// retry policy are executed only once.
+	Retries map[CallbackKind]RetryPolicy
+	// MaxConsecutiveFailures is the number of consecutive callback failures
+	// after which the forwardtest is stopped. Zero disables it.
+	MaxConsecutiveFailures int
+}
+
+// Validate validates the failure policy.
+func (fp FailurePolicy) Validate() error {
+	for kind, rp := range fp.Retries {
+		if err := kind.Validate(); err != nil {
+			return err
+		}
+
+		if err := rp.Validate(); err != nil {
+			return fmt.Errorf("%s: %w", kind, err)
+		}
+	}
+
+	if fp.MaxConsecutiveFailures < 0 {
+		return fmt.Errorf("%w: negative max consecutive failures", ErrInvalidFailurePolicy)
+	}
+
+	return nil
+}
+
+// RetryPolicy returns the retry policy of a callback, if it is retried.
+func (fp FailurePolicy) RetryPolicy(kind CallbackKind) (RetryPolicy, bool) {
+	rp, ok := fp.Retries[kind]
+	if !ok || rp.MaxAttempts <= 1 {
+		return RetryPolicy{}, false
+	}
+
+	return rp, true
+}
+
+// ShouldStop returns true if the given number of consecutive callback
+// failures has reached the threshold. It stays true past the threshold, so a
+// failed stop is attempted again on the next failure.
+func (fp FailurePolicy) ShouldStop(consecutiveFailures int) bool {
+	return fp.MaxConsecutiveFailures > 0 && consecutiveFailures >= fp.MaxConsecutiveFailures
+}
+
+// CallbackError is the failure of a callback of a forwardtest, once all its
+// retries have failed.
+type CallbackError struct {
+	ForwardtestID uuid.UUID
+	Time          time.Time
+	Callback      CallbackKind
+	Error         string
+}
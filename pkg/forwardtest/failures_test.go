@@ -0,0 +1,88 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestFailurePolicySuite(t *testing.T) {
+	suite.Run(t, new(FailurePolicySuite))
+}
+
+type FailurePolicySuite struct {
+	suite.Suite
+}
+
+func (suite *FailurePolicySuite) TestValidate() {
+	cases := []struct {
+		Name   string
+		Policy FailurePolicy
+		Valid  bool
+	}{
+		{Name: "zero", Policy: FailurePolicy{}, Valid: true},
+		{Name: "retries", Policy: FailurePolicy{
+			Retries: map[CallbackKind]RetryPolicy{
+				CallbackKindOnNewPrices: {MaxAttempts: 3, InitialInterval: time.Second, BackoffCoefficient: 2},
+			},
+			MaxConsecutiveFailures: 5,
+		}, Valid: true},
+		{Name: "unknown callback", Policy: FailurePolicy{
+			Retries: map[CallbackKind]RetryPolicy{"on_unknown": {MaxAttempts: 3}},
+		}},
+		{Name: "negative attempts", Policy: FailurePolicy{
+			Retries: map[CallbackKind]RetryPolicy{CallbackKindOnTimer: {MaxAttempts: -1}},
+		}},
+		{Name: "negative interval", Policy: FailurePolicy{
+			Retries: map[CallbackKind]RetryPolicy{CallbackKindOnTimer: {InitialInterval: -time.Second}},
+		}},
+		{Name: "low backoff", Policy: FailurePolicy{
+			Retries: map[CallbackKind]RetryPolicy{CallbackKindOnNewCandlestick: {BackoffCoefficient: 0.5}},
+		}},
+		{Name: "negative threshold", Policy: FailurePolicy{MaxConsecutiveFailures: -1}},
+	}
+
+	for _, c := range cases {
+		err := c.Policy.Validate()
+		if c.Valid {
+			suite.Require().NoError(err, c.Name)
+		} else {
+			suite.Require().ErrorIs(err, ErrInvalidFailurePolicy, c.Name)
+		}
+	}
+}
+
+func (suite *FailurePolicySuite) TestRetryPolicy() {
+	fp := FailurePolicy{
+		Retries: map[CallbackKind]RetryPolicy{
+			CallbackKindOnNewPrices: {MaxAttempts: 3},
+			CallbackKindOnTimer:     {MaxAttempts: 1},
+		},
+	}
+
+	rp, ok := fp.RetryPolicy(CallbackKindOnNewPrices)
+	suite.Require().True(ok)
+	suite.Require().Equal(3, rp.MaxAttempts)
+
+	// A single attempt is not a retry
+	_, ok = fp.RetryPolicy(CallbackKindOnTimer)
+	suite.Require().False(ok)
+
+	_, ok = fp.RetryPolicy(CallbackKindOnNewCandlestick)
+	suite.Require().False(ok)
+}
+
+func (suite *FailurePolicySuite) TestShouldStop() {
+	suite.Require().False(FailurePolicy{}.ShouldStop(10))
+
+	fp := FailurePolicy{MaxConsecutiveFailures: 3}
+	suite.Require().False(fp.ShouldStop(2))
+	suite.Require().True(fp.ShouldStop(3))
+
+	// Keep stopping past the threshold
+	suite.Require().True(fp.ShouldStop(4))
+}
@@ -0,0 +1,55 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrFeedStale is returned when an order is placed on a pair whose price
+	// feed is stale and orders are blocked in this case.
+	ErrFeedStale = errors.New("price feed is stale")
+	// ErrInvalidFeedHealth is returned when the feed health settings are invalid.
+	ErrInvalidFeedHealth = errors.New("invalid feed health settings")
+)
+
+// FeedHealth defines when the price feed of a subscribed pair is considered
+// stale and what happens in this case.
+type FeedHealth struct {
+	// StaleAfter is the duration without tick after which the price feed of a
+	// subscribed pair is stale. Zero disables the detection.
+	StaleAfter time.Duration
+	// BlockOrdersWhenStale rejects the orders on pairs whose feed is stale,
+	// until ticks are received again.
+	BlockOrdersWhenStale bool
+}
+
+// Validate validates the feed health settings.
+func (fh FeedHealth) Validate() error {
+	if fh.StaleAfter < 0 {
+		return fmt.Errorf("%w: negative stale duration", ErrInvalidFeedHealth)
+	}
+
+	return nil
+}
+
+// IsStale returns true if the price feed of the subscription is stale: it has
+// been marked as such or it has not received any tick for too long.
+func (fh FeedHealth) IsStale(sub Subscription, now time.Time) bool {
+	if fh.StaleAfter == 0 {
+		return false
+	}
+
+	return sub.StaleSince != nil || sub.IsStalled(now, fh.StaleAfter)
+}
+
+// CheckFeed checks that an order can be placed on the pair of the subscription.
+func (fh FeedHealth) CheckFeed(sub Subscription, now time.Time) error {
+	if !fh.BlockOrdersWhenStale || !fh.IsStale(sub, now) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: no tick on %s %s since %s",
+		ErrFeedStale, sub.Exchange, sub.Pair, sub.LastActivity().Format(time.RFC3339))
+}
@@ -0,0 +1,66 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+)
+
+func TestFeedHealthSuite(t *testing.T) {
+	suite.Run(t, new(FeedHealthSuite))
+}
+
+type FeedHealthSuite struct {
+	suite.Suite
+}
+
+func (suite *FeedHealthSuite) TestValidate() {
+	suite.Require().NoError(FeedHealth{}.Validate())
+	suite.Require().NoError(FeedHealth{StaleAfter: time.Minute}.Validate())
+	suite.Require().ErrorIs(FeedHealth{StaleAfter: -time.Minute}.Validate(), ErrInvalidFeedHealth)
+}
+
+func (suite *FeedHealthSuite) TestIsStale() {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{Exchange: "binance", Pair: "ETH-USDT", CreatedAt: created}
+	now := created.Add(5 * time.Minute)
+
+	// Detection disabled
+	suite.Require().False(FeedHealth{}.IsStale(sub, now))
+
+	// Not stale yet
+	fh := FeedHealth{StaleAfter: 10 * time.Minute}
+	suite.Require().False(fh.IsStale(sub, now))
+
+	// Stale because of the delay
+	fh.StaleAfter = 2 * time.Minute
+	suite.Require().True(fh.IsStale(sub, now))
+
+	// Stale because it has been marked
+	fh.StaleAfter = 10 * time.Minute
+	sub.StaleSince = &now
+	suite.Require().True(fh.IsStale(sub, now))
+}
+
+func (suite *FeedHealthSuite) TestCheckFeed() {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{Exchange: "binance", Pair: "ETH-USDT", CreatedAt: created}
+	now := created.Add(5 * time.Minute)
+
+	// Orders are not blocked
+	fh := FeedHealth{StaleAfter: time.Minute}
+	suite.Require().NoError(fh.CheckFeed(sub, now))
+
+	// Orders are blocked
+	fh.BlockOrdersWhenStale = true
+	suite.Require().ErrorIs(fh.CheckFeed(sub, now), ErrFeedStale)
+
+	// Feed is healthy again
+	lastTick := now.Add(-time.Second)
+	sub.LastTickAt = &lastTick
+	suite.Require().NoError(fh.CheckFeed(sub, now))
+}
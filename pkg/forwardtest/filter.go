@@ -0,0 +1,147 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrTickRejected is wrapped by every error returned when a tick is
+	// rejected by the price sanity filter.
+	ErrTickRejected = errors.New("tick rejected by price filter")
+	// ErrInvalidTickFilter is returned when the tick filter is invalid.
+	ErrInvalidTickFilter = errors.New("invalid tick filter")
+)
+
+// TickRule is the name of a price sanity check.
+type TickRule string
+
+const (
+	// TickRuleNonPositivePrice checks that the price is strictly positive.
+	TickRuleNonPositivePrice TickRule = "non_positive_price"
+	// TickRuleMaxJump checks the price change versus the last accepted price.
+	TickRuleMaxJump TickRule = "max_jump"
+	// TickRuleOutOfOrder checks that the tick is after the last accepted tick.
+	TickRuleOutOfOrder TickRule = "out_of_order"
+)
+
+// String returns the string representation of the tick rule.
+func (r TickRule) String() string {
+	return string(r)
+}
+
+// TickRejectionError is the error returned when a tick breaks a tick rule.
+type TickRejectionError struct {
+	Rule    TickRule
+	Message string
+}
+
+// Error returns the error message.
+func (e *TickRejectionError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrTickRejected, e.Rule, e.Message)
+}
+
+// Unwrap returns ErrTickRejected so the error can be checked with errors.Is.
+func (e *TickRejectionError) Unwrap() error {
+	return ErrTickRejected
+}
+
+func newTickRejectionError(rule TickRule, format string, args ...any) error {
+	return &TickRejectionError{
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// DefaultMaxJumpWindow is the default duration after the last accepted tick
+// after which the max jump is not checked anymore.
+const DefaultMaxJumpWindow = time.Minute
+
+// TickFilter are the price sanity checks applied to the ticks received on the
+// subscriptions of a forwardtest before they are forwarded to the bot. Zero
+// values disable the corresponding check.
+type TickFilter struct {
+	// RejectNonPositivePrice rejects the ticks with a zero or negative price.
+	RejectNonPositivePrice bool
+	// MaxJump is the maximum relative price change versus the last accepted
+	// price of the pair (e.g. 0.2 for 20%).
+	MaxJump float64
+	// MaxJumpWindow is the duration after the last accepted tick of the pair
+	// after which the max jump is not checked anymore: the price is then
+	// re-anchored on the new tick, instead of rejecting every tick after a
+	// lasting move. If zero, DefaultMaxJumpWindow is used.
+	MaxJumpWindow time.Duration
+	// RejectOutOfOrder rejects the ticks whose time is not strictly after the
+	// last accepted tick of the pair, including duplicates.
+	RejectOutOfOrder bool
+}
+
+// Validate validates the tick filter.
+func (tf TickFilter) Validate() error {
+	if tf.MaxJump < 0 || math.IsNaN(tf.MaxJump) || math.IsInf(tf.MaxJump, 0) {
+		return fmt.Errorf("%w: invalid max jump %f", ErrInvalidTickFilter, tf.MaxJump)
+	}
+
+	if tf.MaxJumpWindow < 0 {
+		return fmt.Errorf("%w: negative max jump window", ErrInvalidTickFilter)
+	}
+
+	return nil
+}
+
+// IsEnabled returns true if at least one check is enabled.
+func (tf TickFilter) IsEnabled() bool {
+	return tf.RejectNonPositivePrice || tf.MaxJump > 0 || tf.RejectOutOfOrder
+}
+
+// Check checks the tick against the last accepted tick of its subscription.
+// The returned error is a *TickRejectionError if a rule is broken.
+func (tf TickFilter) Check(t tick.Tick, sub Subscription) error {
+	if tf.RejectNonPositivePrice && t.Price <= 0 {
+		return newTickRejectionError(TickRuleNonPositivePrice,
+			"price %f is not positive", t.Price)
+	}
+
+	if tf.RejectOutOfOrder && sub.LastTickAt != nil && !t.Time.After(*sub.LastTickAt) {
+		return newTickRejectionError(TickRuleOutOfOrder,
+			"tick time %s is not after last tick time %s",
+			t.Time.Format(time.RFC3339Nano), sub.LastTickAt.Format(time.RFC3339Nano))
+	}
+
+	if tf.MaxJump > 0 && sub.LastPrice > 0 && !tf.isMaxJumpExpired(t, sub) {
+		jump := math.Abs(t.Price-sub.LastPrice) / sub.LastPrice
+		if jump > tf.MaxJump {
+			return newTickRejectionError(TickRuleMaxJump,
+				"price change of %.2f%% from %f to %f exceeds %.2f%%",
+				jump*100, sub.LastPrice, t.Price, tf.MaxJump*100)
+		}
+	}
+
+	return nil
+}
+
+// isMaxJumpExpired returns true if the last accepted tick of the subscription
+// is too old for its price to be compared with the tick.
+func (tf TickFilter) isMaxJumpExpired(t tick.Tick, sub Subscription) bool {
+	window := tf.MaxJumpWindow
+	if window == 0 {
+		window = DefaultMaxJumpWindow
+	}
+
+	return sub.LastTickAt != nil && t.Time.Sub(*sub.LastTickAt) > window
+}
+
+// QuarantinedTick is a tick that has been rejected by the tick filter of a
+// forwardtest and has not been forwarded to the bot.
+type QuarantinedTick struct {
+	ForwardtestID uuid.UUID
+	Tick          tick.Tick
+	Rule          TickRule
+	Reason        string
+	QuarantinedAt time.Time
+}
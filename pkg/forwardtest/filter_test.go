@@ -0,0 +1,86 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTickFilterSuite(t *testing.T) {
+	suite.Run(t, new(TickFilterSuite))
+}
+
+type TickFilterSuite struct {
+	suite.Suite
+}
+
+func (suite *TickFilterSuite) requireRule(err error, rule TickRule) {
+	suite.Require().ErrorIs(err, ErrTickRejected)
+
+	var rejection *TickRejectionError
+	suite.Require().True(errors.As(err, &rejection))
+	suite.Require().Equal(rule, rejection.Rule)
+}
+
+func (suite *TickFilterSuite) TestValidate() {
+	suite.Require().NoError(TickFilter{}.Validate())
+	suite.Require().NoError(TickFilter{MaxJump: 0.5}.Validate())
+	suite.Require().ErrorIs(TickFilter{MaxJump: -0.1}.Validate(), ErrInvalidTickFilter)
+	suite.Require().ErrorIs(TickFilter{MaxJumpWindow: -time.Second}.Validate(), ErrInvalidTickFilter)
+}
+
+func (suite *TickFilterSuite) TestDisabled() {
+	tf := TickFilter{}
+	suite.Require().False(tf.IsEnabled())
+	suite.Require().NoError(tf.Check(tick.Tick{Price: -1}, Subscription{}))
+}
+
+func (suite *TickFilterSuite) TestNonPositivePrice() {
+	tf := TickFilter{RejectNonPositivePrice: true}
+	suite.Require().True(tf.IsEnabled())
+
+	suite.requireRule(tf.Check(tick.Tick{Price: 0}, Subscription{}), TickRuleNonPositivePrice)
+	suite.requireRule(tf.Check(tick.Tick{Price: -10}, Subscription{}), TickRuleNonPositivePrice)
+	suite.Require().NoError(tf.Check(tick.Tick{Price: 10}, Subscription{}))
+}
+
+func (suite *TickFilterSuite) TestMaxJump() {
+	tf := TickFilter{MaxJump: 0.2}
+	sub := Subscription{LastPrice: 100}
+
+	suite.Require().NoError(tf.Check(tick.Tick{Price: 119}, sub))
+	suite.Require().NoError(tf.Check(tick.Tick{Price: 81}, sub))
+	suite.requireRule(tf.Check(tick.Tick{Price: 150}, sub), TickRuleMaxJump)
+	suite.requireRule(tf.Check(tick.Tick{Price: 50}, sub), TickRuleMaxJump)
+
+	// Without last price, the jump is not checked
+	suite.Require().NoError(tf.Check(tick.Tick{Price: 150}, Subscription{}))
+
+	// After the window without accepted tick, the price is re-anchored
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub.LastTickAt = &last
+	suite.requireRule(tf.Check(tick.Tick{Time: last.Add(DefaultMaxJumpWindow), Price: 150}, sub), TickRuleMaxJump)
+	suite.Require().NoError(tf.Check(tick.Tick{Time: last.Add(DefaultMaxJumpWindow + time.Second), Price: 150}, sub))
+
+	tf.MaxJumpWindow = time.Second
+	suite.Require().NoError(tf.Check(tick.Tick{Time: last.Add(2 * time.Second), Price: 150}, sub))
+}
+
+func (suite *TickFilterSuite) TestOutOfOrder() {
+	tf := TickFilter{RejectOutOfOrder: true}
+	last := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{LastTickAt: &last}
+
+	suite.Require().NoError(tf.Check(tick.Tick{Time: last.Add(time.Second), Price: 1}, sub))
+	suite.requireRule(tf.Check(tick.Tick{Time: last, Price: 1}, sub), TickRuleOutOfOrder)
+	suite.requireRule(tf.Check(tick.Tick{Time: last.Add(-time.Second), Price: 1}, sub), TickRuleOutOfOrder)
+
+	// Without last tick, any time is accepted
+	suite.Require().NoError(tf.Check(tick.Tick{Time: last, Price: 1}, Subscription{}))
+}
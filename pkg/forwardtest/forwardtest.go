@@ -11,6 +11,7 @@ import (
 	"github.com/cryptellation/runtime"
 	"github.com/cryptellation/runtime/account"
 	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
 	"github.com/google/uuid"
 )
 
@@ -19,22 +20,67 @@ var (
 	ErrEmptyAccounts = errors.New("empty accounts")
 	// ErrInvalidExchange is returned when the exchange is invalid.
 	ErrInvalidExchange = errors.New("invalid exchange")
+	// ErrRunning is returned when an operation is not possible on a running forwardtest.
+	ErrRunning = errors.New("forwardtest is running")
+	// ErrNoInitialAccounts is returned when the initial accounts of a forwardtest are unknown.
+	ErrNoInitialAccounts = errors.New("no initial accounts")
+	// ErrNoQuoteTime is returned when an order is executed from a quote without
+	// time, as its execution time would not be deterministic.
+	ErrNoQuoteTime = errors.New("quote has no time")
 )
 
 // Forwardtest is a forwardtest.
 type Forwardtest struct {
-	ID        uuid.UUID
-	UpdatedAt time.Time
-	Accounts  map[string]account.Account
-	Orders    []order.Order
-	Callbacks runtime.Callbacks
-	Status    Status
+	ID                uuid.UUID
+	ParentID          *uuid.UUID
+	UpdatedAt         time.Time
+	InitialAccounts   map[string]account.Account
+	Accounts          map[string]account.Account
+	Orders            []order.Order
+	Callbacks         runtime.Callbacks
+	OptionalCallbacks OptionalCallbacks
+	Risk              RiskLimits
+	Delivery          DeliveryPolicy
+	FeedHealth        FeedHealth
+	TickFilter        TickFilter
+	Execution         ExecutionPolicy
+	Failures          FailurePolicy
+	// OrderTriggers are the ticks that triggered the orders returned by
+	// callbacks, by order ID.
+	OrderTriggers map[uuid.UUID]tick.Tick
+	// RecordTicks saves every tick delivered to the forwardtest so it can be
+	// replayed later.
+	RecordTicks bool
+	// ReplayOf is the ID of the forwardtest whose recorded ticks are replayed
+	// on this forwardtest, if it is a replay.
+	ReplayOf *uuid.UUID
+	Status   Status
+	// StatusReason explains the status when it has been set by the service,
+	// like "callback failures" when the forwardtest has been stopped
+	// automatically.
+	StatusReason string
+	// ConsecutiveCallbackFailures is the number of callback failures since
+	// the last successful callback. It is maintained by the service and is
+	// not saved with the rest of the forwardtest.
+	ConsecutiveCallbackFailures int
+	Archived                    bool
+	Audit                       []AuditEntry
 }
 
 // NewForwardtestParams is the params for the New function.
 type NewForwardtestParams struct {
-	Accounts  map[string]account.Account
-	Callbacks runtime.Callbacks
+	Accounts          map[string]account.Account
+	Callbacks         runtime.Callbacks
+	OptionalCallbacks OptionalCallbacks
+	Risk              RiskLimits
+	Delivery          DeliveryPolicy
+	FeedHealth        FeedHealth
+	TickFilter        TickFilter
+	Execution         ExecutionPolicy
+	Failures          FailurePolicy
+	RecordTicks       bool
+	// ParentID is the ID of the forwardtest this one has been cloned from.
+	ParentID *uuid.UUID
 }
 
 // Validate validates the NewParams.
@@ -47,6 +93,34 @@ func (np NewForwardtestParams) Validate() error {
 		return fmt.Errorf("validating callbacks: %w", err)
 	}
 
+	if err := np.OptionalCallbacks.Validate(); err != nil {
+		return fmt.Errorf("validating optional callbacks: %w", err)
+	}
+
+	if err := np.Risk.Validate(); err != nil {
+		return fmt.Errorf("validating risk limits: %w", err)
+	}
+
+	if err := np.Delivery.Validate(); err != nil {
+		return fmt.Errorf("validating delivery policy: %w", err)
+	}
+
+	if err := np.FeedHealth.Validate(); err != nil {
+		return fmt.Errorf("validating feed health: %w", err)
+	}
+
+	if err := np.TickFilter.Validate(); err != nil {
+		return fmt.Errorf("validating tick filter: %w", err)
+	}
+
+	if err := np.Execution.Validate(); err != nil {
+		return fmt.Errorf("validating execution policy: %w", err)
+	}
+
+	if err := np.Failures.Validate(); err != nil {
+		return fmt.Errorf("validating failure policy: %w", err)
+	}
+
 	return nil
 }
 
@@ -57,15 +131,168 @@ func New(params NewForwardtestParams) (Forwardtest, error) {
 	}
 
 	return Forwardtest{
-		ID:        uuid.New(),
-		Accounts:  params.Accounts,
-		Callbacks: params.Callbacks,
-		Status:    StatusReady,
+		ID:                uuid.New(),
+		ParentID:          params.ParentID,
+		InitialAccounts:   copyAccounts(params.Accounts),
+		Accounts:          copyAccounts(params.Accounts),
+		Callbacks:         params.Callbacks,
+		OptionalCallbacks: params.OptionalCallbacks,
+		Risk:              params.Risk,
+		Delivery:          params.Delivery,
+		FeedHealth:        params.FeedHealth,
+		TickFilter:        params.TickFilter,
+		Execution:         params.Execution,
+		Failures:          params.Failures,
+		RecordTicks:       params.RecordTicks,
+		Status:            StatusReady,
 	}, nil
 }
 
-// AddOrder adds an order to the forwardtest.
+// CloneParams are the fields that can be overridden when cloning a forwardtest.
+// Nil fields are copied from the original forwardtest.
+type CloneParams struct {
+	Accounts          map[string]account.Account
+	Callbacks         *runtime.Callbacks
+	OptionalCallbacks *OptionalCallbacks
+	Risk              *RiskLimits
+	Delivery          *DeliveryPolicy
+	FeedHealth        *FeedHealth
+	TickFilter        *TickFilter
+	Execution         *ExecutionPolicy
+	Failures          *FailurePolicy
+	RecordTicks       *bool
+}
+
+// Clone creates a new ready forwardtest with the configuration of the
+// forwardtest, starting from its initial accounts.
+func (ft Forwardtest) Clone(params CloneParams) (Forwardtest, error) {
+	payload := NewForwardtestParams{
+		Accounts:          ft.InitialAccounts,
+		Callbacks:         ft.Callbacks,
+		OptionalCallbacks: ft.OptionalCallbacks,
+		Risk:              ft.Risk,
+		Delivery:          ft.Delivery,
+		FeedHealth:        ft.FeedHealth,
+		TickFilter:        ft.TickFilter,
+		Execution:         ft.Execution,
+		Failures:          ft.Failures,
+		RecordTicks:       ft.RecordTicks,
+		ParentID:          &ft.ID,
+	}
+
+	// Forwardtests created before initial accounts were saved
+	if payload.Accounts == nil {
+		payload.Accounts = ft.Accounts
+	}
+
+	if params.Accounts != nil {
+		payload.Accounts = params.Accounts
+	}
+	if params.Callbacks != nil {
+		payload.Callbacks = *params.Callbacks
+	}
+	if params.Risk != nil {
+		payload.Risk = *params.Risk
+	}
+	if params.OptionalCallbacks != nil {
+		payload.OptionalCallbacks = *params.OptionalCallbacks
+	}
+	if params.Delivery != nil {
+		payload.Delivery = *params.Delivery
+	}
+	if params.FeedHealth != nil {
+		payload.FeedHealth = *params.FeedHealth
+	}
+	if params.TickFilter != nil {
+		payload.TickFilter = *params.TickFilter
+	}
+	if params.Execution != nil {
+		payload.Execution = *params.Execution
+	}
+	if params.Failures != nil {
+		payload.Failures = *params.Failures
+	}
+	if params.RecordTicks != nil {
+		payload.RecordTicks = *params.RecordTicks
+	}
+
+	return New(payload)
+}
+
+// NewReplay creates a new ready forwardtest, cloned from the forwardtest, on
+// which the ticks recorded on the forwardtest are replayed. Ticks are not
+// recorded on the replay.
+func (ft Forwardtest) NewReplay(params CloneParams) (Forwardtest, error) {
+	replay, err := ft.Clone(params)
+	if err != nil {
+		return Forwardtest{}, err
+	}
+
+	replay.ReplayOf = &ft.ID
+	replay.RecordTicks = false
+
+	return replay, nil
+}
+
+// IsReplay returns true if the forwardtest replays the recorded ticks of
+// another forwardtest instead of receiving live ticks.
+func (ft Forwardtest) IsReplay() bool {
+	return ft.ReplayOf != nil
+}
+
+// Reset restores the forwardtest to its initial state: initial balances, no
+// orders and ready status. The reset is recorded in the audit log.
+func (ft *Forwardtest) Reset(now time.Time) error {
+	if ft.Status == StatusRunning {
+		return ErrRunning
+	}
+
+	if ft.InitialAccounts == nil {
+		return ErrNoInitialAccounts
+	}
+
+	ft.Audit = append(ft.Audit, AuditEntry{
+		Time:   now,
+		Action: AuditActionReset,
+		Details: fmt.Sprintf("reset from status %q with %d orders",
+			ft.Status, len(ft.Orders)),
+	})
+	ft.Accounts = copyAccounts(ft.InitialAccounts)
+	ft.Orders = nil
+	ft.OrderTriggers = nil
+	ft.Status = StatusReady
+	ft.StatusReason = ""
+
+	return nil
+}
+
+func copyAccounts(accounts map[string]account.Account) map[string]account.Account {
+	if accounts == nil {
+		return nil
+	}
+
+	accountsCopy := make(map[string]account.Account, len(accounts))
+	for exchange, acc := range accounts {
+		accountsCopy[exchange] = account.Account{
+			Balances: maps.Clone(acc.Balances),
+		}
+	}
+
+	return accountsCopy
+}
+
+// AddOrder adds an order to the forwardtest, executed at the close price of
+// the candlestick.
 func (ft *Forwardtest) AddOrder(o order.Order, cs candlestick.Candlestick) error {
+	return ft.ExecuteOrder(o, Quote{
+		Last: cs.Close,
+		Time: cs.Time,
+	})
+}
+
+// ExecuteOrder adds an order to the forwardtest, filled from the quote
+// according to the execution policy of the forwardtest.
+func (ft *Forwardtest) ExecuteOrder(o order.Order, q Quote) error {
 	// Get exchange account
 	exchangeAccount, ok := ft.Accounts[o.Exchange]
 	if !ok {
@@ -73,10 +300,21 @@ func (ft *Forwardtest) AddOrder(o order.Order, cs candlestick.Candlestick) error
 	}
 
 	// Get price
-	price := cs.Close
-	if price == 0 {
+	if q.Last == 0 {
 		return errors.New("price is 0, that should not happen")
 	}
+	if q.Time.IsZero() {
+		return ErrNoQuoteTime
+	}
+	price, err := ft.Execution.FillPrice(o, q)
+	if err != nil {
+		return err
+	}
+
+	// Check risk limits
+	if err := ft.CheckOrder(o, price); err != nil {
+		return err
+	}
 
 	// Apply order
 	if err := exchangeAccount.ApplyOrder(price, o); err != nil {
@@ -84,8 +322,8 @@ func (ft *Forwardtest) AddOrder(o order.Order, cs candlestick.Candlestick) error
 	}
 	ft.Accounts[o.Exchange] = exchangeAccount
 
-	// Update and save the order
-	t := time.Now()
+	// Update and save the order, filled at the time of the quote
+	t := q.Time
 	o.ExecutionTime = &t
 	o.Price = price
 	ft.Orders = append(ft.Orders, o)
@@ -93,6 +331,17 @@ func (ft *Forwardtest) AddOrder(o order.Order, cs candlestick.Candlestick) error
 	return nil
 }
 
+// Order returns the executed order with the given ID, if any.
+func (ft Forwardtest) Order(id uuid.UUID) (order.Order, bool) {
+	for _, o := range ft.Orders {
+		if o.ID == id {
+			return o, true
+		}
+	}
+
+	return order.Order{}, false
+}
+
 // GetAccountsSymbols returns the list of symbols used in the accounts.
 func (ft Forwardtest) GetAccountsSymbols() []string {
 	symbols := make(map[string]string, 0)
@@ -5,10 +5,15 @@ package forwardtest
 
 import (
 	"testing"
+	"time"
 
+	"github.com/cryptellation/candlesticks/pkg/candlestick"
+	"github.com/cryptellation/runtime"
 	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
 
@@ -77,3 +82,152 @@ func (suite *ForwardtestSuite) TestGetAccountsSymbols() {
 		suite.Require().True(cmp.Diff(c.Expected, ft.GetAccountsSymbols(), cmpopts.SortSlices(less)) == "")
 	}
 }
+
+func testCallbacks(name string) runtime.Callbacks {
+	return runtime.Callbacks{
+		OnInitCallback:      runtime.CallbackWorkflow{Name: name + "-init", TaskQueueName: "queue"},
+		OnNewPricesCallback: runtime.CallbackWorkflow{Name: name + "-prices", TaskQueueName: "queue"},
+		OnExitCallback:      runtime.CallbackWorkflow{Name: name + "-exit", TaskQueueName: "queue"},
+	}
+}
+
+func (suite *ForwardtestSuite) TestClone() {
+	// Create a forwardtest and pass an order on it
+	ft, err := New(NewForwardtestParams{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 1000}},
+		},
+		Callbacks: testCallbacks("original"),
+		Risk:      RiskLimits{MaxOrderNotional: 500},
+		Delivery:  DeliveryPolicy{Window: time.Second},
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(ft.AddOrder(order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "exchange",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	}, candlestick.Candlestick{Time: time.Unix(60, 0), Close: 100}))
+	ft.Status = StatusFinished
+
+	// Clone without override
+	clone, err := ft.Clone(CloneParams{})
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(ft.ID, clone.ID)
+	suite.Require().Equal(&ft.ID, clone.ParentID)
+	suite.Require().Equal(StatusReady, clone.Status)
+	suite.Require().Equal(1000.0, clone.Accounts["exchange"].Balances["USDT"])
+	suite.Require().Empty(clone.Orders)
+	suite.Require().Equal(ft.Callbacks, clone.Callbacks)
+	suite.Require().Equal(ft.Risk, clone.Risk)
+	suite.Require().Equal(ft.Delivery, clone.Delivery)
+
+	// Clone with overrides
+	callbacks := testCallbacks("override")
+	clone, err = ft.Clone(CloneParams{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 2000}},
+		},
+		Callbacks: &callbacks,
+		Risk:      &RiskLimits{},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(2000.0, clone.Accounts["exchange"].Balances["USDT"])
+	suite.Require().Equal(callbacks, clone.Callbacks)
+	suite.Require().Equal(RiskLimits{}, clone.Risk)
+}
+
+func (suite *ForwardtestSuite) TestNewReplay() {
+	ft, err := New(NewForwardtestParams{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 1000}},
+		},
+		Callbacks:   testCallbacks("original"),
+		RecordTicks: true,
+	})
+	suite.Require().NoError(err)
+	suite.Require().False(ft.IsReplay())
+
+	// Replay with the original callbacks
+	replay, err := ft.NewReplay(CloneParams{})
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(ft.ID, replay.ID)
+	suite.Require().True(replay.IsReplay())
+	suite.Require().Equal(&ft.ID, replay.ReplayOf)
+	suite.Require().False(replay.RecordTicks)
+	suite.Require().Equal(StatusReady, replay.Status)
+	suite.Require().Equal(ft.Callbacks, replay.Callbacks)
+
+	// Replay with other callbacks
+	callbacks := testCallbacks("override")
+	replay, err = ft.NewReplay(CloneParams{Callbacks: &callbacks})
+	suite.Require().NoError(err)
+	suite.Require().Equal(callbacks, replay.Callbacks)
+}
+
+func (suite *ForwardtestSuite) TestReset() {
+	// Create a forwardtest and pass an order on it
+	ft, err := New(NewForwardtestParams{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 1000}},
+		},
+		Callbacks: testCallbacks("reset"),
+	})
+	suite.Require().NoError(err)
+	suite.Require().NoError(ft.AddOrder(order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "exchange",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	}, candlestick.Candlestick{Time: time.Unix(60, 0), Close: 100}))
+
+	// Reset is refused while running
+	ft.Status = StatusRunning
+	suite.Require().ErrorIs(ft.Reset(time.Now()), ErrRunning)
+
+	// Reset once finished
+	ft.Status = StatusFinished
+	now := time.Now()
+	suite.Require().NoError(ft.Reset(now))
+	suite.Require().Equal(StatusReady, ft.Status)
+	suite.Require().Empty(ft.Orders)
+	suite.Require().Equal(1000.0, ft.Accounts["exchange"].Balances["USDT"])
+	suite.Require().Equal(0.0, ft.Accounts["exchange"].Balances["BTC"])
+	suite.Require().Len(ft.Audit, 1)
+	suite.Require().Equal(AuditActionReset, ft.Audit[0].Action)
+	suite.Require().Equal(now, ft.Audit[0].Time)
+
+	// Reset without initial accounts
+	ft.InitialAccounts = nil
+	suite.Require().ErrorIs(ft.Reset(now), ErrNoInitialAccounts)
+}
+
+func (suite *ForwardtestSuite) TestOrder() {
+	ft := Forwardtest{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 1000}},
+		},
+	}
+	o := order.Order{
+		ID:       uuid.New(),
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "exchange",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	}
+
+	// Unknown order
+	_, ok := ft.Order(o.ID)
+	suite.Require().False(ok)
+
+	// Executed order, filled at the time of the quote
+	filledAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	suite.Require().NoError(ft.ExecuteOrder(o, Quote{Last: 100, Time: filledAt}))
+	executed, ok := ft.Order(o.ID)
+	suite.Require().True(ok)
+	suite.Require().Equal(100.0, executed.Price)
+	suite.Require().Equal(filledAt, *executed.ExecutionTime)
+}
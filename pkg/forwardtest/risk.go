@@ -0,0 +1,273 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/cryptellation/candlesticks/pkg/pair"
+	"github.com/cryptellation/runtime/order"
+)
+
+var (
+	// ErrOrderRejected is wrapped by every error returned when an order is
+	// rejected by the pre-trade risk checks.
+	ErrOrderRejected = errors.New("order rejected by risk checks")
+	// ErrInvalidRiskLimits is returned when the risk limits are invalid.
+	ErrInvalidRiskLimits = errors.New("invalid risk limits")
+)
+
+// stepSizeTolerance is the tolerance used when checking that a quantity is a
+// multiple of the step size, to absorb floating point errors.
+const stepSizeTolerance = 1e-9
+
+// flatPositionTolerance is the tolerance under which the position opened by
+// orders is considered closed, to absorb floating point errors.
+const flatPositionTolerance = 1e-9
+
+// RiskRule is the name of a pre-trade risk check.
+type RiskRule string
+
+const (
+	// RiskRuleMaxOrderNotional checks the order value in quote asset.
+	RiskRuleMaxOrderNotional RiskRule = "max_order_notional"
+	// RiskRuleMaxPosition checks the resulting position on the base asset.
+	RiskRuleMaxPosition RiskRule = "max_position"
+	// RiskRuleMaxOpenOrders checks the number of open orders.
+	RiskRuleMaxOpenOrders RiskRule = "max_open_orders"
+	// RiskRuleAllowedPairs checks that the pair is allowed on the exchange.
+	RiskRuleAllowedPairs RiskRule = "allowed_pairs"
+	// RiskRuleMinQuantity checks the minimum quantity of the pair.
+	RiskRuleMinQuantity RiskRule = "min_quantity"
+	// RiskRuleStepSize checks the quantity step size of the pair.
+	RiskRuleStepSize RiskRule = "step_size"
+)
+
+// String returns the string representation of the risk rule.
+func (r RiskRule) String() string {
+	return string(r)
+}
+
+// RiskRejectionError is the error returned when an order breaks a risk rule.
+type RiskRejectionError struct {
+	Rule    RiskRule
+	Message string
+}
+
+// Error returns the error message.
+func (e *RiskRejectionError) Error() string {
+	return fmt.Sprintf("%s: %s: %s", ErrOrderRejected, e.Rule, e.Message)
+}
+
+// Unwrap returns ErrOrderRejected so the error can be checked with errors.Is.
+func (e *RiskRejectionError) Unwrap() error {
+	return ErrOrderRejected
+}
+
+func newRiskRejectionError(rule RiskRule, format string, args ...any) error {
+	return &RiskRejectionError{
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// PairRules are the quantity rules of a pair.
+type PairRules struct {
+	// MinQuantity is the minimum quantity of an order (0 means no minimum).
+	MinQuantity float64
+	// StepSize is the quantity increment of an order (0 means no step).
+	StepSize float64
+}
+
+// RiskLimits are the pre-trade checks applied to an order before it is
+// executed on a forwardtest. Zero values disable the corresponding check.
+type RiskLimits struct {
+	// MaxOrderNotional is the maximum value of an order in quote asset.
+	MaxOrderNotional float64
+	// MaxPositions is the maximum balance of each asset on an exchange account.
+	MaxPositions map[string]float64
+	// MaxOpenOrders is the maximum number of open orders. As orders are filled
+	// immediately, an order stays open until the position built by the orders
+	// of its exchange and pair is closed. Orders reducing an open position are
+	// always accepted.
+	MaxOpenOrders int
+	// AllowedPairs is the whitelist of pairs for each exchange. An exchange
+	// that is not present in the map has no restriction.
+	AllowedPairs map[string][]string
+	// PairRules are the quantity rules for each pair.
+	PairRules map[string]PairRules
+}
+
+// Validate validates the risk limits.
+func (rl RiskLimits) Validate() error {
+	if rl.MaxOrderNotional < 0 {
+		return fmt.Errorf("%w: negative max order notional", ErrInvalidRiskLimits)
+	}
+
+	if rl.MaxOpenOrders < 0 {
+		return fmt.Errorf("%w: negative max open orders", ErrInvalidRiskLimits)
+	}
+
+	for asset, limit := range rl.MaxPositions {
+		if limit < 0 {
+			return fmt.Errorf("%w: negative max position for %q", ErrInvalidRiskLimits, asset)
+		}
+	}
+
+	for p, rules := range rl.PairRules {
+		if rules.MinQuantity < 0 || rules.StepSize < 0 {
+			return fmt.Errorf("%w: negative quantity rules for %q", ErrInvalidRiskLimits, p)
+		}
+	}
+
+	return nil
+}
+
+// CheckOrder checks that the order respects the risk limits, considering the
+// forwardtest state and the price at which the order would be executed.
+func (ft Forwardtest) CheckOrder(o order.Order, price float64) error {
+	rl := ft.Risk
+
+	if err := rl.checkAllowedPair(o); err != nil {
+		return err
+	}
+
+	if err := rl.checkPairRules(o); err != nil {
+		return err
+	}
+
+	if rl.MaxOrderNotional > 0 && price*o.Quantity > rl.MaxOrderNotional {
+		return newRiskRejectionError(RiskRuleMaxOrderNotional,
+			"order notional %f is above %f", price*o.Quantity, rl.MaxOrderNotional)
+	}
+
+	if err := rl.checkMaxOpenOrders(ft, o); err != nil {
+		return err
+	}
+
+	return rl.checkMaxPosition(ft, o)
+}
+
+func (rl RiskLimits) checkAllowedPair(o order.Order) error {
+	allowed, ok := rl.AllowedPairs[o.Exchange]
+	if !ok || slices.Contains(allowed, o.Pair) {
+		return nil
+	}
+
+	return newRiskRejectionError(RiskRuleAllowedPairs,
+		"pair %q is not allowed on exchange %q", o.Pair, o.Exchange)
+}
+
+func (rl RiskLimits) checkPairRules(o order.Order) error {
+	rules, ok := rl.PairRules[o.Pair]
+	if !ok {
+		return nil
+	}
+
+	if rules.MinQuantity > 0 && o.Quantity < rules.MinQuantity {
+		return newRiskRejectionError(RiskRuleMinQuantity,
+			"quantity %f is below %f on %q", o.Quantity, rules.MinQuantity, o.Pair)
+	}
+
+	if rules.StepSize > 0 {
+		steps := o.Quantity / rules.StepSize
+		if math.Abs(steps-math.Round(steps)) > stepSizeTolerance {
+			return newRiskRejectionError(RiskRuleStepSize,
+				"quantity %f is not a multiple of %f on %q", o.Quantity, rules.StepSize, o.Pair)
+		}
+	}
+
+	return nil
+}
+
+func (rl RiskLimits) checkMaxPosition(ft Forwardtest, o order.Order) error {
+	// Only buy orders increase the position on the base asset
+	if len(rl.MaxPositions) == 0 || o.Side != order.SideIsBuy {
+		return nil
+	}
+
+	base, _, err := pair.ParsePair(o.Pair)
+	if err != nil {
+		return fmt.Errorf("error when parsing order pair symbol: %w", err)
+	}
+
+	limit, ok := rl.MaxPositions[base]
+	if !ok {
+		return nil
+	}
+
+	position := ft.Accounts[o.Exchange].Balances[base] + o.Quantity
+	if position > limit {
+		return newRiskRejectionError(RiskRuleMaxPosition,
+			"position on %s would be %f, above %f", base, position, limit)
+	}
+
+	return nil
+}
+
+func (rl RiskLimits) checkMaxOpenOrders(ft Forwardtest, o order.Order) error {
+	if rl.MaxOpenOrders == 0 {
+		return nil
+	}
+
+	positions := ft.openPositions()
+
+	// Reducing an open position never opens a new order
+	key := openPositionKey{Exchange: o.Exchange, Pair: o.Pair}
+	if p := positions[key]; (o.Side == order.SideIsSell && p.Quantity > 0) ||
+		(o.Side == order.SideIsBuy && p.Quantity < 0) {
+		return nil
+	}
+
+	count := 0
+	for _, p := range positions {
+		count += p.Orders
+	}
+	if count >= rl.MaxOpenOrders {
+		return newRiskRejectionError(RiskRuleMaxOpenOrders,
+			"already %d open orders", count)
+	}
+
+	return nil
+}
+
+// openPositionKey identifies the position built by orders on a pair of an
+// exchange.
+type openPositionKey struct {
+	Exchange string
+	Pair     string
+}
+
+// openPosition is the position built by the orders of a pair since it was
+// last closed.
+type openPosition struct {
+	// Quantity is the net quantity of base asset bought by the orders.
+	Quantity float64
+	// Orders is the number of orders since the position was last closed.
+	Orders int
+}
+
+// openPositions returns the open positions built by the forwardtest orders.
+func (ft Forwardtest) openPositions() map[openPositionKey]openPosition {
+	positions := make(map[openPositionKey]openPosition)
+	for _, o := range ft.Orders {
+		key := openPositionKey{Exchange: o.Exchange, Pair: o.Pair}
+		p := positions[key]
+
+		if o.Side == order.SideIsBuy {
+			p.Quantity += o.Quantity
+		} else {
+			p.Quantity -= o.Quantity
+		}
+		p.Orders++
+
+		if math.Abs(p.Quantity) <= flatPositionTolerance {
+			delete(positions, key)
+			continue
+		}
+		positions[key] = p
+	}
+
+	return positions
+}
@@ -0,0 +1,184 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestRiskSuite(t *testing.T) {
+	suite.Run(t, new(RiskSuite))
+}
+
+type RiskSuite struct {
+	suite.Suite
+}
+
+func (suite *RiskSuite) TestValidate() {
+	suite.Require().NoError(RiskLimits{}.Validate())
+	suite.Require().ErrorIs(RiskLimits{MaxOrderNotional: -1}.Validate(), ErrInvalidRiskLimits)
+	suite.Require().ErrorIs(RiskLimits{MaxOpenOrders: -1}.Validate(), ErrInvalidRiskLimits)
+	suite.Require().ErrorIs(RiskLimits{
+		MaxPositions: map[string]float64{"BTC": -1},
+	}.Validate(), ErrInvalidRiskLimits)
+	suite.Require().ErrorIs(RiskLimits{
+		PairRules: map[string]PairRules{"BTC-USDT": {StepSize: -0.1}},
+	}.Validate(), ErrInvalidRiskLimits)
+}
+
+func (suite *RiskSuite) TestCheckOrder() {
+	buy := order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 0.5,
+	}
+	sell := buy
+	sell.Side = order.SideIsSell
+	eth := buy
+	eth.Pair = "ETH-USDT"
+
+	cases := []struct {
+		Name     string
+		Limits   RiskLimits
+		Orders   []order.Order
+		Order    order.Order
+		Expected RiskRule
+	}{
+		{
+			Name:   "no limits",
+			Limits: RiskLimits{},
+			Order:  buy,
+		},
+		{
+			Name:     "notional above maximum",
+			Limits:   RiskLimits{MaxOrderNotional: 1000},
+			Order:    buy,
+			Expected: RiskRuleMaxOrderNotional,
+		},
+		{
+			Name:   "notional under maximum",
+			Limits: RiskLimits{MaxOrderNotional: 10000},
+			Order:  buy,
+		},
+		{
+			Name:     "position above maximum",
+			Limits:   RiskLimits{MaxPositions: map[string]float64{"BTC": 1}},
+			Order:    buy,
+			Expected: RiskRuleMaxPosition,
+		},
+		{
+			Name:   "sell is not limited by position",
+			Limits: RiskLimits{MaxPositions: map[string]float64{"BTC": 1}},
+			Order: order.Order{
+				Type: order.TypeIsMarket, Side: order.SideIsSell,
+				Exchange: "binance", Pair: "BTC-USDT", Quantity: 0.5,
+			},
+		},
+		{
+			Name:     "too many open orders",
+			Limits:   RiskLimits{MaxOpenOrders: 2},
+			Orders:   []order.Order{buy, eth},
+			Order:    buy,
+			Expected: RiskRuleMaxOpenOrders,
+		},
+		{
+			Name:   "reducing an open position with too many open orders",
+			Limits: RiskLimits{MaxOpenOrders: 2},
+			Orders: []order.Order{buy, eth},
+			Order:  sell,
+		},
+		{
+			Name:   "closed positions have no open orders",
+			Limits: RiskLimits{MaxOpenOrders: 2},
+			Orders: []order.Order{buy, eth, sell},
+			Order:  buy,
+		},
+		{
+			Name:     "pair not allowed",
+			Limits:   RiskLimits{AllowedPairs: map[string][]string{"binance": {"ETH-USDT"}}},
+			Order:    buy,
+			Expected: RiskRuleAllowedPairs,
+		},
+		{
+			Name:   "pair allowed on another exchange",
+			Limits: RiskLimits{AllowedPairs: map[string][]string{"kucoin": {"ETH-USDT"}}},
+			Order:  buy,
+		},
+		{
+			Name:     "quantity below minimum",
+			Limits:   RiskLimits{PairRules: map[string]PairRules{"BTC-USDT": {MinQuantity: 1}}},
+			Order:    buy,
+			Expected: RiskRuleMinQuantity,
+		},
+		{
+			Name:     "quantity not a multiple of the step",
+			Limits:   RiskLimits{PairRules: map[string]PairRules{"BTC-USDT": {StepSize: 0.3}}},
+			Order:    buy,
+			Expected: RiskRuleStepSize,
+		},
+		{
+			Name:   "quantity multiple of the step",
+			Limits: RiskLimits{PairRules: map[string]PairRules{"BTC-USDT": {StepSize: 0.1}}},
+			Order:  buy,
+		},
+	}
+
+	for _, c := range cases {
+		ft := Forwardtest{
+			Accounts: map[string]account.Account{
+				"binance": {Balances: map[string]float64{"BTC": 0.75, "USDT": 100000}},
+			},
+			Orders: c.Orders,
+			Risk:   c.Limits,
+		}
+
+		err := ft.CheckOrder(c.Order, 20000)
+		if c.Expected == "" {
+			suite.Require().NoError(err, c.Name)
+			continue
+		}
+
+		var riskErr *RiskRejectionError
+		suite.Require().True(errors.As(err, &riskErr), c.Name)
+		suite.Require().Equal(c.Expected, riskErr.Rule, c.Name)
+		suite.Require().ErrorIs(err, ErrOrderRejected, c.Name)
+	}
+}
+
+func (suite *RiskSuite) TestExecuteOrderRiskLimits() {
+	ft := Forwardtest{
+		Accounts: map[string]account.Account{
+			"binance": {Balances: map[string]float64{"USDT": 100000}},
+		},
+		Risk: RiskLimits{MaxPositions: map[string]float64{"BTC": 1}},
+	}
+	buy := order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 0.75,
+	}
+
+	// First order is filled
+	suite.Require().NoError(ft.ExecuteOrder(buy, Quote{Last: 20000, Time: time.Unix(60, 0)}))
+
+	// Second order would exceed the position built by the first fill
+	err := ft.ExecuteOrder(buy, Quote{Last: 20000, Time: time.Unix(60, 0)})
+	var riskErr *RiskRejectionError
+	suite.Require().True(errors.As(err, &riskErr))
+	suite.Require().Equal(RiskRuleMaxPosition, riskErr.Rule)
+
+	// The rejected order is not applied
+	suite.Require().Len(ft.Orders, 1)
+	suite.Require().Equal(0.75, ft.Accounts["binance"].Balances["BTC"])
+}
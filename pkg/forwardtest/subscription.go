@@ -0,0 +1,108 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/period"
+	"github.com/cryptellation/runtime"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrInvalidSubscription is returned when the subscription is invalid.
+	ErrInvalidSubscription = errors.New("invalid subscription")
+	// ErrSubscriptionConflict is returned when subscribing to a pair that is
+	// already subscribed with another delivery mode.
+	ErrSubscriptionConflict = errors.New("pair already subscribed with another delivery mode")
+)
+
+// Subscription is a price subscription of a forwardtest.
+type Subscription struct {
+	ForwardtestID uuid.UUID
+	Exchange      string
+	Pair          string
+	CreatedAt     time.Time
+	LastTickAt    *time.Time
+	TickCount     int64
+	// Period is the period of the closed candlesticks delivered to the
+	// OnNewCandlestickCallback. If nil, ticks are delivered to the
+	// OnNewPricesCallback of the forwardtest.
+	Period                   *period.Symbol
+	OnNewCandlestickCallback *runtime.CallbackWorkflow
+	// StaleSince is the time at which the price feed has been detected as
+	// stale. It is nil while ticks are received.
+	StaleSince *time.Time
+	// LastPrice is the price of the last accepted tick, or the close price of
+	// the last closed candlestick.
+	LastPrice float64
+	// RejectedTickCount is the number of ticks rejected by the tick filter.
+	RejectedTickCount int64
+	// OrderBook is the last order book snapshot received for the pair, if any.
+	OrderBook *OrderBook
+}
+
+// Validate validates the subscription.
+func (s Subscription) Validate() error {
+	if s.Exchange == "" || s.Pair == "" {
+		return fmt.Errorf("%w: empty exchange or pair", ErrInvalidSubscription)
+	}
+
+	if s.Period == nil {
+		return nil
+	}
+
+	if err := s.Period.Validate(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSubscription, err)
+	}
+
+	if s.OnNewCandlestickCallback == nil {
+		return fmt.Errorf("%w: no candlestick callback", ErrInvalidSubscription)
+	}
+
+	if err := s.OnNewCandlestickCallback.Validate(); err != nil {
+		return fmt.Errorf("%w: candlestick callback: %w", ErrInvalidSubscription, err)
+	}
+
+	return nil
+}
+
+// DeliversCandlesticks returns true if the subscription delivers closed
+// candlesticks instead of ticks.
+func (s Subscription) DeliversCandlesticks() bool {
+	return s.Period != nil
+}
+
+// SameDelivery returns true if both subscriptions deliver prices the same way.
+func (s Subscription) SameDelivery(other Subscription) bool {
+	if s.Period == nil || other.Period == nil {
+		return s.Period == other.Period
+	}
+	return *s.Period == *other.Period
+}
+
+// LastActivity returns the time of the last tick or closed candlestick
+// received, or the creation time if nothing has been received yet.
+func (s Subscription) LastActivity() time.Time {
+	if s.LastTickAt != nil {
+		return *s.LastTickAt
+	}
+	return s.CreatedAt
+}
+
+// IsStalled returns true if no tick has been received for longer than the
+// timeout. As closed candlesticks are received once per period, the period
+// is added to the timeout of the subscriptions delivering them.
+func (s Subscription) IsStalled(now time.Time, timeout time.Duration) bool {
+	return now.After(s.StalledAt(timeout))
+}
+
+// StalledAt returns the time after which the subscription is stalled if
+// nothing is received in the meantime.
+func (s Subscription) StalledAt(timeout time.Duration) time.Time {
+	if s.Period != nil {
+		timeout += s.Period.Duration()
+	}
+	return s.LastActivity().Add(timeout)
+}
@@ -0,0 +1,78 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/period"
+	"github.com/cryptellation/runtime"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSubscriptionSuite(t *testing.T) {
+	suite.Run(t, new(SubscriptionSuite))
+}
+
+type SubscriptionSuite struct {
+	suite.Suite
+}
+
+func (suite *SubscriptionSuite) TestIsStalled() {
+	created := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	sub := Subscription{CreatedAt: created}
+
+	// Without tick, the creation time is used
+	suite.Require().Equal(created, sub.LastActivity())
+	suite.Require().False(sub.IsStalled(created.Add(time.Minute), 2*time.Minute))
+	suite.Require().True(sub.IsStalled(created.Add(3*time.Minute), 2*time.Minute))
+
+	// With a tick, the last tick time is used
+	lastTick := created.Add(2 * time.Minute)
+	sub.LastTickAt = &lastTick
+	suite.Require().Equal(lastTick, sub.LastActivity())
+	suite.Require().False(sub.IsStalled(created.Add(3*time.Minute), 2*time.Minute))
+
+	// With closed candlesticks, a period is waited for in addition
+	sub.Period = period.M15.Opt()
+	suite.Require().False(sub.IsStalled(lastTick.Add(16*time.Minute), 2*time.Minute))
+	suite.Require().True(sub.IsStalled(lastTick.Add(18*time.Minute), 2*time.Minute))
+	suite.Require().Equal(lastTick.Add(17*time.Minute), sub.StalledAt(2*time.Minute))
+}
+
+func (suite *SubscriptionSuite) TestValidate() {
+	sub := Subscription{Exchange: "binance", Pair: "ETH-USDT"}
+	suite.Require().NoError(sub.Validate())
+	suite.Require().False(sub.DeliversCandlesticks())
+
+	// Missing pair
+	suite.Require().ErrorIs(Subscription{Exchange: "binance"}.Validate(), ErrInvalidSubscription)
+
+	// Period without callback
+	sub.Period = period.M1.Opt()
+	suite.Require().ErrorIs(sub.Validate(), ErrInvalidSubscription)
+
+	// Period with callback
+	sub.OnNewCandlestickCallback = &runtime.CallbackWorkflow{Name: "candles", TaskQueueName: "queue"}
+	suite.Require().NoError(sub.Validate())
+	suite.Require().True(sub.DeliversCandlesticks())
+
+	// Invalid period
+	invalid := period.Symbol("M2")
+	sub.Period = &invalid
+	suite.Require().ErrorIs(sub.Validate(), ErrInvalidSubscription)
+}
+
+func (suite *SubscriptionSuite) TestSameDelivery() {
+	ticks := Subscription{}
+	m1 := Subscription{Period: period.M1.Opt()}
+	h1 := Subscription{Period: period.H1.Opt()}
+
+	suite.Require().True(ticks.SameDelivery(Subscription{}))
+	suite.Require().True(m1.SameDelivery(Subscription{Period: period.M1.Opt()}))
+	suite.Require().False(ticks.SameDelivery(m1))
+	suite.Require().False(m1.SameDelivery(ticks))
+	suite.Require().False(m1.SameDelivery(h1))
+}
@@ -0,0 +1,76 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/runtime"
+	"github.com/google/uuid"
+	"github.com/robfig/cron"
+)
+
+var (
+	// ErrInvalidTimer is returned when a timer is invalid.
+	ErrInvalidTimer = errors.New("invalid timer")
+	// ErrTimerAlreadyExists is returned when registering a timer with the
+	// name of an existing timer of the forwardtest.
+	ErrTimerAlreadyExists = errors.New("timer already exists")
+)
+
+// MinTimerInterval is the minimum interval of a timer.
+const MinTimerInterval = time.Second
+
+// Timer is a recurring timer of a forwardtest, executing a callback at a
+// fixed interval or on a cron schedule.
+type Timer struct {
+	ForwardtestID uuid.UUID
+	// Name identifies the timer on the forwardtest.
+	Name string
+	// Interval is the duration between two executions of the callback.
+	Interval time.Duration
+	// Cron is the cron schedule of the callback, in the standard 5 fields
+	// format or with a descriptor like "@hourly". It is exclusive with Interval.
+	Cron      string
+	Callback  runtime.CallbackWorkflow
+	CreatedAt time.Time
+}
+
+// Validate validates the timer.
+func (t Timer) Validate() error {
+	if t.Name == "" {
+		return fmt.Errorf("%w: empty name", ErrInvalidTimer)
+	}
+
+	switch {
+	case t.Interval != 0 && t.Cron != "":
+		return fmt.Errorf("%w: both interval and cron are set", ErrInvalidTimer)
+	case t.Cron != "":
+		if err := validateCron(t.Cron); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidTimer, err)
+		}
+	case t.Interval < MinTimerInterval:
+		return fmt.Errorf("%w: interval %s is below %s", ErrInvalidTimer, t.Interval, MinTimerInterval)
+	}
+
+	if err := t.Callback.Validate(); err != nil {
+		return fmt.Errorf("%w: callback: %w", ErrInvalidTimer, err)
+	}
+
+	return nil
+}
+
+// IsCron returns true if the timer is executed on a cron schedule.
+func (t Timer) IsCron() bool {
+	return t.Cron != ""
+}
+
+// validateCron parses a cron expression the way Temporal schedules it: with
+// the standard 5 fields or a descriptor like "@hourly" or "@every 1h".
+func validateCron(spec string) error {
+	if _, err := cron.ParseStandard(spec); err != nil {
+		return fmt.Errorf("cron %q: %w", spec, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,57 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/runtime"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTimerSuite(t *testing.T) {
+	suite.Run(t, new(TimerSuite))
+}
+
+type TimerSuite struct {
+	suite.Suite
+}
+
+func (suite *TimerSuite) TestValidate() {
+	callback := runtime.CallbackWorkflow{
+		Name:          "timer-callback",
+		TaskQueueName: "timer-queue",
+	}
+
+	cases := []struct {
+		Name  string
+		Timer Timer
+		Valid bool
+	}{
+		{Name: "interval", Timer: Timer{Name: "t", Interval: time.Hour, Callback: callback}, Valid: true},
+		{Name: "cron", Timer: Timer{Name: "t", Cron: "0 * * * *", Callback: callback}, Valid: true},
+		{Name: "descriptor", Timer: Timer{Name: "t", Cron: "@every 1h", Callback: callback}, Valid: true},
+		{Name: "no name", Timer: Timer{Interval: time.Hour, Callback: callback}},
+		{Name: "no schedule", Timer: Timer{Name: "t", Callback: callback}},
+		{Name: "short interval", Timer: Timer{Name: "t", Interval: time.Millisecond, Callback: callback}},
+		{Name: "both", Timer: Timer{Name: "t", Interval: time.Hour, Cron: "0 * * * *", Callback: callback}},
+		{Name: "invalid cron", Timer: Timer{Name: "t", Cron: "0 * *", Callback: callback}},
+		{Name: "invalid cron values", Timer: Timer{Name: "t", Cron: "61 * * * *", Callback: callback}},
+		{Name: "unknown descriptor", Timer: Timer{Name: "t", Cron: "@sometimes", Callback: callback}},
+		{Name: "no callback", Timer: Timer{Name: "t", Interval: time.Hour}},
+	}
+
+	for _, c := range cases {
+		err := c.Timer.Validate()
+		if c.Valid {
+			suite.Require().NoError(err, c.Name)
+		} else {
+			suite.Require().ErrorIs(err, ErrInvalidTimer, c.Name)
+		}
+	}
+
+	suite.Require().True(Timer{Cron: "@hourly"}.IsCron())
+	suite.Require().False(Timer{Interval: time.Hour}.IsCron())
+}
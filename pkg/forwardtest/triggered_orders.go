@@ -0,0 +1,83 @@
+package forwardtest
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrNoTriggeringTick is returned when an order returned by a callback is
+	// on a pair without tick in the ticks delivered to the callback.
+	ErrNoTriggeringTick = errors.New("no triggering tick")
+)
+
+// ExecuteTriggeredOrders executes the orders returned by a callback, each one
+// at the price of the last delivered tick of its pair, and records this tick
+// as the trigger of the order. The order books are taken from the
+// subscriptions when the execution policy uses them.
+//
+// The orders are executed atomically: if one of them fails, none of them is
+// applied to the forwardtest.
+func (ft *Forwardtest) ExecuteTriggeredOrders(
+	orders []order.Order,
+	ticks []tick.Tick,
+	subs []Subscription,
+) error {
+	updated := *ft
+	updated.Accounts = copyAccounts(ft.Accounts)
+	updated.Orders = slices.Clone(ft.Orders)
+	updated.OrderTriggers = maps.Clone(ft.OrderTriggers)
+	if updated.OrderTriggers == nil {
+		updated.OrderTriggers = make(map[uuid.UUID]tick.Tick, len(orders))
+	}
+
+	for i, o := range orders {
+		trigger, ok := lastTick(ticks, o.Exchange, o.Pair)
+		if !ok {
+			return fmt.Errorf("order %d: %w on %s %s", i, ErrNoTriggeringTick, o.Exchange, o.Pair)
+		}
+
+		err := updated.ExecuteOrder(o, Quote{
+			Last: trigger.Price,
+			Book: subscriptionOrderBook(subs, o.Exchange, o.Pair),
+			Time: trigger.Time,
+		})
+		if err != nil {
+			return fmt.Errorf("order %d: %w", i, err)
+		}
+
+		updated.OrderTriggers[o.ID] = trigger
+	}
+
+	*ft = updated
+	return nil
+}
+
+// lastTick returns the last tick of the pair in the ticks sorted by time.
+func lastTick(ticks []tick.Tick, exchange, pair string) (tick.Tick, bool) {
+	for i := len(ticks) - 1; i >= 0; i-- {
+		if ticks[i].Exchange == exchange && ticks[i].Pair == pair {
+			return ticks[i], true
+		}
+	}
+
+	return tick.Tick{}, false
+}
+
+// subscriptionOrderBook returns the order book of the subscription to the
+// pair, if any.
+func subscriptionOrderBook(subs []Subscription, exchange, pair string) *OrderBook {
+	for _, sub := range subs {
+		if sub.Exchange == exchange && sub.Pair == pair {
+			return sub.OrderBook
+		}
+	}
+
+	return nil
+}
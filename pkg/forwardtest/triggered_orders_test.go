@@ -0,0 +1,107 @@
+//go:build unit
+// +build unit
+
+package forwardtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestTriggeredOrdersSuite(t *testing.T) {
+	suite.Run(t, new(TriggeredOrdersSuite))
+}
+
+type TriggeredOrdersSuite struct {
+	suite.Suite
+}
+
+func (suite *TriggeredOrdersSuite) newForwardtest() Forwardtest {
+	return Forwardtest{
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"USDT": 1000}},
+		},
+	}
+}
+
+func (suite *TriggeredOrdersSuite) newOrder(pair string, quantity float64) order.Order {
+	return order.Order{
+		ID:       uuid.New(),
+		Type:     order.TypeIsMarket,
+		Exchange: "exchange",
+		Pair:     pair,
+		Side:     order.SideIsBuy,
+		Quantity: quantity,
+	}
+}
+
+func (suite *TriggeredOrdersSuite) TestExecuteTriggeredOrders() {
+	ft := suite.newForwardtest()
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	ticks := []tick.Tick{
+		{Time: start, Exchange: "exchange", Pair: "ETH-USDT", Price: 100},
+		{Time: start.Add(time.Second), Exchange: "exchange", Pair: "BTC-USDT", Price: 200},
+		{Time: start.Add(2 * time.Second), Exchange: "exchange", Pair: "ETH-USDT", Price: 110},
+	}
+	eth, btc := suite.newOrder("ETH-USDT", 1), suite.newOrder("BTC-USDT", 2)
+
+	err := ft.ExecuteTriggeredOrders([]order.Order{eth, btc}, ticks, nil)
+	suite.Require().NoError(err)
+
+	// Orders are executed at the last tick of their pair
+	suite.Require().Len(ft.Orders, 2)
+	suite.Require().Equal(110.0, ft.Orders[0].Price)
+	suite.Require().Equal(ticks[2].Time, *ft.Orders[0].ExecutionTime)
+	suite.Require().Equal(200.0, ft.Orders[1].Price)
+	suite.Require().Equal(ticks[1].Time, *ft.Orders[1].ExecutionTime)
+	suite.Require().Equal(490.0, ft.Accounts["exchange"].Balances["USDT"])
+
+	// Triggering ticks are recorded
+	suite.Require().Equal(ticks[2], ft.OrderTriggers[eth.ID])
+	suite.Require().Equal(ticks[1], ft.OrderTriggers[btc.ID])
+}
+
+func (suite *TriggeredOrdersSuite) TestExecuteTriggeredOrdersAtomic() {
+	ft := suite.newForwardtest()
+	ticks := []tick.Tick{{Time: time.Now(), Exchange: "exchange", Pair: "ETH-USDT", Price: 100}}
+
+	// Second order exceeds the balance
+	orders := []order.Order{suite.newOrder("ETH-USDT", 1), suite.newOrder("ETH-USDT", 100)}
+	err := ft.ExecuteTriggeredOrders(orders, ticks, nil)
+	suite.Require().Error(err)
+
+	// Nothing is applied
+	suite.Require().Empty(ft.Orders)
+	suite.Require().Empty(ft.OrderTriggers)
+	suite.Require().Equal(1000.0, ft.Accounts["exchange"].Balances["USDT"])
+}
+
+func (suite *TriggeredOrdersSuite) TestExecuteTriggeredOrdersWithoutTick() {
+	ft := suite.newForwardtest()
+	ticks := []tick.Tick{{Time: time.Now(), Exchange: "exchange", Pair: "ETH-USDT", Price: 100}}
+
+	err := ft.ExecuteTriggeredOrders([]order.Order{suite.newOrder("BTC-USDT", 1)}, ticks, nil)
+	suite.Require().ErrorIs(err, ErrNoTriggeringTick)
+	suite.Require().Empty(ft.Orders)
+}
+
+func (suite *TriggeredOrdersSuite) TestExecuteTriggeredOrdersWithOrderBook() {
+	ft := suite.newForwardtest()
+	ft.Execution = ExecutionPolicy{Mode: ExecutionModeBidAsk}
+	ticks := []tick.Tick{{Time: time.Now(), Exchange: "exchange", Pair: "ETH-USDT", Price: 100}}
+	subs := []Subscription{{
+		Exchange:  "exchange",
+		Pair:      "ETH-USDT",
+		OrderBook: &OrderBook{Asks: []BookLevel{{Price: 105}}},
+	}}
+
+	err := ft.ExecuteTriggeredOrders([]order.Order{suite.newOrder("ETH-USDT", 1)}, ticks, subs)
+	suite.Require().NoError(err)
+	suite.Require().Equal(105.0, ft.Orders[0].Price)
+}
@@ -0,0 +1,37 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ArchiveForwardtestWorkflow sets the archive flag of a forwardtest. Archived
+// forwardtests are kept in database but hidden from the default listing.
+func (wf *workflows) ArchiveForwardtestWorkflow(
+	ctx workflow.Context,
+	params api.ArchiveForwardtestWorkflowParams,
+) (api.ArchiveForwardtestWorkflowResults, error) {
+	// Read forwardtest from database
+	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.ArchiveForwardtestWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// Save forwardtest with the new flag
+	ft.Archived = params.Archived
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.UpdateForwardtestActivity, db.UpdateForwardtestActivityParams{
+			Forwardtest: ft,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.ArchiveForwardtestWorkflowResults{},
+			fmt.Errorf("updating forwardtest archive flag: %w", err)
+	}
+
+	return api.ArchiveForwardtestWorkflowResults{}, nil
+}
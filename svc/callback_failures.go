@@ -0,0 +1,216 @@
+package svc
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// withCallbackRetries returns the child workflow options of a callback with
+// the retry policy of the forwardtest for this callback, if any.
+func withCallbackRetries(
+	opts workflow.ChildWorkflowOptions,
+	ft forwardtest.Forwardtest,
+	kind forwardtest.CallbackKind,
+) workflow.ChildWorkflowOptions {
+	rp, ok := ft.Failures.RetryPolicy(kind)
+	if !ok {
+		return opts
+	}
+
+	// The execution timeout covers all the attempts, so it is applied to
+	// each attempt instead
+	opts.WorkflowRunTimeout = opts.WorkflowExecutionTimeout
+	opts.WorkflowExecutionTimeout = 0
+	opts.RetryPolicy = &temporal.RetryPolicy{
+		InitialInterval:    rp.InitialInterval,
+		BackoffCoefficient: rp.BackoffCoefficient,
+		MaximumInterval:    rp.MaxInterval,
+		MaximumAttempts:    int32(min(rp.MaxAttempts, math.MaxInt32)),
+	}
+
+	return opts
+}
+
+// callbackSucceeded resets the consecutive callback failures of the
+// forwardtest, if there are some.
+func (wf *workflows) callbackSucceeded(ctx workflow.Context, ft *forwardtest.Forwardtest) {
+	if ft.ConsecutiveCallbackFailures == 0 {
+		return
+	}
+
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ResetCallbackFailuresActivity, db.ResetCallbackFailuresActivityParams{
+			ForwardtestID: ft.ID,
+		}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Could not reset callback failures",
+			"forwardtest_id", ft.ID.String(),
+			"error", err.Error())
+		return
+	}
+
+	ft.ConsecutiveCallbackFailures = 0
+}
+
+// resetCallbackFailures resets the consecutive callback failures of a
+// forwardtest that is not running, if there are some.
+func (wf *workflows) resetCallbackFailures(ctx workflow.Context, ft forwardtest.Forwardtest) error {
+	if ft.ConsecutiveCallbackFailures == 0 {
+		return nil
+	}
+
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ResetCallbackFailuresActivity, db.ResetCallbackFailuresActivityParams{
+			ForwardtestID: ft.ID,
+		}).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("resetting callback failures: %w", err)
+	}
+
+	return nil
+}
+
+// callbackFailed records the failure of a callback of the forwardtest,
+// executes its OnErrorCallback and stops the forwardtest when its consecutive
+// failures reach the threshold of its failure policy.
+func (wf *workflows) callbackFailed(
+	ctx workflow.Context,
+	ft *forwardtest.Forwardtest,
+	kind forwardtest.CallbackKind,
+	cbErr error,
+) {
+	logger := workflow.GetLogger(ctx)
+	callbackErr := forwardtest.CallbackError{
+		ForwardtestID: ft.ID,
+		Time:          workflow.Now(ctx),
+		Callback:      kind,
+		Error:         cbErr.Error(),
+	}
+	wf.recordEvent(ctx, forwardtest.NewCallbackErrorEvent(callbackErr))
+
+	var res db.RecordCallbackErrorActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.RecordCallbackErrorActivity, db.RecordCallbackErrorActivityParams{
+			Error: callbackErr,
+		}).Get(ctx, &res)
+	if err != nil {
+		logger.Error("Could not record callback error",
+			"forwardtest_id", ft.ID.String(),
+			"callback", kind.String(),
+			"error", err.Error())
+		return
+	}
+	ft.ConsecutiveCallbackFailures = res.ConsecutiveFailures
+
+	stopping := ft.Failures.ShouldStop(res.ConsecutiveFailures)
+	wf.notifyCallbackError(ctx, *ft, callbackErr, stopping)
+
+	if stopping {
+		logger.Warn("Stopping forwardtest after consecutive callback failures",
+			"forwardtest_id", ft.ID.String(),
+			"failures", res.ConsecutiveFailures)
+		wf.autoStop(ctx, ft.ID, forwardtest.StatusReasonCallbackFailures)
+	}
+}
+
+// notifyCallbackError starts the OnErrorCallback workflow of the forwardtest,
+// if any, without waiting for its completion.
+func (wf *workflows) notifyCallbackError(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	callbackErr forwardtest.CallbackError,
+	stopping bool,
+) {
+	callback := ft.OptionalCallbacks.OnErrorCallback
+	if callback == nil {
+		return
+	}
+
+	opts := workflow.ChildWorkflowOptions{
+		// Unique identifier for this child workflow execution
+		WorkflowID: fmt.Sprintf("forwardtest-%s-on-error-%s-%s",
+			ft.ID.String(), callbackErr.Callback, callbackErr.Time.Format(time.RFC3339Nano)),
+		// Task queue where the child workflow will be executed
+		TaskQueue: callback.TaskQueueName,
+		// Maximum time allowed for the child workflow to complete
+		WorkflowExecutionTimeout: time.Second * 30,
+		// Do not wait for the callback to complete
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+	}
+
+	// Check if the timeout is set
+	if callback.ExecutionTimeout > 0 {
+		opts.WorkflowExecutionTimeout = callback.ExecutionTimeout
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		callback.Name,
+		api.OnErrorCallbackWorkflowParams{
+			Context: runtime.Context{
+				ID:              ft.ID,
+				Mode:            runtime.ModeForwardtest,
+				Now:             callbackErr.Time,
+				ParentTaskQueue: workflow.GetInfo(ctx).TaskQueueName,
+			},
+			Error:               callbackErr,
+			ConsecutiveFailures: ft.ConsecutiveCallbackFailures,
+			Stopping:            stopping,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		workflow.GetLogger(ctx).Error("Could not start OnErrorCallback workflow",
+			"forwardtest_id", ft.ID.String(),
+			"callback", callbackErr.Callback.String(),
+			"error", err)
+	}
+}
+
+// autoStop starts the StopForwardtestWorkflow of the forwardtest with the
+// reason, without waiting for its completion as it stops the workflows
+// delivering prices and timers. It can be called on each failure past the
+// threshold: calls during a stop are rejected as already started and later
+// ones find the forwardtest finished.
+func (wf *workflows) autoStop(ctx workflow.Context, forwardtestID uuid.UUID, reason string) {
+	opts := workflow.ChildWorkflowOptions{
+		// Only one automatic stop at a time per forwardtest
+		WorkflowID: autoStopWorkflowID(forwardtestID),
+		TaskQueue:  workflow.GetInfo(ctx).TaskQueueName,
+		// Keep stopping the forwardtest even if this workflow is cancelled
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+		// A forwardtest run again can be stopped again
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		api.StopForwardtestWorkflowName,
+		api.StopForwardtestWorkflowParams{
+			ForwardtestID: forwardtestID,
+			Reason:        reason,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		workflow.GetLogger(ctx).Error("Could not stop forwardtest",
+			"forwardtest_id", forwardtestID.String(),
+			"reason", reason,
+			"error", err)
+	}
+}
+
+// autoStopWorkflowID returns the workflow ID of the automatic stop of a
+// forwardtest.
+func autoStopWorkflowID(forwardtestID uuid.UUID) string {
+	return fmt.Sprintf("forwardtest-%s-auto-stop", forwardtestID.String())
+}
@@ -0,0 +1,144 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/workflow"
+)
+
+// applyCallbackOrders executes atomically the orders returned by a callback
+// at the price of the ticks that triggered it. Rejected orders are notified
+// and logged without failing, as the callback itself has succeeded: only
+// the errors preventing to process the orders are returned. If one of the
+// orders has already been executed, by a previous attempt for example, none
+// of them is executed again. If the forwardtest has been updated
+// concurrently, the orders are executed again on its new state.
+func (wf *workflows) applyCallbackOrders(
+	ctx workflow.Context,
+	forwardtestID uuid.UUID,
+	ticks []tick.Tick,
+	orders []order.Order,
+) error {
+	logger := workflow.GetLogger(ctx)
+
+	// Set the missing order IDs
+	for i := range orders {
+		if orders[i].ID != uuid.Nil {
+			continue
+		}
+
+		id, err := newOrderID(ctx)
+		if err != nil {
+			return err
+		}
+		orders[i].ID = id
+	}
+
+	// Read the forwardtest again as the callback may have changed it
+	ft, err := wf.readForwardtestFromDB(ctx, forwardtestID)
+	if err != nil {
+		return fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	subs, err := wf.listOrderBookSubscriptions(ctx, ft)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		saved, err := wf.saveCallbackOrders(ctx, ft, ticks, orders, subs)
+		if err != nil || saved {
+			return err
+		}
+
+		// Read the forwardtest again to check which update came first
+		ft, err = wf.reloadForwardtestOrders(ctx, forwardtestID, attempt)
+		if err != nil {
+			return fmt.Errorf("saving orders returned by callback: %w", err)
+		}
+		if hasAnyOrder(ft, orders) {
+			logger.Info("Orders returned by callback already executed",
+				"forwardtest_id", forwardtestID.String(),
+				"orders", len(orders))
+			return nil
+		}
+	}
+}
+
+// saveCallbackOrders executes the orders returned by a callback on the
+// forwardtest, saves them and notifies the strategy of the fills or the
+// rejections. It returns false if the orders have not been saved because of a
+// concurrent update.
+func (wf *workflows) saveCallbackOrders(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	ticks []tick.Tick,
+	orders []order.Order,
+	subs []forwardtest.Subscription,
+) (bool, error) {
+	if err := ft.ExecuteTriggeredOrders(orders, ticks, subs); err != nil {
+		workflow.GetLogger(ctx).Warn("Orders returned by callback rejected",
+			"forwardtest_id", ft.ID.String(),
+			"orders", len(orders),
+			"error", err.Error())
+		for _, o := range orders {
+			wf.notifyOrderUpdate(ctx, ft, api.OrderUpdateStatusRejected, o, err)
+		}
+		return true, nil
+	}
+
+	// Save forwardtest to database
+	saved, err := wf.saveOrders(ctx, ft, orders)
+	if err != nil {
+		return false, fmt.Errorf("saving orders returned by callback: %w", err)
+	} else if !saved {
+		return false, nil
+	}
+
+	// Notify the strategy of the fills
+	for _, o := range ft.Orders[len(ft.Orders)-len(orders):] {
+		wf.notifyOrderUpdate(ctx, ft, api.OrderUpdateStatusFilled, o, nil)
+	}
+
+	return true, nil
+}
+
+// hasAnyOrder checks if one of the orders is saved on the forwardtest.
+func hasAnyOrder(ft forwardtest.Forwardtest, orders []order.Order) bool {
+	for _, o := range orders {
+		if _, ok := ft.Order(o.ID); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// listOrderBookSubscriptions lists the subscriptions of the forwardtest when
+// its execution policy uses their order books.
+func (wf *workflows) listOrderBookSubscriptions(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+) ([]forwardtest.Subscription, error) {
+	if !ft.Execution.UsesOrderBook() {
+		return nil, nil
+	}
+
+	var res db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: ft.ID,
+		}).Get(ctx, &res)
+	if err != nil {
+		return nil, fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	return res.Subscriptions, nil
+}
@@ -0,0 +1,63 @@
+package svc
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/preflight"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// errPreflightUnavailable is returned when a preflight is requested on a
+// worker without preflight checks.
+var errPreflightUnavailable = errors.New("callbacks preflight is not available on this worker")
+
+// checkCallbacks checks that a worker is polling the task queue of each
+// callback. It returns an UnreachableCallbacks error listing the callbacks
+// whose task queue has no worker.
+func (wf *workflows) checkCallbacks(ctx workflow.Context, callbacks []forwardtest.NamedCallback) error {
+	if wf.preflight == nil {
+		return errPreflightUnavailable
+	}
+
+	taskQueues := make([]string, 0, len(callbacks))
+	for _, c := range callbacks {
+		taskQueues = append(taskQueues, c.Callback.TaskQueueName)
+	}
+
+	var res preflight.CheckTaskQueuesActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, preflight.DefaultActivityOptions()),
+		wf.preflight.CheckTaskQueuesActivity, preflight.CheckTaskQueuesActivityParams{
+			TaskQueues: taskQueues,
+		}).Get(ctx, &res)
+	if err != nil {
+		return fmt.Errorf("checking callbacks task queues: %w", err)
+	} else if len(res.Unreachable) == 0 {
+		return nil
+	}
+
+	// List the callbacks on the unreachable task queues
+	unreachable := make(map[string]bool, len(res.Unreachable))
+	for _, tq := range res.Unreachable {
+		unreachable[tq] = true
+	}
+
+	names := make([]string, 0, len(callbacks))
+	descriptions := make([]string, 0, len(callbacks))
+	for _, c := range callbacks {
+		if unreachable[c.Callback.TaskQueueName] {
+			names = append(names, c.Name)
+			descriptions = append(descriptions,
+				fmt.Sprintf("%s (task queue %q)", c.Name, c.Callback.TaskQueueName))
+		}
+	}
+
+	return temporal.NewNonRetryableApplicationError(
+		"unreachable callbacks, no worker is polling their task queue: "+strings.Join(descriptions, ", "),
+		api.UnreachableCallbacksErrorType, nil, names)
+}
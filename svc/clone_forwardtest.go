@@ -0,0 +1,54 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CloneForwardtestWorkflow creates a new forwardtest from the configuration of
+// an existing one and saves it to the database.
+func (wf *workflows) CloneForwardtestWorkflow(
+	ctx workflow.Context,
+	params api.CloneForwardtestWorkflowParams,
+) (api.CloneForwardtestWorkflowResults, error) {
+	// Read forwardtest from database
+	parent, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.CloneForwardtestWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// Create the clone and save it to database
+	ft, err := parent.Clone(forwardtest.CloneParams{
+		Accounts:          params.Accounts,
+		Callbacks:         params.Callbacks,
+		OptionalCallbacks: params.OptionalCallbacks,
+		Risk:              params.Risk,
+		Delivery:          params.Delivery,
+		FeedHealth:        params.FeedHealth,
+		TickFilter:        params.TickFilter,
+		Execution:         params.Execution,
+		Failures:          params.Failures,
+		RecordTicks:       params.RecordTicks,
+	})
+	if err != nil {
+		return api.CloneForwardtestWorkflowResults{}, fmt.Errorf("cloning forwardtest: %w", err)
+	}
+
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.CreateForwardtestActivity, db.CreateForwardtestActivityParams{
+			Forwardtest: ft,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.CloneForwardtestWorkflowResults{}, fmt.Errorf("adding forwardtest to db: %w", err)
+	}
+
+	return api.CloneForwardtestWorkflowResults{
+		ID: ft.ID,
+	}, nil
+}
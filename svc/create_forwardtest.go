@@ -20,8 +20,16 @@ func (wf *workflows) CreateForwardtestWorkflow(
 	}
 
 	payload := forwardtest.NewForwardtestParams{
-		Accounts:  params.Accounts,
-		Callbacks: params.Callbacks,
+		Accounts:          params.Accounts,
+		Callbacks:         params.Callbacks,
+		OptionalCallbacks: params.OptionalCallbacks,
+		Risk:              params.Risk,
+		Delivery:          params.Delivery,
+		FeedHealth:        params.FeedHealth,
+		TickFilter:        params.TickFilter,
+		Execution:         params.Execution,
+		Failures:          params.Failures,
+		RecordTicks:       params.RecordTicks,
 	}
 
 	// Create new forwardtest and save it to database
@@ -30,6 +38,13 @@ func (wf *workflows) CreateForwardtestWorkflow(
 		return api.CreateForwardtestWorkflowResults{}, fmt.Errorf("creating a new forwardtest from request: %w", err)
 	}
 
+	// Check that the callbacks can be executed, if requested
+	if params.Preflight {
+		if err := wf.checkCallbacks(ctx, forwardtest.ListCallbacks(ft.Callbacks, ft.OptionalCallbacks)); err != nil {
+			return api.CreateForwardtestWorkflowResults{}, err
+		}
+	}
+
 	err = workflow.ExecuteActivity(
 		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
 		wf.db.CreateForwardtestActivity, db.CreateForwardtestActivityParams{
@@ -1,16 +1,29 @@
 package svc
 
 import (
+	"errors"
 	"fmt"
 
 	candlesticksapi "github.com/cryptellation/candlesticks/api"
+	"github.com/cryptellation/candlesticks/pkg/candlestick"
 	"github.com/cryptellation/candlesticks/pkg/period"
 	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
 	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime/order"
 	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/workflow"
 )
 
+// maxOrdersSaveAttempts is the number of times orders are executed on a
+// forwardtest before giving up when it keeps being updated concurrently.
+const maxOrdersSaveAttempts = 10
+
+// ErrOrdersSaveConflict is the error when orders could not be saved as the
+// forwardtest kept being updated concurrently.
+var ErrOrdersSaveConflict = errors.New("forwardtest updated concurrently")
+
 // CreateForwardtestOrderWorkflow creates a new forwardtest order and saves it to the database.
 func (wf *workflows) CreateForwardtestOrderWorkflow(
 	ctx workflow.Context,
@@ -18,8 +31,13 @@ func (wf *workflows) CreateForwardtestOrderWorkflow(
 ) (api.CreateForwardtestOrderWorkflowResults, error) {
 	logger := workflow.GetLogger(ctx)
 
+	// Set the missing order ID
 	if params.Order.ID == uuid.Nil {
-		params.Order.ID = uuid.New()
+		id, err := newOrderID(ctx)
+		if err != nil {
+			return api.CreateForwardtestOrderWorkflowResults{}, err
+		}
+		params.Order.ID = id
 	}
 
 	logger.Debug("Creating order on forwardtest",
@@ -33,11 +51,192 @@ func (wf *workflows) CreateForwardtestOrderWorkflow(
 			fmt.Errorf("could not read forwardtest from db: %w", err)
 	}
 
-	// Get candlestick for order validation
+	// Return the order already executed by a previous submission, if any
+	if o, ok := ft.Order(params.Order.ID); ok {
+		return duplicateOrder(ctx, ft.ID, o), nil
+	}
+
+	// Check the price feed of the order pair
+	if err := wf.checkOrderFeed(ctx, ft, params.Order); errors.Is(err, forwardtest.ErrFeedStale) {
+		return api.CreateForwardtestOrderWorkflowResults{}, wf.rejectOrder(ctx, ft, params.Order, err)
+	} else if err != nil {
+		return api.CreateForwardtestOrderWorkflowResults{}, err
+	}
+
+	// Get the quote to execute the order
+	quote, err := wf.getOrderQuote(ctx, ft, params.Order)
+	if err != nil {
+		return api.CreateForwardtestOrderWorkflowResults{}, err
+	}
+
+	logger.Info("Adding order to forwardtest",
+		"order", params.Order,
+		"forwardtest", params.ForwardtestID.String())
+	return wf.executeOrder(ctx, ft, params.Order, quote)
+}
+
+// executeOrder executes an order on the forwardtest, saves it and notifies the
+// strategy of the fill. If the forwardtest has been updated concurrently, it is
+// read again and the order executed on its new state. If a concurrent
+// submission of the same order has been saved first, its order is returned
+// instead.
+func (wf *workflows) executeOrder(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	o order.Order,
+	quote forwardtest.Quote,
+) (api.CreateForwardtestOrderWorkflowResults, error) {
+	for attempt := 1; ; attempt++ {
+		if err := ft.ExecuteOrder(o, quote); err != nil {
+			return api.CreateForwardtestOrderWorkflowResults{}, wf.rejectOrder(ctx, ft, o, err)
+		}
+		filled := ft.Orders[len(ft.Orders)-1]
+
+		// Save forwardtest to database
+		saved, err := wf.saveOrders(ctx, ft, []order.Order{filled})
+		if err != nil {
+			return api.CreateForwardtestOrderWorkflowResults{}, err
+		} else if saved {
+			wf.notifyOrderUpdate(ctx, ft, api.OrderUpdateStatusFilled, filled, nil)
+			return api.CreateForwardtestOrderWorkflowResults{
+				Order: filled,
+			}, nil
+		}
+
+		// Read the forwardtest again to check which update came first
+		ft, err = wf.reloadForwardtestOrders(ctx, ft.ID, attempt)
+		if err != nil {
+			return api.CreateForwardtestOrderWorkflowResults{}, err
+		}
+		if existing, ok := ft.Order(o.ID); ok {
+			return duplicateOrder(ctx, ft.ID, existing), nil
+		}
+	}
+}
+
+// reloadForwardtestOrders reads the forwardtest again after its orders have not
+// been saved, and fails once the save has been attempted too many times.
+func (wf *workflows) reloadForwardtestOrders(
+	ctx workflow.Context,
+	forwardtestID uuid.UUID,
+	attempt int,
+) (forwardtest.Forwardtest, error) {
+	if attempt >= maxOrdersSaveAttempts {
+		return forwardtest.Forwardtest{}, fmt.Errorf("%w: %d attempts", ErrOrdersSaveConflict, attempt)
+	}
+
+	ft, err := wf.readForwardtestFromDB(ctx, forwardtestID)
+	if err != nil {
+		return forwardtest.Forwardtest{}, fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	return ft, nil
+}
+
+// saveOrders saves the forwardtest with its executed orders. It returns false
+// without saving anything if one of the orders has already been saved or if
+// the forwardtest has been updated since it was read, the database ensuring
+// that an order is only executed once and that no update is overwritten.
+func (wf *workflows) saveOrders(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	orders []order.Order,
+) (bool, error) {
+	ids := make([]uuid.UUID, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+
+	var res db.SaveForwardtestOrdersActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.SaveForwardtestOrdersActivity, db.SaveForwardtestOrdersActivityParams{
+			Forwardtest: ft,
+			OrderIDs:    ids,
+		}).Get(ctx, &res)
+	if err != nil {
+		return false, fmt.Errorf("saving orders to db: %w", err)
+	}
+
+	return res.Saved, nil
+}
+
+// duplicateOrder returns the result of an order already executed by a
+// previous submission.
+func duplicateOrder(
+	ctx workflow.Context,
+	forwardtestID uuid.UUID,
+	o order.Order,
+) api.CreateForwardtestOrderWorkflowResults {
+	workflow.GetLogger(ctx).Info("Order already executed on forwardtest",
+		"order_id", o.ID.String(),
+		"forwardtest_id", forwardtestID.String())
+	return api.CreateForwardtestOrderWorkflowResults{
+		Order:     o,
+		Duplicate: true,
+	}
+}
+
+// newOrderID generates the ID of an order submitted without ID, recorded in
+// the workflow history to stay deterministic.
+func newOrderID(ctx workflow.Context) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := workflow.SideEffect(ctx, func(workflow.Context) any {
+		return uuid.New()
+	}).Get(&id)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("generating order ID: %w", err)
+	}
+
+	return id, nil
+}
+
+// getOrderQuote gets the quote used to execute an order: the price of the
+// current candlestick and, for bid/ask execution, the order book of the pair.
+func (wf *workflows) getOrderQuote(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	o order.Order,
+) (forwardtest.Quote, error) {
+	cs, err := wf.getOrderCandlestick(ctx, ft, o)
+	if err != nil {
+		return forwardtest.Quote{}, err
+	}
+
+	book, err := wf.getOrderBook(ctx, ft, o)
+	if err != nil {
+		return forwardtest.Quote{}, err
+	}
+
+	// Replays are filled at the time of the last replayed tick
+	t := workflow.Now(ctx)
+	if ft.IsReplay() {
+		t = cs.Time
+	}
+
+	return forwardtest.Quote{
+		Last: cs.Close,
+		Book: book,
+		Time: t,
+	}, nil
+}
+
+// getOrderCandlestick gets the candlestick used to execute an order: the
+// current candlestick from the candlesticks service or, on a replay, the last
+// replayed tick of the pair.
+func (wf *workflows) getOrderCandlestick(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	o order.Order,
+) (candlestick.Candlestick, error) {
+	if ft.IsReplay() {
+		return wf.getReplayCandlestick(ctx, ft, o)
+	}
+
 	now := workflow.Now(ctx)
 	csRes, err := wf.candlesticks.ListCandlesticks(ctx, candlesticksapi.ListCandlesticksWorkflowParams{
-		Exchange: params.Order.Exchange,
-		Pair:     params.Order.Pair,
+		Exchange: o.Exchange,
+		Pair:     o.Pair,
 		Period:   period.M1,
 		Start:    &now,
 		End:      &now,
@@ -46,28 +245,54 @@ func (wf *workflows) CreateForwardtestOrderWorkflow(
 		TaskQueue: candlesticksapi.WorkerTaskQueueName,
 	})
 	if err != nil {
-		return api.CreateForwardtestOrderWorkflowResults{},
-			fmt.Errorf("could not get candlesticks from service: %w", err)
+		return candlestick.Candlestick{}, fmt.Errorf("could not get candlesticks from service: %w", err)
 	}
 
-	cs := csRes.List[0]
+	return csRes.List[0], nil
+}
 
-	logger.Info("Adding order to forwardtest",
-		"order", params.Order,
-		"forwardtest", params.ForwardtestID.String())
-	if err := ft.AddOrder(params.Order, cs); err != nil {
-		return api.CreateForwardtestOrderWorkflowResults{}, err
+// getReplayCandlestick gets a candlestick from the last replayed tick of the
+// order pair.
+func (wf *workflows) getReplayCandlestick(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	o order.Order,
+) (candlestick.Candlestick, error) {
+	sub, err := wf.getSubscription(ctx, ft.ID, o.Exchange, o.Pair)
+	if err != nil {
+		return candlestick.Candlestick{}, err
 	}
 
-	// Save forwardtest to database
-	err = workflow.ExecuteActivity(
-		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
-		wf.db.UpdateForwardtestActivity, db.UpdateForwardtestActivityParams{
-			Forwardtest: ft,
-		}).Get(ctx, nil)
-	if err != nil {
-		return api.CreateForwardtestOrderWorkflowResults{}, err
+	if sub != nil && sub.LastTickAt != nil {
+		return candlestick.Candlestick{
+			Time:  *sub.LastTickAt,
+			Open:  sub.LastPrice,
+			High:  sub.LastPrice,
+			Low:   sub.LastPrice,
+			Close: sub.LastPrice,
+		}, nil
+	}
+
+	return candlestick.Candlestick{}, fmt.Errorf("%w on %s %s", ErrNoReplayedPrice, o.Exchange, o.Pair)
+}
+
+// toOrderError converts an error from an order execution into a typed,
+// non-retryable application error when the order was rejected by the risk
+// checks or could not be filled.
+func toOrderError(err error) error {
+	if errors.Is(err, forwardtest.ErrFeedStale) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), api.FeedStaleErrorType, err)
+	}
+
+	if errors.Is(err, forwardtest.ErrInsufficientDepth) {
+		return temporal.NewNonRetryableApplicationError(err.Error(), api.InsufficientDepthErrorType, err)
+	}
+
+	var riskErr *forwardtest.RiskRejectionError
+	if !errors.As(err, &riskErr) {
+		return err
 	}
 
-	return api.CreateForwardtestOrderWorkflowResults{}, nil
+	return temporal.NewNonRetryableApplicationError(
+		riskErr.Error(), api.RiskRejectedErrorType, riskErr, riskErr.Rule)
 }
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/ticks/pkg/tick"
 	"github.com/google/uuid"
 	"go.temporal.io/sdk/temporal"
 	"go.temporal.io/sdk/worker"
@@ -47,7 +48,9 @@ const ListForwardtestsActivityName = "ListForwardtestsActivity"
 
 type (
 	// ListForwardtestsActivityParams is the parameters for the ListForwardtestsActivity.
-	ListForwardtestsActivityParams struct{}
+	ListForwardtestsActivityParams struct {
+		IncludeArchived bool
+	}
 
 	// ListForwardtestsActivityResult is the result for the ListForwardtestsActivity.
 	ListForwardtestsActivityResult struct {
@@ -68,6 +71,29 @@ type (
 	UpdateForwardtestActivityResult struct{}
 )
 
+// SaveForwardtestOrdersActivityName is the name of the SaveForwardtestOrdersActivity.
+const SaveForwardtestOrdersActivityName = "SaveForwardtestOrdersActivity"
+
+type (
+	// SaveForwardtestOrdersActivityParams is the parameters for the SaveForwardtestOrdersActivity.
+	SaveForwardtestOrdersActivityParams struct {
+		// Forwardtest is the forwardtest with the executed orders. Its
+		// UpdatedAt is the one read from the database, nothing being saved if
+		// the forwardtest has been updated since.
+		Forwardtest forwardtest.Forwardtest
+		// OrderIDs are the IDs of the executed orders.
+		OrderIDs []uuid.UUID
+	}
+
+	// SaveForwardtestOrdersActivityResult is the result for the SaveForwardtestOrdersActivity.
+	SaveForwardtestOrdersActivityResult struct {
+		// Saved is false if one of the orders is already saved on the
+		// forwardtest or if it has been updated since it was read, in which
+		// case nothing is saved.
+		Saved bool
+	}
+)
+
 // DeleteForwardtestActivityName is the name of the DeleteForwardtestActivity.
 const DeleteForwardtestActivityName = "DeleteForwardtestActivity"
 
@@ -81,6 +107,336 @@ type (
 	DeleteForwardtestActivityResult struct{}
 )
 
+// CreateSubscriptionActivityName is the name of the CreateSubscriptionActivity.
+const CreateSubscriptionActivityName = "CreateSubscriptionActivity"
+
+type (
+	// CreateSubscriptionActivityParams is the parameters for the CreateSubscriptionActivity.
+	CreateSubscriptionActivityParams struct {
+		Subscription forwardtest.Subscription
+	}
+
+	// CreateSubscriptionActivityResult is the result for the CreateSubscriptionActivity.
+	CreateSubscriptionActivityResult struct{}
+)
+
+// ListSubscriptionsActivityName is the name of the ListSubscriptionsActivity.
+const ListSubscriptionsActivityName = "ListSubscriptionsActivity"
+
+type (
+	// ListSubscriptionsActivityParams is the parameters for the ListSubscriptionsActivity.
+	ListSubscriptionsActivityParams struct {
+		ForwardtestID uuid.UUID
+	}
+
+	// ListSubscriptionsActivityResult is the result for the ListSubscriptionsActivity.
+	ListSubscriptionsActivityResult struct {
+		Subscriptions []forwardtest.Subscription
+	}
+)
+
+// GetSubscriptionActivityName is the name of the GetSubscriptionActivity.
+const GetSubscriptionActivityName = "GetSubscriptionActivity"
+
+type (
+	// GetSubscriptionActivityParams is the parameters for the GetSubscriptionActivity.
+	GetSubscriptionActivityParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+	}
+
+	// GetSubscriptionActivityResult is the result for the GetSubscriptionActivity.
+	GetSubscriptionActivityResult struct {
+		// Subscription is the subscription, nil if it does not exist.
+		Subscription *forwardtest.Subscription
+	}
+)
+
+// UpdateSubscriptionLastTickActivityName is the name of the UpdateSubscriptionLastTickActivity.
+const UpdateSubscriptionLastTickActivityName = "UpdateSubscriptionLastTickActivity"
+
+type (
+	// UpdateSubscriptionLastTickActivityParams is the parameters for the UpdateSubscriptionLastTickActivity.
+	UpdateSubscriptionLastTickActivityParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+		Time          time.Time
+		Price         float64
+	}
+
+	// UpdateSubscriptionLastTickActivityResult is the result for the UpdateSubscriptionLastTickActivity.
+	UpdateSubscriptionLastTickActivityResult struct {
+		// Subscription is the updated subscription, nil if it does not exist.
+		Subscription *forwardtest.Subscription
+	}
+)
+
+// DeleteSubscriptionActivityName is the name of the DeleteSubscriptionActivity.
+const DeleteSubscriptionActivityName = "DeleteSubscriptionActivity"
+
+type (
+	// DeleteSubscriptionActivityParams is the parameters for the DeleteSubscriptionActivity.
+	DeleteSubscriptionActivityParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+	}
+
+	// DeleteSubscriptionActivityResult is the result for the DeleteSubscriptionActivity.
+	DeleteSubscriptionActivityResult struct{}
+)
+
+// MarkSubscriptionStaleActivityName is the name of the MarkSubscriptionStaleActivity.
+const MarkSubscriptionStaleActivityName = "MarkSubscriptionStaleActivity"
+
+type (
+	// MarkSubscriptionStaleActivityParams is the parameters for the MarkSubscriptionStaleActivity.
+	MarkSubscriptionStaleActivityParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+		Since         time.Time
+	}
+
+	// MarkSubscriptionStaleActivityResult is the result for the MarkSubscriptionStaleActivity.
+	MarkSubscriptionStaleActivityResult struct {
+		// Marked is true if the subscription was not already marked as stale.
+		Marked bool
+	}
+)
+
+// UpdateSubscriptionOrderBookActivityName is the name of the UpdateSubscriptionOrderBookActivity.
+const UpdateSubscriptionOrderBookActivityName = "UpdateSubscriptionOrderBookActivity"
+
+type (
+	// UpdateSubscriptionOrderBookActivityParams is the parameters for the UpdateSubscriptionOrderBookActivity.
+	UpdateSubscriptionOrderBookActivityParams struct {
+		ForwardtestID uuid.UUID
+		Exchange      string
+		Pair          string
+		OrderBook     forwardtest.OrderBook
+	}
+
+	// UpdateSubscriptionOrderBookActivityResult is the result for the UpdateSubscriptionOrderBookActivity.
+	UpdateSubscriptionOrderBookActivityResult struct {
+		// Updated is false if the subscription does not exist or already has
+		// a more recent order book.
+		Updated bool
+	}
+)
+
+// QuarantineTickActivityName is the name of the QuarantineTickActivity.
+const QuarantineTickActivityName = "QuarantineTickActivity"
+
+type (
+	// QuarantineTickActivityParams is the parameters for the QuarantineTickActivity.
+	QuarantineTickActivityParams struct {
+		Tick forwardtest.QuarantinedTick
+	}
+
+	// QuarantineTickActivityResult is the result for the QuarantineTickActivity.
+	QuarantineTickActivityResult struct{}
+)
+
+// ListQuarantinedTicksActivityName is the name of the ListQuarantinedTicksActivity.
+const ListQuarantinedTicksActivityName = "ListQuarantinedTicksActivity"
+
+type (
+	// ListQuarantinedTicksActivityParams is the parameters for the ListQuarantinedTicksActivity.
+	ListQuarantinedTicksActivityParams struct {
+		ForwardtestID uuid.UUID
+		// Limit is the maximum number of ticks returned, the most recent first.
+		// Zero means no limit.
+		Limit int
+	}
+
+	// ListQuarantinedTicksActivityResult is the result for the ListQuarantinedTicksActivity.
+	ListQuarantinedTicksActivityResult struct {
+		Ticks []forwardtest.QuarantinedTick
+	}
+)
+
+// RecordTickActivityName is the name of the RecordTickActivity.
+const RecordTickActivityName = "RecordTickActivity"
+
+type (
+	// RecordTickActivityParams is the parameters for the RecordTickActivity.
+	RecordTickActivityParams struct {
+		ForwardtestID uuid.UUID
+		Tick          tick.Tick
+	}
+
+	// RecordTickActivityResult is the result for the RecordTickActivity.
+	RecordTickActivityResult struct{}
+)
+
+// ListRecordedTicksActivityName is the name of the ListRecordedTicksActivity.
+const ListRecordedTicksActivityName = "ListRecordedTicksActivity"
+
+type (
+	// ListRecordedTicksActivityParams is the parameters for the ListRecordedTicksActivity.
+	ListRecordedTicksActivityParams struct {
+		ForwardtestID uuid.UUID
+		// Offset is the number of recorded ticks to skip.
+		Offset int
+		// Limit is the maximum number of ticks returned. Zero means no limit.
+		Limit int
+	}
+
+	// ListRecordedTicksActivityResult is the result for the ListRecordedTicksActivity.
+	ListRecordedTicksActivityResult struct {
+		Ticks []tick.Tick
+	}
+)
+
+// CreateTimerActivityName is the name of the CreateTimerActivity.
+const CreateTimerActivityName = "CreateTimerActivity"
+
+type (
+	// CreateTimerActivityParams is the parameters for the CreateTimerActivity.
+	CreateTimerActivityParams struct {
+		Timer forwardtest.Timer
+	}
+
+	// CreateTimerActivityResult is the result for the CreateTimerActivity.
+	CreateTimerActivityResult struct {
+		// Created is false if a timer with the same name already exists.
+		Created bool
+	}
+)
+
+// ListTimersActivityName is the name of the ListTimersActivity.
+const ListTimersActivityName = "ListTimersActivity"
+
+type (
+	// ListTimersActivityParams is the parameters for the ListTimersActivity.
+	ListTimersActivityParams struct {
+		ForwardtestID uuid.UUID
+	}
+
+	// ListTimersActivityResult is the result for the ListTimersActivity.
+	ListTimersActivityResult struct {
+		Timers []forwardtest.Timer
+	}
+)
+
+// DeleteTimerActivityName is the name of the DeleteTimerActivity.
+const DeleteTimerActivityName = "DeleteTimerActivity"
+
+type (
+	// DeleteTimerActivityParams is the parameters for the DeleteTimerActivity.
+	DeleteTimerActivityParams struct {
+		ForwardtestID uuid.UUID
+		Name          string
+	}
+
+	// DeleteTimerActivityResult is the result for the DeleteTimerActivity.
+	DeleteTimerActivityResult struct{}
+)
+
+// RecordCallbackErrorActivityName is the name of the RecordCallbackErrorActivity.
+const RecordCallbackErrorActivityName = "RecordCallbackErrorActivity"
+
+type (
+	// RecordCallbackErrorActivityParams is the parameters for the RecordCallbackErrorActivity.
+	RecordCallbackErrorActivityParams struct {
+		Error forwardtest.CallbackError
+	}
+
+	// RecordCallbackErrorActivityResult is the result for the RecordCallbackErrorActivity.
+	RecordCallbackErrorActivityResult struct {
+		// ConsecutiveFailures is the number of consecutive callback failures
+		// of the forwardtest, including this one.
+		ConsecutiveFailures int
+	}
+)
+
+// ResetCallbackFailuresActivityName is the name of the ResetCallbackFailuresActivity.
+const ResetCallbackFailuresActivityName = "ResetCallbackFailuresActivity"
+
+type (
+	// ResetCallbackFailuresActivityParams is the parameters for the ResetCallbackFailuresActivity.
+	ResetCallbackFailuresActivityParams struct {
+		ForwardtestID uuid.UUID
+	}
+
+	// ResetCallbackFailuresActivityResult is the result for the ResetCallbackFailuresActivity.
+	ResetCallbackFailuresActivityResult struct{}
+)
+
+// ListCallbackErrorsActivityName is the name of the ListCallbackErrorsActivity.
+const ListCallbackErrorsActivityName = "ListCallbackErrorsActivity"
+
+type (
+	// ListCallbackErrorsActivityParams is the parameters for the ListCallbackErrorsActivity.
+	ListCallbackErrorsActivityParams struct {
+		ForwardtestID uuid.UUID
+		// Limit is the maximum number of errors returned, the most recent
+		// first. Zero means no limit.
+		Limit int
+	}
+
+	// ListCallbackErrorsActivityResult is the result for the ListCallbackErrorsActivity.
+	ListCallbackErrorsActivityResult struct {
+		Errors []forwardtest.CallbackError
+	}
+)
+
+// RecordEventActivityName is the name of the RecordEventActivity.
+const RecordEventActivityName = "RecordEventActivity"
+
+type (
+	// RecordEventActivityParams is the parameters for the RecordEventActivity.
+	RecordEventActivityParams struct {
+		Event forwardtest.Event
+	}
+
+	// RecordEventActivityResult is the result for the RecordEventActivity.
+	RecordEventActivityResult struct {
+		// ID is the cursor of the recorded event, or of the event it has
+		// been coalesced into.
+		ID int64
+	}
+)
+
+// ListEventsActivityName is the name of the ListEventsActivity.
+const ListEventsActivityName = "ListEventsActivity"
+
+type (
+	// ListEventsActivityParams is the parameters for the ListEventsActivity.
+	ListEventsActivityParams struct {
+		ForwardtestID uuid.UUID
+		// After is the cursor after which the events are listed, the oldest
+		// first. Zero lists the events from the start.
+		After int64
+		// Limit is the maximum number of events returned. Zero means no limit.
+		Limit int
+	}
+
+	// ListEventsActivityResult is the result for the ListEventsActivity.
+	ListEventsActivityResult struct {
+		Events []forwardtest.Event
+	}
+)
+
+// PruneEventsActivityName is the name of the PruneEventsActivity.
+const PruneEventsActivityName = "PruneEventsActivity"
+
+type (
+	// PruneEventsActivityParams is the parameters for the PruneEventsActivity.
+	PruneEventsActivityParams struct {
+		// Retention is the duration the events are kept after being recorded.
+		Retention time.Duration
+	}
+
+	// PruneEventsActivityResult is the result for the PruneEventsActivity.
+	PruneEventsActivityResult struct {
+		Deleted int64
+	}
+)
+
 // DB is the interface for the database activities.
 type DB interface {
 	Register(w worker.Worker)
@@ -101,10 +457,100 @@ type DB interface {
 		ctx context.Context,
 		params UpdateForwardtestActivityParams,
 	) (UpdateForwardtestActivityResult, error)
+	SaveForwardtestOrdersActivity(
+		ctx context.Context,
+		params SaveForwardtestOrdersActivityParams,
+	) (SaveForwardtestOrdersActivityResult, error)
 	DeleteForwardtestActivity(
 		ctx context.Context,
 		params DeleteForwardtestActivityParams,
 	) (DeleteForwardtestActivityResult, error)
+
+	CreateSubscriptionActivity(
+		ctx context.Context,
+		params CreateSubscriptionActivityParams,
+	) (CreateSubscriptionActivityResult, error)
+	ListSubscriptionsActivity(
+		ctx context.Context,
+		params ListSubscriptionsActivityParams,
+	) (ListSubscriptionsActivityResult, error)
+	GetSubscriptionActivity(
+		ctx context.Context,
+		params GetSubscriptionActivityParams,
+	) (GetSubscriptionActivityResult, error)
+	UpdateSubscriptionLastTickActivity(
+		ctx context.Context,
+		params UpdateSubscriptionLastTickActivityParams,
+	) (UpdateSubscriptionLastTickActivityResult, error)
+	DeleteSubscriptionActivity(
+		ctx context.Context,
+		params DeleteSubscriptionActivityParams,
+	) (DeleteSubscriptionActivityResult, error)
+	MarkSubscriptionStaleActivity(
+		ctx context.Context,
+		params MarkSubscriptionStaleActivityParams,
+	) (MarkSubscriptionStaleActivityResult, error)
+	UpdateSubscriptionOrderBookActivity(
+		ctx context.Context,
+		params UpdateSubscriptionOrderBookActivityParams,
+	) (UpdateSubscriptionOrderBookActivityResult, error)
+
+	QuarantineTickActivity(
+		ctx context.Context,
+		params QuarantineTickActivityParams,
+	) (QuarantineTickActivityResult, error)
+	ListQuarantinedTicksActivity(
+		ctx context.Context,
+		params ListQuarantinedTicksActivityParams,
+	) (ListQuarantinedTicksActivityResult, error)
+
+	RecordTickActivity(
+		ctx context.Context,
+		params RecordTickActivityParams,
+	) (RecordTickActivityResult, error)
+	ListRecordedTicksActivity(
+		ctx context.Context,
+		params ListRecordedTicksActivityParams,
+	) (ListRecordedTicksActivityResult, error)
+
+	CreateTimerActivity(
+		ctx context.Context,
+		params CreateTimerActivityParams,
+	) (CreateTimerActivityResult, error)
+	ListTimersActivity(
+		ctx context.Context,
+		params ListTimersActivityParams,
+	) (ListTimersActivityResult, error)
+	DeleteTimerActivity(
+		ctx context.Context,
+		params DeleteTimerActivityParams,
+	) (DeleteTimerActivityResult, error)
+
+	RecordCallbackErrorActivity(
+		ctx context.Context,
+		params RecordCallbackErrorActivityParams,
+	) (RecordCallbackErrorActivityResult, error)
+	ResetCallbackFailuresActivity(
+		ctx context.Context,
+		params ResetCallbackFailuresActivityParams,
+	) (ResetCallbackFailuresActivityResult, error)
+	ListCallbackErrorsActivity(
+		ctx context.Context,
+		params ListCallbackErrorsActivityParams,
+	) (ListCallbackErrorsActivityResult, error)
+
+	RecordEventActivity(
+		ctx context.Context,
+		params RecordEventActivityParams,
+	) (RecordEventActivityResult, error)
+	ListEventsActivity(
+		ctx context.Context,
+		params ListEventsActivityParams,
+	) (ListEventsActivityResult, error)
+	PruneEventsActivity(
+		ctx context.Context,
+		params PruneEventsActivityParams,
+	) (PruneEventsActivityResult, error)
 }
 
 // DefaultActivityOptions returns the default database activities options.
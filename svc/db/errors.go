@@ -3,6 +3,8 @@ package db
 import (
 	"errors"
 	"fmt"
+
+	"go.temporal.io/sdk/temporal"
 )
 
 var (
@@ -13,3 +15,21 @@ var (
 	// ErrNotImplemented is returned when the method is not implemented.
 	ErrNotImplemented = errors.New("not implemented")
 )
+
+// NewRecordNotFoundError creates a non-retryable application error whose type
+// is ErrRecordNotFound, so it can be identified after going through Temporal.
+func NewRecordNotFoundError(format string, args ...any) error {
+	return temporal.NewNonRetryableApplicationError(
+		fmt.Sprintf(format, args...), ErrRecordNotFound.Error(), ErrRecordNotFound)
+}
+
+// IsRecordNotFound returns true if the error, returned directly or through
+// an activity, means that the record was not found.
+func IsRecordNotFound(err error) bool {
+	var appErr *temporal.ApplicationError
+	if errors.As(err, &appErr) && appErr.Type() == ErrRecordNotFound.Error() {
+		return true
+	}
+
+	return errors.Is(err, ErrRecordNotFound)
+}
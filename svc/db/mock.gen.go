@@ -50,6 +50,36 @@ func (mr *MockDBMockRecorder) CreateForwardtestActivity(ctx, params interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateForwardtestActivity", reflect.TypeOf((*MockDB)(nil).CreateForwardtestActivity), ctx, params)
 }
 
+// CreateSubscriptionActivity mocks base method.
+func (m *MockDB) CreateSubscriptionActivity(ctx context.Context, params CreateSubscriptionActivityParams) (CreateSubscriptionActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSubscriptionActivity", ctx, params)
+	ret0, _ := ret[0].(CreateSubscriptionActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSubscriptionActivity indicates an expected call of CreateSubscriptionActivity.
+func (mr *MockDBMockRecorder) CreateSubscriptionActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSubscriptionActivity", reflect.TypeOf((*MockDB)(nil).CreateSubscriptionActivity), ctx, params)
+}
+
+// CreateTimerActivity mocks base method.
+func (m *MockDB) CreateTimerActivity(ctx context.Context, params CreateTimerActivityParams) (CreateTimerActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTimerActivity", ctx, params)
+	ret0, _ := ret[0].(CreateTimerActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTimerActivity indicates an expected call of CreateTimerActivity.
+func (mr *MockDBMockRecorder) CreateTimerActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTimerActivity", reflect.TypeOf((*MockDB)(nil).CreateTimerActivity), ctx, params)
+}
+
 // DeleteForwardtestActivity mocks base method.
 func (m *MockDB) DeleteForwardtestActivity(ctx context.Context, params DeleteForwardtestActivityParams) (DeleteForwardtestActivityResult, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +95,81 @@ func (mr *MockDBMockRecorder) DeleteForwardtestActivity(ctx, params interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteForwardtestActivity", reflect.TypeOf((*MockDB)(nil).DeleteForwardtestActivity), ctx, params)
 }
 
+// DeleteSubscriptionActivity mocks base method.
+func (m *MockDB) DeleteSubscriptionActivity(ctx context.Context, params DeleteSubscriptionActivityParams) (DeleteSubscriptionActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSubscriptionActivity", ctx, params)
+	ret0, _ := ret[0].(DeleteSubscriptionActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteSubscriptionActivity indicates an expected call of DeleteSubscriptionActivity.
+func (mr *MockDBMockRecorder) DeleteSubscriptionActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSubscriptionActivity", reflect.TypeOf((*MockDB)(nil).DeleteSubscriptionActivity), ctx, params)
+}
+
+// DeleteTimerActivity mocks base method.
+func (m *MockDB) DeleteTimerActivity(ctx context.Context, params DeleteTimerActivityParams) (DeleteTimerActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTimerActivity", ctx, params)
+	ret0, _ := ret[0].(DeleteTimerActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTimerActivity indicates an expected call of DeleteTimerActivity.
+func (mr *MockDBMockRecorder) DeleteTimerActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTimerActivity", reflect.TypeOf((*MockDB)(nil).DeleteTimerActivity), ctx, params)
+}
+
+// GetSubscriptionActivity mocks base method.
+func (m *MockDB) GetSubscriptionActivity(ctx context.Context, params GetSubscriptionActivityParams) (GetSubscriptionActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSubscriptionActivity", ctx, params)
+	ret0, _ := ret[0].(GetSubscriptionActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSubscriptionActivity indicates an expected call of GetSubscriptionActivity.
+func (mr *MockDBMockRecorder) GetSubscriptionActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSubscriptionActivity", reflect.TypeOf((*MockDB)(nil).GetSubscriptionActivity), ctx, params)
+}
+
+// ListCallbackErrorsActivity mocks base method.
+func (m *MockDB) ListCallbackErrorsActivity(ctx context.Context, params ListCallbackErrorsActivityParams) (ListCallbackErrorsActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCallbackErrorsActivity", ctx, params)
+	ret0, _ := ret[0].(ListCallbackErrorsActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCallbackErrorsActivity indicates an expected call of ListCallbackErrorsActivity.
+func (mr *MockDBMockRecorder) ListCallbackErrorsActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCallbackErrorsActivity", reflect.TypeOf((*MockDB)(nil).ListCallbackErrorsActivity), ctx, params)
+}
+
+// ListEventsActivity mocks base method.
+func (m *MockDB) ListEventsActivity(ctx context.Context, params ListEventsActivityParams) (ListEventsActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListEventsActivity", ctx, params)
+	ret0, _ := ret[0].(ListEventsActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListEventsActivity indicates an expected call of ListEventsActivity.
+func (mr *MockDBMockRecorder) ListEventsActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListEventsActivity", reflect.TypeOf((*MockDB)(nil).ListEventsActivity), ctx, params)
+}
+
 // ListForwardtestsActivity mocks base method.
 func (m *MockDB) ListForwardtestsActivity(ctx context.Context, params ListForwardtestsActivityParams) (ListForwardtestsActivityResult, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +185,111 @@ func (mr *MockDBMockRecorder) ListForwardtestsActivity(ctx, params interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListForwardtestsActivity", reflect.TypeOf((*MockDB)(nil).ListForwardtestsActivity), ctx, params)
 }
 
+// ListQuarantinedTicksActivity mocks base method.
+func (m *MockDB) ListQuarantinedTicksActivity(ctx context.Context, params ListQuarantinedTicksActivityParams) (ListQuarantinedTicksActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListQuarantinedTicksActivity", ctx, params)
+	ret0, _ := ret[0].(ListQuarantinedTicksActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListQuarantinedTicksActivity indicates an expected call of ListQuarantinedTicksActivity.
+func (mr *MockDBMockRecorder) ListQuarantinedTicksActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListQuarantinedTicksActivity", reflect.TypeOf((*MockDB)(nil).ListQuarantinedTicksActivity), ctx, params)
+}
+
+// ListRecordedTicksActivity mocks base method.
+func (m *MockDB) ListRecordedTicksActivity(ctx context.Context, params ListRecordedTicksActivityParams) (ListRecordedTicksActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListRecordedTicksActivity", ctx, params)
+	ret0, _ := ret[0].(ListRecordedTicksActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListRecordedTicksActivity indicates an expected call of ListRecordedTicksActivity.
+func (mr *MockDBMockRecorder) ListRecordedTicksActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListRecordedTicksActivity", reflect.TypeOf((*MockDB)(nil).ListRecordedTicksActivity), ctx, params)
+}
+
+// ListSubscriptionsActivity mocks base method.
+func (m *MockDB) ListSubscriptionsActivity(ctx context.Context, params ListSubscriptionsActivityParams) (ListSubscriptionsActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSubscriptionsActivity", ctx, params)
+	ret0, _ := ret[0].(ListSubscriptionsActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSubscriptionsActivity indicates an expected call of ListSubscriptionsActivity.
+func (mr *MockDBMockRecorder) ListSubscriptionsActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSubscriptionsActivity", reflect.TypeOf((*MockDB)(nil).ListSubscriptionsActivity), ctx, params)
+}
+
+// ListTimersActivity mocks base method.
+func (m *MockDB) ListTimersActivity(ctx context.Context, params ListTimersActivityParams) (ListTimersActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTimersActivity", ctx, params)
+	ret0, _ := ret[0].(ListTimersActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTimersActivity indicates an expected call of ListTimersActivity.
+func (mr *MockDBMockRecorder) ListTimersActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTimersActivity", reflect.TypeOf((*MockDB)(nil).ListTimersActivity), ctx, params)
+}
+
+// MarkSubscriptionStaleActivity mocks base method.
+func (m *MockDB) MarkSubscriptionStaleActivity(ctx context.Context, params MarkSubscriptionStaleActivityParams) (MarkSubscriptionStaleActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkSubscriptionStaleActivity", ctx, params)
+	ret0, _ := ret[0].(MarkSubscriptionStaleActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkSubscriptionStaleActivity indicates an expected call of MarkSubscriptionStaleActivity.
+func (mr *MockDBMockRecorder) MarkSubscriptionStaleActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkSubscriptionStaleActivity", reflect.TypeOf((*MockDB)(nil).MarkSubscriptionStaleActivity), ctx, params)
+}
+
+// PruneEventsActivity mocks base method.
+func (m *MockDB) PruneEventsActivity(ctx context.Context, params PruneEventsActivityParams) (PruneEventsActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PruneEventsActivity", ctx, params)
+	ret0, _ := ret[0].(PruneEventsActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PruneEventsActivity indicates an expected call of PruneEventsActivity.
+func (mr *MockDBMockRecorder) PruneEventsActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PruneEventsActivity", reflect.TypeOf((*MockDB)(nil).PruneEventsActivity), ctx, params)
+}
+
+// QuarantineTickActivity mocks base method.
+func (m *MockDB) QuarantineTickActivity(ctx context.Context, params QuarantineTickActivityParams) (QuarantineTickActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "QuarantineTickActivity", ctx, params)
+	ret0, _ := ret[0].(QuarantineTickActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// QuarantineTickActivity indicates an expected call of QuarantineTickActivity.
+func (mr *MockDBMockRecorder) QuarantineTickActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "QuarantineTickActivity", reflect.TypeOf((*MockDB)(nil).QuarantineTickActivity), ctx, params)
+}
+
 // ReadForwardtestActivity mocks base method.
 func (m *MockDB) ReadForwardtestActivity(ctx context.Context, params ReadForwardtestActivityParams) (ReadForwardtestActivityResult, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +305,51 @@ func (mr *MockDBMockRecorder) ReadForwardtestActivity(ctx, params interface{}) *
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadForwardtestActivity", reflect.TypeOf((*MockDB)(nil).ReadForwardtestActivity), ctx, params)
 }
 
+// RecordCallbackErrorActivity mocks base method.
+func (m *MockDB) RecordCallbackErrorActivity(ctx context.Context, params RecordCallbackErrorActivityParams) (RecordCallbackErrorActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordCallbackErrorActivity", ctx, params)
+	ret0, _ := ret[0].(RecordCallbackErrorActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordCallbackErrorActivity indicates an expected call of RecordCallbackErrorActivity.
+func (mr *MockDBMockRecorder) RecordCallbackErrorActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordCallbackErrorActivity", reflect.TypeOf((*MockDB)(nil).RecordCallbackErrorActivity), ctx, params)
+}
+
+// RecordEventActivity mocks base method.
+func (m *MockDB) RecordEventActivity(ctx context.Context, params RecordEventActivityParams) (RecordEventActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordEventActivity", ctx, params)
+	ret0, _ := ret[0].(RecordEventActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordEventActivity indicates an expected call of RecordEventActivity.
+func (mr *MockDBMockRecorder) RecordEventActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordEventActivity", reflect.TypeOf((*MockDB)(nil).RecordEventActivity), ctx, params)
+}
+
+// RecordTickActivity mocks base method.
+func (m *MockDB) RecordTickActivity(ctx context.Context, params RecordTickActivityParams) (RecordTickActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordTickActivity", ctx, params)
+	ret0, _ := ret[0].(RecordTickActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordTickActivity indicates an expected call of RecordTickActivity.
+func (mr *MockDBMockRecorder) RecordTickActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordTickActivity", reflect.TypeOf((*MockDB)(nil).RecordTickActivity), ctx, params)
+}
+
 // Register mocks base method.
 func (m *MockDB) Register(w worker.Worker) {
 	m.ctrl.T.Helper()
@@ -107,6 +362,36 @@ func (mr *MockDBMockRecorder) Register(w interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Register", reflect.TypeOf((*MockDB)(nil).Register), w)
 }
 
+// ResetCallbackFailuresActivity mocks base method.
+func (m *MockDB) ResetCallbackFailuresActivity(ctx context.Context, params ResetCallbackFailuresActivityParams) (ResetCallbackFailuresActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetCallbackFailuresActivity", ctx, params)
+	ret0, _ := ret[0].(ResetCallbackFailuresActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ResetCallbackFailuresActivity indicates an expected call of ResetCallbackFailuresActivity.
+func (mr *MockDBMockRecorder) ResetCallbackFailuresActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetCallbackFailuresActivity", reflect.TypeOf((*MockDB)(nil).ResetCallbackFailuresActivity), ctx, params)
+}
+
+// SaveForwardtestOrdersActivity mocks base method.
+func (m *MockDB) SaveForwardtestOrdersActivity(ctx context.Context, params SaveForwardtestOrdersActivityParams) (SaveForwardtestOrdersActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveForwardtestOrdersActivity", ctx, params)
+	ret0, _ := ret[0].(SaveForwardtestOrdersActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SaveForwardtestOrdersActivity indicates an expected call of SaveForwardtestOrdersActivity.
+func (mr *MockDBMockRecorder) SaveForwardtestOrdersActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveForwardtestOrdersActivity", reflect.TypeOf((*MockDB)(nil).SaveForwardtestOrdersActivity), ctx, params)
+}
+
 // UpdateForwardtestActivity mocks base method.
 func (m *MockDB) UpdateForwardtestActivity(ctx context.Context, params UpdateForwardtestActivityParams) (UpdateForwardtestActivityResult, error) {
 	m.ctrl.T.Helper()
@@ -121,3 +406,33 @@ func (mr *MockDBMockRecorder) UpdateForwardtestActivity(ctx, params interface{})
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateForwardtestActivity", reflect.TypeOf((*MockDB)(nil).UpdateForwardtestActivity), ctx, params)
 }
+
+// UpdateSubscriptionLastTickActivity mocks base method.
+func (m *MockDB) UpdateSubscriptionLastTickActivity(ctx context.Context, params UpdateSubscriptionLastTickActivityParams) (UpdateSubscriptionLastTickActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscriptionLastTickActivity", ctx, params)
+	ret0, _ := ret[0].(UpdateSubscriptionLastTickActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSubscriptionLastTickActivity indicates an expected call of UpdateSubscriptionLastTickActivity.
+func (mr *MockDBMockRecorder) UpdateSubscriptionLastTickActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriptionLastTickActivity", reflect.TypeOf((*MockDB)(nil).UpdateSubscriptionLastTickActivity), ctx, params)
+}
+
+// UpdateSubscriptionOrderBookActivity mocks base method.
+func (m *MockDB) UpdateSubscriptionOrderBookActivity(ctx context.Context, params UpdateSubscriptionOrderBookActivityParams) (UpdateSubscriptionOrderBookActivityResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSubscriptionOrderBookActivity", ctx, params)
+	ret0, _ := ret[0].(UpdateSubscriptionOrderBookActivityResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSubscriptionOrderBookActivity indicates an expected call of UpdateSubscriptionOrderBookActivity.
+func (mr *MockDBMockRecorder) UpdateSubscriptionOrderBookActivity(ctx, params interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSubscriptionOrderBookActivity", reflect.TypeOf((*MockDB)(nil).UpdateSubscriptionOrderBookActivity), ctx, params)
+}
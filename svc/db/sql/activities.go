@@ -2,6 +2,8 @@ package sql
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
@@ -9,7 +11,7 @@ import (
 	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
-	_ "github.com/lib/pq" // PostGres driver
+	"github.com/lib/pq" // PostGres driver
 	"go.temporal.io/sdk/activity"
 	"go.temporal.io/sdk/worker"
 )
@@ -48,13 +50,91 @@ func (a *Activities) Register(w worker.Worker) {
 		activity.RegisterOptions{Name: db.ListForwardtestsActivityName})
 	w.RegisterActivityWithOptions(a.UpdateForwardtestActivity,
 		activity.RegisterOptions{Name: db.UpdateForwardtestActivityName})
+	w.RegisterActivityWithOptions(a.SaveForwardtestOrdersActivity,
+		activity.RegisterOptions{Name: db.SaveForwardtestOrdersActivityName})
 	w.RegisterActivityWithOptions(a.DeleteForwardtestActivity,
 		activity.RegisterOptions{Name: db.DeleteForwardtestActivityName})
+
+	w.RegisterActivityWithOptions(a.CreateSubscriptionActivity,
+		activity.RegisterOptions{Name: db.CreateSubscriptionActivityName})
+	w.RegisterActivityWithOptions(a.ListSubscriptionsActivity,
+		activity.RegisterOptions{Name: db.ListSubscriptionsActivityName})
+	w.RegisterActivityWithOptions(a.GetSubscriptionActivity,
+		activity.RegisterOptions{Name: db.GetSubscriptionActivityName})
+	w.RegisterActivityWithOptions(a.UpdateSubscriptionLastTickActivity,
+		activity.RegisterOptions{Name: db.UpdateSubscriptionLastTickActivityName})
+	w.RegisterActivityWithOptions(a.DeleteSubscriptionActivity,
+		activity.RegisterOptions{Name: db.DeleteSubscriptionActivityName})
+	w.RegisterActivityWithOptions(a.MarkSubscriptionStaleActivity,
+		activity.RegisterOptions{Name: db.MarkSubscriptionStaleActivityName})
+	w.RegisterActivityWithOptions(a.UpdateSubscriptionOrderBookActivity,
+		activity.RegisterOptions{Name: db.UpdateSubscriptionOrderBookActivityName})
+
+	w.RegisterActivityWithOptions(a.QuarantineTickActivity,
+		activity.RegisterOptions{Name: db.QuarantineTickActivityName})
+	w.RegisterActivityWithOptions(a.ListQuarantinedTicksActivity,
+		activity.RegisterOptions{Name: db.ListQuarantinedTicksActivityName})
+
+	w.RegisterActivityWithOptions(a.RecordTickActivity,
+		activity.RegisterOptions{Name: db.RecordTickActivityName})
+	w.RegisterActivityWithOptions(a.ListRecordedTicksActivity,
+		activity.RegisterOptions{Name: db.ListRecordedTicksActivityName})
+
+	w.RegisterActivityWithOptions(a.CreateTimerActivity,
+		activity.RegisterOptions{Name: db.CreateTimerActivityName})
+	w.RegisterActivityWithOptions(a.ListTimersActivity,
+		activity.RegisterOptions{Name: db.ListTimersActivityName})
+	w.RegisterActivityWithOptions(a.DeleteTimerActivity,
+		activity.RegisterOptions{Name: db.DeleteTimerActivityName})
+
+	w.RegisterActivityWithOptions(a.RecordCallbackErrorActivity,
+		activity.RegisterOptions{Name: db.RecordCallbackErrorActivityName})
+	w.RegisterActivityWithOptions(a.ResetCallbackFailuresActivity,
+		activity.RegisterOptions{Name: db.ResetCallbackFailuresActivityName})
+	w.RegisterActivityWithOptions(a.ListCallbackErrorsActivity,
+		activity.RegisterOptions{Name: db.ListCallbackErrorsActivityName})
+
+	w.RegisterActivityWithOptions(a.RecordEventActivity,
+		activity.RegisterOptions{Name: db.RecordEventActivityName})
+	w.RegisterActivityWithOptions(a.ListEventsActivity,
+		activity.RegisterOptions{Name: db.ListEventsActivityName})
+	w.RegisterActivityWithOptions(a.PruneEventsActivity,
+		activity.RegisterOptions{Name: db.PruneEventsActivityName})
 }
 
 // Reset will reset the database.
 func (a *Activities) Reset(ctx context.Context) error {
-	_, err := a.db.ExecContext(ctx, "DELETE FROM forwardtests")
+	_, err := a.db.ExecContext(ctx, "DELETE FROM forwardtest_events")
+	if err != nil {
+		return fmt.Errorf("deleting forwardtest events rows: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, "DELETE FROM forwardtest_callback_errors")
+	if err != nil {
+		return fmt.Errorf("deleting forwardtest callback errors rows: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, "DELETE FROM forwardtest_timers")
+	if err != nil {
+		return fmt.Errorf("deleting forwardtest timers rows: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, "DELETE FROM forwardtest_ticks")
+	if err != nil {
+		return fmt.Errorf("deleting forwardtest ticks rows: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, "DELETE FROM forwardtest_quarantined_ticks")
+	if err != nil {
+		return fmt.Errorf("deleting forwardtest quarantined ticks rows: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, "DELETE FROM forwardtest_subscriptions")
+	if err != nil {
+		return fmt.Errorf("deleting forwardtest subscriptions rows: %w", err)
+	}
+
+	_, err = a.db.ExecContext(ctx, "DELETE FROM forwardtests")
 	if err != nil {
 		return fmt.Errorf("deleting forwardtests rows: %w", err)
 	}
@@ -101,7 +181,9 @@ func (a *Activities) ReadForwardtestActivity(
 	}
 
 	err := a.db.GetContext(ctx, &entity, "SELECT * FROM forwardtests WHERE id = $1", params.ID)
-	if err != nil {
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.ReadForwardtestActivityResult{}, db.NewRecordNotFoundError("reading forwardtest %s", params.ID)
+	} else if err != nil {
 		return db.ReadForwardtestActivityResult{}, fmt.Errorf("reading forwardtest: %w", err)
 	}
 
@@ -115,18 +197,20 @@ func (a *Activities) ReadForwardtestActivity(
 	}, nil
 }
 
-// ListForwardtestsActivity lists all forwardtests from the database.
+// ListForwardtestsActivity lists the forwardtests from the database, without
+// the archived ones unless requested.
 func (a *Activities) ListForwardtestsActivity(
 	ctx context.Context,
-	_ db.ListForwardtestsActivityParams,
+	params db.ListForwardtestsActivityParams,
 ) (db.ListForwardtestsActivityResult, error) {
 	var entities []entities.Forwardtest
 
 	err := a.db.SelectContext(ctx, &entities, `
 		SELECT *
 		FROM forwardtests
+		WHERE $1 OR NOT COALESCE((data->>'archived')::boolean, false)
 		ORDER BY updated_at DESC
-	`)
+	`, params.IncludeArchived)
 	if err != nil {
 		return db.ListForwardtestsActivityResult{}, fmt.Errorf("querying forwardtests rows: %w", err)
 	}
@@ -173,6 +257,55 @@ func (a *Activities) UpdateForwardtestActivity(
 	return db.UpdateForwardtestActivityResult{}, nil
 }
 
+// SaveForwardtestOrdersActivity updates a forwardtest with its executed
+// orders, only if none of them is already saved on it and if it has not been
+// updated since it was read. The checks and the update are a single
+// statement, so concurrent executions of the same order only save it once and
+// concurrent updates of the forwardtest are never overwritten.
+func (a *Activities) SaveForwardtestOrdersActivity(
+	ctx context.Context,
+	params db.SaveForwardtestOrdersActivityParams,
+) (db.SaveForwardtestOrdersActivityResult, error) {
+	// Check ID is not nil
+	if params.Forwardtest.ID == uuid.Nil {
+		return db.SaveForwardtestOrdersActivityResult{}, db.ErrNilID
+	}
+
+	entity, err := entities.FromForwardtestModel(params.Forwardtest)
+	if err != nil {
+		return db.SaveForwardtestOrdersActivityResult{},
+			fmt.Errorf("converting forwardtest model to entity: %w", err)
+	}
+
+	orderIDs := make([]string, len(params.OrderIDs))
+	for i, id := range params.OrderIDs {
+		orderIDs[i] = id.String()
+	}
+
+	res, err := a.db.DB.ExecContext(ctx, `
+		UPDATE forwardtests
+		SET updated_at = $1, data = $2
+		WHERE id = $3 AND updated_at = $5 AND NOT EXISTS (
+			SELECT 1 FROM jsonb_array_elements(
+				CASE WHEN jsonb_typeof(data->'orders') = 'array' THEN data->'orders' ELSE '[]'::jsonb END
+			) AS saved
+			WHERE saved->>'id' = ANY($4)
+		)
+	`, entity.UpdatedAt, entity.Data, params.Forwardtest.ID, pq.Array(orderIDs), params.Forwardtest.UpdatedAt)
+	if err != nil {
+		return db.SaveForwardtestOrdersActivityResult{}, fmt.Errorf("updating forwardtest row: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return db.SaveForwardtestOrdersActivityResult{}, fmt.Errorf("counting updated forwardtest rows: %w", err)
+	}
+
+	return db.SaveForwardtestOrdersActivityResult{
+		Saved: count > 0,
+	}, nil
+}
+
 // DeleteForwardtestActivity deletes a forwardtest from the database.
 func (a *Activities) DeleteForwardtestActivity(
 	ctx context.Context,
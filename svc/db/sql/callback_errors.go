@@ -0,0 +1,121 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
+	"github.com/google/uuid"
+)
+
+// RecordCallbackErrorActivity saves a callback failure of a forwardtest and
+// increments its consecutive callback failures count.
+func (a *Activities) RecordCallbackErrorActivity(
+	ctx context.Context,
+	params db.RecordCallbackErrorActivityParams,
+) (db.RecordCallbackErrorActivityResult, error) {
+	// Check ID is not nil
+	if params.Error.ForwardtestID == uuid.Nil {
+		return db.RecordCallbackErrorActivityResult{}, db.ErrNilID
+	}
+
+	entity := entities.FromCallbackErrorModel(params.Error)
+	query, args, err := a.db.BindNamed(`
+		WITH recorded AS (
+			INSERT INTO forwardtest_callback_errors (forwardtest_id, time, callback, error)
+			VALUES (:forwardtest_id, :time, :callback, :error)
+		)
+		UPDATE forwardtests
+		SET consecutive_callback_failures = consecutive_callback_failures + 1
+		WHERE id = :forwardtest_id
+		RETURNING consecutive_callback_failures
+	`, entity)
+	if err != nil {
+		return db.RecordCallbackErrorActivityResult{}, fmt.Errorf("binding callback error query: %w", err)
+	}
+
+	var failures int
+	err = a.db.GetContext(ctx, &failures, query, args...)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.RecordCallbackErrorActivityResult{},
+			db.NewRecordNotFoundError("recording callback error of forwardtest %s", params.Error.ForwardtestID)
+	} else if err != nil {
+		return db.RecordCallbackErrorActivityResult{}, fmt.Errorf("inserting callback error row: %w", err)
+	}
+
+	return db.RecordCallbackErrorActivityResult{
+		ConsecutiveFailures: failures,
+	}, nil
+}
+
+// ResetCallbackFailuresActivity resets the consecutive callback failures
+// count of a forwardtest.
+func (a *Activities) ResetCallbackFailuresActivity(
+	ctx context.Context,
+	params db.ResetCallbackFailuresActivityParams,
+) (db.ResetCallbackFailuresActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ResetCallbackFailuresActivityResult{}, db.ErrNilID
+	}
+
+	// Only write the rows that have failures
+	_, err := a.db.ExecContext(ctx, `
+		UPDATE forwardtests
+		SET consecutive_callback_failures = 0
+		WHERE id = $1 AND consecutive_callback_failures > 0
+	`, params.ForwardtestID)
+	if err != nil {
+		return db.ResetCallbackFailuresActivityResult{}, fmt.Errorf("resetting callback failures: %w", err)
+	}
+
+	return db.ResetCallbackFailuresActivityResult{}, nil
+}
+
+// ListCallbackErrorsActivity lists the callback errors of a forwardtest, the
+// most recent first.
+func (a *Activities) ListCallbackErrorsActivity(
+	ctx context.Context,
+	params db.ListCallbackErrorsActivityParams,
+) (db.ListCallbackErrorsActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ListCallbackErrorsActivityResult{}, db.ErrNilID
+	}
+
+	// A NULL limit returns all the rows
+	var limit *int
+	if params.Limit > 0 {
+		limit = &params.Limit
+	}
+
+	var ents []entities.CallbackError
+	err := a.db.SelectContext(ctx, &ents, `
+		SELECT forwardtest_id, time, callback, error
+		FROM forwardtest_callback_errors
+		WHERE forwardtest_id = $1
+		ORDER BY time DESC, id DESC
+		LIMIT $2
+	`, params.ForwardtestID, limit)
+	if err != nil {
+		return db.ListCallbackErrorsActivityResult{}, fmt.Errorf("querying callback errors rows: %w", err)
+	}
+
+	models := make([]forwardtest.CallbackError, 0, len(ents))
+	for _, entity := range ents {
+		model, err := entity.ToModel()
+		if err != nil {
+			return db.ListCallbackErrorsActivityResult{},
+				fmt.Errorf("converting callback error entity to model: %w", err)
+		}
+		models = append(models, model)
+	}
+
+	return db.ListCallbackErrorsActivityResult{
+		Errors: models,
+	}, nil
+}
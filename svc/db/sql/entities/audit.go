@@ -0,0 +1,48 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+)
+
+// AuditEntry is the entity for an entry of the forwardtest audit log.
+type AuditEntry struct {
+	Time    time.Time `json:"time"`
+	Action  string    `json:"action"`
+	Details string    `json:"details,omitempty"`
+}
+
+// ToAuditEntryModels converts a list of AuditEntry to a list of forwardtest.AuditEntry.
+func ToAuditEntryModels(entries []AuditEntry) []forwardtest.AuditEntry {
+	if entries == nil {
+		return nil
+	}
+
+	models := make([]forwardtest.AuditEntry, len(entries))
+	for i, e := range entries {
+		models[i] = forwardtest.AuditEntry{
+			Time:    e.Time,
+			Action:  forwardtest.AuditAction(e.Action),
+			Details: e.Details,
+		}
+	}
+	return models
+}
+
+// FromAuditEntryModels converts a list of forwardtest.AuditEntry to a list of AuditEntry.
+func FromAuditEntryModels(models []forwardtest.AuditEntry) []AuditEntry {
+	if models == nil {
+		return nil
+	}
+
+	entities := make([]AuditEntry, len(models))
+	for i, m := range models {
+		entities[i] = AuditEntry{
+			Time:    m.Time,
+			Action:  m.Action.String(),
+			Details: m.Details,
+		}
+	}
+	return entities
+}
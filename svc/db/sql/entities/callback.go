@@ -3,6 +3,7 @@ package entities
 import (
 	"time"
 
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
 	"github.com/cryptellation/runtime"
 )
 
@@ -18,6 +19,10 @@ type Callbacks struct {
 	OnInitCallback      CallbackWorkflow `json:"on_init_callback"`
 	OnNewPricesCallback CallbackWorkflow `json:"on_new_prices_callback"`
 	OnExitCallback      CallbackWorkflow `json:"on_exit_callback"`
+
+	OnFeedStaleCallback   *CallbackWorkflow `json:"on_feed_stale_callback,omitempty"`
+	OnOrderUpdateCallback *CallbackWorkflow `json:"on_order_update_callback,omitempty"`
+	OnErrorCallback       *CallbackWorkflow `json:"on_error_callback,omitempty"`
 }
 
 // ToCallbackWorkflowModel converts a CallbackWorkflow entity to a runtime.CallbackWorkflow model.
@@ -55,3 +60,39 @@ func FromCallbacksModel(c runtime.Callbacks) Callbacks {
 		OnExitCallback:      FromCallbackWorkflowModel(c.OnExitCallback),
 	}
 }
+
+// ToOptionalCallbacksModel converts a Callbacks entity to a forwardtest.OptionalCallbacks model.
+func (c Callbacks) ToOptionalCallbacksModel() forwardtest.OptionalCallbacks {
+	return forwardtest.OptionalCallbacks{
+		OnFeedStaleCallback:   toOptionalCallbackWorkflowModel(c.OnFeedStaleCallback),
+		OnOrderUpdateCallback: toOptionalCallbackWorkflowModel(c.OnOrderUpdateCallback),
+		OnErrorCallback:       toOptionalCallbackWorkflowModel(c.OnErrorCallback),
+	}
+}
+
+// WithOptionalCallbacksModel returns the Callbacks entity with the callbacks
+// from a forwardtest.OptionalCallbacks model.
+func (c Callbacks) WithOptionalCallbacksModel(oc forwardtest.OptionalCallbacks) Callbacks {
+	c.OnFeedStaleCallback = fromOptionalCallbackWorkflowModel(oc.OnFeedStaleCallback)
+	c.OnOrderUpdateCallback = fromOptionalCallbackWorkflowModel(oc.OnOrderUpdateCallback)
+	c.OnErrorCallback = fromOptionalCallbackWorkflowModel(oc.OnErrorCallback)
+	return c
+}
+
+func toOptionalCallbackWorkflowModel(cw *CallbackWorkflow) *runtime.CallbackWorkflow {
+	if cw == nil {
+		return nil
+	}
+
+	model := cw.ToCallbackWorkflowModel()
+	return &model
+}
+
+func fromOptionalCallbackWorkflowModel(cw *runtime.CallbackWorkflow) *CallbackWorkflow {
+	if cw == nil {
+		return nil
+	}
+
+	entity := FromCallbackWorkflowModel(*cw)
+	return &entity
+}
@@ -0,0 +1,38 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+)
+
+// DeliveryPolicy is the entity for the ticks delivery policy of a forwardtest.
+type DeliveryPolicy struct {
+	Window      time.Duration `json:"window,omitempty"`
+	LatestOnly  bool          `json:"latest_only,omitempty"`
+	MinInterval time.Duration `json:"min_interval,omitempty"`
+	Concurrency string        `json:"concurrency,omitempty"`
+	QueueSize   int           `json:"queue_size,omitempty"`
+}
+
+// ToModel converts a DeliveryPolicy entity to a forwardtest.DeliveryPolicy model.
+func (dp DeliveryPolicy) ToModel() forwardtest.DeliveryPolicy {
+	return forwardtest.DeliveryPolicy{
+		Window:      dp.Window,
+		LatestOnly:  dp.LatestOnly,
+		MinInterval: dp.MinInterval,
+		Concurrency: forwardtest.ConcurrencyPolicy(dp.Concurrency),
+		QueueSize:   dp.QueueSize,
+	}
+}
+
+// FromDeliveryPolicyModel converts a forwardtest.DeliveryPolicy model to a DeliveryPolicy entity.
+func FromDeliveryPolicyModel(dp forwardtest.DeliveryPolicy) DeliveryPolicy {
+	return DeliveryPolicy{
+		Window:      dp.Window,
+		LatestOnly:  dp.LatestOnly,
+		MinInterval: dp.MinInterval,
+		Concurrency: dp.Concurrency.String(),
+		QueueSize:   dp.QueueSize,
+	}
+}
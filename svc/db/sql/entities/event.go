@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+// EventTick is the entity for the last tick processed in an event.
+type EventTick struct {
+	Exchange string    `json:"exchange"`
+	Pair     string    `json:"pair"`
+	Time     time.Time `json:"time"`
+	Price    float64   `json:"price"`
+}
+
+// EventData is the data for an event of a forwardtest.
+type EventData struct {
+	Status   string     `json:"status,omitempty"`
+	Reason   string     `json:"reason,omitempty"`
+	Order    *Order     `json:"order,omitempty"`
+	RiskRule string     `json:"risk_rule,omitempty"`
+	Ticks    int        `json:"ticks,omitempty"`
+	LastTick *EventTick `json:"last_tick,omitempty"`
+	Callback string     `json:"callback,omitempty"`
+	Error    string     `json:"error,omitempty"`
+}
+
+// Event is the entity for an event of a forwardtest.
+type Event struct {
+	// Seq is the position of the event in the log of its forwardtest.
+	Seq           int64     `db:"seq"`
+	ForwardtestID string    `db:"forwardtest_id"`
+	Time          time.Time `db:"time"`
+	Type          string    `db:"type"`
+	Data          []byte    `db:"data"`
+}
+
+// ToModel converts an Event entity to a forwardtest.Event model.
+func (e Event) ToModel() (forwardtest.Event, error) {
+	id, err := uuid.Parse(e.ForwardtestID)
+	if err != nil {
+		return forwardtest.Event{}, err
+	}
+
+	var data EventData
+	if err := json.Unmarshal(e.Data, &data); err != nil {
+		return forwardtest.Event{}, err
+	}
+
+	model := forwardtest.Event{
+		ID:            e.Seq,
+		ForwardtestID: id,
+		Time:          e.Time.UTC(),
+		Type:          forwardtest.EventType(e.Type),
+		Status:        forwardtest.Status(data.Status),
+		Reason:        data.Reason,
+		RiskRule:      forwardtest.RiskRule(data.RiskRule),
+		Ticks:         data.Ticks,
+		Callback:      forwardtest.CallbackKind(data.Callback),
+		Error:         data.Error,
+	}
+
+	if data.Order != nil {
+		o, err := data.Order.ToModel()
+		if err != nil {
+			return forwardtest.Event{}, err
+		}
+		model.Order = &o
+	}
+
+	if data.LastTick != nil {
+		model.LastTick = &tick.Tick{
+			Exchange: data.LastTick.Exchange,
+			Pair:     data.LastTick.Pair,
+			Time:     data.LastTick.Time.UTC(),
+			Price:    data.LastTick.Price,
+		}
+	}
+
+	return model, nil
+}
+
+// FromEventModel converts a forwardtest.Event model to an Event entity.
+func FromEventModel(e forwardtest.Event) (Event, error) {
+	data := EventData{
+		Status:   e.Status.String(),
+		Reason:   e.Reason,
+		RiskRule: e.RiskRule.String(),
+		Ticks:    e.Ticks,
+		Callback: e.Callback.String(),
+		Error:    e.Error,
+	}
+
+	if e.Order != nil {
+		o := FromOrderModel(*e.Order)
+		data.Order = &o
+	}
+
+	if e.LastTick != nil {
+		data.LastTick = &EventTick{
+			Exchange: e.LastTick.Exchange,
+			Pair:     e.LastTick.Pair,
+			Time:     e.LastTick.Time.UTC(),
+			Price:    e.LastTick.Price,
+		}
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, err
+	}
+
+	return Event{
+		Seq:           e.ID,
+		ForwardtestID: e.ForwardtestID.String(),
+		Time:          e.Time.UTC(),
+		Type:          e.Type.String(),
+		Data:          rawData,
+	}, nil
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+)
+
+// ExecutionPolicy is the entity for the execution policy of a forwardtest.
+type ExecutionPolicy struct {
+	Mode            string        `json:"mode,omitempty"`
+	SyntheticSpread float64       `json:"synthetic_spread,omitempty"`
+	MaxBookAge      time.Duration `json:"max_book_age,omitempty"`
+}
+
+// ToModel converts an ExecutionPolicy entity to a forwardtest.ExecutionPolicy model.
+func (ep ExecutionPolicy) ToModel() forwardtest.ExecutionPolicy {
+	return forwardtest.ExecutionPolicy{
+		Mode:            forwardtest.ExecutionMode(ep.Mode),
+		SyntheticSpread: ep.SyntheticSpread,
+		MaxBookAge:      ep.MaxBookAge,
+	}
+}
+
+// FromExecutionPolicyModel converts a forwardtest.ExecutionPolicy model to an ExecutionPolicy entity.
+func FromExecutionPolicyModel(ep forwardtest.ExecutionPolicy) ExecutionPolicy {
+	return ExecutionPolicy{
+		Mode:            ep.Mode.String(),
+		SyntheticSpread: ep.SyntheticSpread,
+		MaxBookAge:      ep.MaxBookAge,
+	}
+}
+
+// BookLevel is the entity for a price level of an order book.
+type BookLevel struct {
+	Price    float64 `json:"price"`
+	Quantity float64 `json:"quantity,omitempty"`
+}
+
+// OrderBook is the entity for an order book snapshot of a subscription.
+type OrderBook struct {
+	Time time.Time   `json:"time"`
+	Bids []BookLevel `json:"bids,omitempty"`
+	Asks []BookLevel `json:"asks,omitempty"`
+}
+
+// ToModel converts an OrderBook entity to a forwardtest.OrderBook model.
+func (ob OrderBook) ToModel() forwardtest.OrderBook {
+	return forwardtest.OrderBook{
+		Time: ob.Time,
+		Bids: toBookLevelModels(ob.Bids),
+		Asks: toBookLevelModels(ob.Asks),
+	}
+}
+
+// FromOrderBookModel converts a forwardtest.OrderBook model to an OrderBook entity.
+func FromOrderBookModel(ob forwardtest.OrderBook) OrderBook {
+	return OrderBook{
+		Time: ob.Time.UTC(),
+		Bids: fromBookLevelModels(ob.Bids),
+		Asks: fromBookLevelModels(ob.Asks),
+	}
+}
+
+func toBookLevelModels(levels []BookLevel) []forwardtest.BookLevel {
+	if levels == nil {
+		return nil
+	}
+
+	models := make([]forwardtest.BookLevel, len(levels))
+	for i, l := range levels {
+		models[i] = forwardtest.BookLevel{Price: l.Price, Quantity: l.Quantity}
+	}
+	return models
+}
+
+func fromBookLevelModels(levels []forwardtest.BookLevel) []BookLevel {
+	if levels == nil {
+		return nil
+	}
+
+	ents := make([]BookLevel, len(levels))
+	for i, l := range levels {
+		ents[i] = BookLevel{Price: l.Price, Quantity: l.Quantity}
+	}
+	return ents
+}
@@ -0,0 +1,87 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/google/uuid"
+)
+
+// RetryPolicy is the entity for the retry policy of a callback.
+type RetryPolicy struct {
+	MaxAttempts        int           `json:"max_attempts,omitempty"`
+	InitialInterval    time.Duration `json:"initial_interval,omitempty"`
+	BackoffCoefficient float64       `json:"backoff_coefficient,omitempty"`
+	MaxInterval        time.Duration `json:"max_interval,omitempty"`
+}
+
+// FailurePolicy is the entity for the callback failure policy of a forwardtest.
+type FailurePolicy struct {
+	Retries                map[string]RetryPolicy `json:"retries,omitempty"`
+	MaxConsecutiveFailures int                    `json:"max_consecutive_failures,omitempty"`
+}
+
+// ToModel converts a FailurePolicy entity to a forwardtest.FailurePolicy model.
+func (fp FailurePolicy) ToModel() forwardtest.FailurePolicy {
+	var retries map[forwardtest.CallbackKind]forwardtest.RetryPolicy
+	if fp.Retries != nil {
+		retries = make(map[forwardtest.CallbackKind]forwardtest.RetryPolicy, len(fp.Retries))
+		for kind, rp := range fp.Retries {
+			retries[forwardtest.CallbackKind(kind)] = forwardtest.RetryPolicy(rp)
+		}
+	}
+
+	return forwardtest.FailurePolicy{
+		Retries:                retries,
+		MaxConsecutiveFailures: fp.MaxConsecutiveFailures,
+	}
+}
+
+// FromFailurePolicyModel converts a forwardtest.FailurePolicy model to a FailurePolicy entity.
+func FromFailurePolicyModel(fp forwardtest.FailurePolicy) FailurePolicy {
+	var retries map[string]RetryPolicy
+	if fp.Retries != nil {
+		retries = make(map[string]RetryPolicy, len(fp.Retries))
+		for kind, rp := range fp.Retries {
+			retries[kind.String()] = RetryPolicy(rp)
+		}
+	}
+
+	return FailurePolicy{
+		Retries:                retries,
+		MaxConsecutiveFailures: fp.MaxConsecutiveFailures,
+	}
+}
+
+// CallbackError is the entity for a callback failure of a forwardtest.
+type CallbackError struct {
+	ForwardtestID string    `db:"forwardtest_id"`
+	Time          time.Time `db:"time"`
+	Callback      string    `db:"callback"`
+	Error         string    `db:"error"`
+}
+
+// ToModel converts a CallbackError entity to a forwardtest.CallbackError model.
+func (ce CallbackError) ToModel() (forwardtest.CallbackError, error) {
+	id, err := uuid.Parse(ce.ForwardtestID)
+	if err != nil {
+		return forwardtest.CallbackError{}, err
+	}
+
+	return forwardtest.CallbackError{
+		ForwardtestID: id,
+		Time:          ce.Time.UTC(),
+		Callback:      forwardtest.CallbackKind(ce.Callback),
+		Error:         ce.Error,
+	}, nil
+}
+
+// FromCallbackErrorModel converts a forwardtest.CallbackError model to a CallbackError entity.
+func FromCallbackErrorModel(ce forwardtest.CallbackError) CallbackError {
+	return CallbackError{
+		ForwardtestID: ce.ForwardtestID.String(),
+		Time:          ce.Time.UTC(),
+		Callback:      ce.Callback.String(),
+		Error:         ce.Error,
+	}
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+)
+
+// FeedHealth is the entity for the feed health settings of a forwardtest.
+type FeedHealth struct {
+	StaleAfter           time.Duration `json:"stale_after,omitempty"`
+	BlockOrdersWhenStale bool          `json:"block_orders_when_stale,omitempty"`
+}
+
+// ToModel converts a FeedHealth entity to a forwardtest.FeedHealth model.
+func (fh FeedHealth) ToModel() forwardtest.FeedHealth {
+	return forwardtest.FeedHealth{
+		StaleAfter:           fh.StaleAfter,
+		BlockOrdersWhenStale: fh.BlockOrdersWhenStale,
+	}
+}
+
+// FromFeedHealthModel converts a forwardtest.FeedHealth model to a FeedHealth entity.
+func FromFeedHealthModel(fh forwardtest.FeedHealth) FeedHealth {
+	return FeedHealth{
+		StaleAfter:           fh.StaleAfter,
+		BlockOrdersWhenStale: fh.BlockOrdersWhenStale,
+	}
+}
@@ -0,0 +1,86 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+// TickFilter is the entity for the tick filter of a forwardtest.
+type TickFilter struct {
+	RejectNonPositivePrice bool          `json:"reject_non_positive_price,omitempty"`
+	MaxJump                float64       `json:"max_jump,omitempty"`
+	MaxJumpWindow          time.Duration `json:"max_jump_window,omitempty"`
+	RejectOutOfOrder       bool          `json:"reject_out_of_order,omitempty"`
+}
+
+// ToModel converts a TickFilter entity to a forwardtest.TickFilter model.
+func (tf TickFilter) ToModel() forwardtest.TickFilter {
+	return forwardtest.TickFilter{
+		RejectNonPositivePrice: tf.RejectNonPositivePrice,
+		MaxJump:                tf.MaxJump,
+		MaxJumpWindow:          tf.MaxJumpWindow,
+		RejectOutOfOrder:       tf.RejectOutOfOrder,
+	}
+}
+
+// FromTickFilterModel converts a forwardtest.TickFilter model to a TickFilter entity.
+func FromTickFilterModel(tf forwardtest.TickFilter) TickFilter {
+	return TickFilter{
+		RejectNonPositivePrice: tf.RejectNonPositivePrice,
+		MaxJump:                tf.MaxJump,
+		MaxJumpWindow:          tf.MaxJumpWindow,
+		RejectOutOfOrder:       tf.RejectOutOfOrder,
+	}
+}
+
+// QuarantinedTick is the entity for a tick rejected by the tick filter of a
+// forwardtest.
+type QuarantinedTick struct {
+	ForwardtestID string    `db:"forwardtest_id"`
+	Exchange      string    `db:"exchange"`
+	Pair          string    `db:"pair"`
+	Time          time.Time `db:"time"`
+	Price         float64   `db:"price"`
+	Rule          string    `db:"rule"`
+	Reason        string    `db:"reason"`
+	QuarantinedAt time.Time `db:"quarantined_at"`
+}
+
+// ToModel converts a QuarantinedTick entity to a forwardtest.QuarantinedTick model.
+func (qt QuarantinedTick) ToModel() (forwardtest.QuarantinedTick, error) {
+	id, err := uuid.Parse(qt.ForwardtestID)
+	if err != nil {
+		return forwardtest.QuarantinedTick{}, err
+	}
+
+	return forwardtest.QuarantinedTick{
+		ForwardtestID: id,
+		Tick: tick.Tick{
+			Time:     qt.Time,
+			Pair:     qt.Pair,
+			Price:    qt.Price,
+			Exchange: qt.Exchange,
+		},
+		Rule:          forwardtest.TickRule(qt.Rule),
+		Reason:        qt.Reason,
+		QuarantinedAt: qt.QuarantinedAt,
+	}, nil
+}
+
+// FromQuarantinedTickModel converts a forwardtest.QuarantinedTick model to a
+// QuarantinedTick entity.
+func FromQuarantinedTickModel(qt forwardtest.QuarantinedTick) QuarantinedTick {
+	return QuarantinedTick{
+		ForwardtestID: qt.ForwardtestID.String(),
+		Exchange:      qt.Tick.Exchange,
+		Pair:          qt.Tick.Pair,
+		Time:          qt.Tick.Time,
+		Price:         qt.Tick.Price,
+		Rule:          qt.Rule.String(),
+		Reason:        qt.Reason,
+		QuarantinedAt: qt.QuarantinedAt,
+	}
+}
@@ -5,15 +5,30 @@ import (
 	"time"
 
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/runtime/account"
 	"github.com/google/uuid"
 )
 
 // ForwardtestData is the data for a forwardtest.
 type ForwardtestData struct {
-	Accounts  map[string]Account `json:"accounts"`
-	Orders    []Order            `json:"orders"`
-	Callbacks Callbacks          `json:"callbacks"`
-	Status    string             `json:"status"`
+	ParentID        *string                 `json:"parent_id,omitempty"`
+	InitialAccounts map[string]Account      `json:"initial_accounts,omitempty"`
+	Accounts        map[string]Account      `json:"accounts"`
+	Orders          []Order                 `json:"orders"`
+	OrderTriggers   map[string]OrderTrigger `json:"order_triggers,omitempty"`
+	Callbacks       Callbacks               `json:"callbacks"`
+	Risk            RiskLimits              `json:"risk"`
+	Delivery        DeliveryPolicy          `json:"delivery"`
+	FeedHealth      FeedHealth              `json:"feed_health"`
+	TickFilter      TickFilter              `json:"tick_filter"`
+	Execution       ExecutionPolicy         `json:"execution"`
+	Failures        FailurePolicy           `json:"failures"`
+	RecordTicks     bool                    `json:"record_ticks,omitempty"`
+	ReplayOf        *string                 `json:"replay_of,omitempty"`
+	Status          string                  `json:"status"`
+	StatusReason    string                  `json:"status_reason,omitempty"`
+	Archived        bool                    `json:"archived,omitempty"`
+	Audit           []AuditEntry            `json:"audit,omitempty"`
 }
 
 // Forwardtest is the entity for a forwardtest.
@@ -21,6 +36,12 @@ type Forwardtest struct {
 	ID        string    `db:"id"`
 	UpdatedAt time.Time `db:"updated_at"`
 	Data      []byte    `db:"data"`
+	// ConsecutiveCallbackFailures is only updated by the callback errors
+	// activities.
+	ConsecutiveCallbackFailures int `db:"consecutive_callback_failures"`
+	// EventSeq is the sequence of the last event recorded on the forwardtest.
+	// It is only updated by the record event activity.
+	EventSeq int64 `db:"event_seq"`
 }
 
 // ToModel converts a Forwardtest entity to a Forwardtest model.
@@ -40,6 +61,20 @@ func (ft Forwardtest) ToModel() (forwardtest.Forwardtest, error) {
 	if err != nil {
 		return forwardtest.Forwardtest{}, err
 	}
+	orderTriggers, err := ToOrderTriggerModels(data.OrderTriggers)
+	if err != nil {
+		return forwardtest.Forwardtest{}, err
+	}
+
+	// Parse parent and replayed forwardtests IDs
+	parentID, err := toOptionalUUID(data.ParentID)
+	if err != nil {
+		return forwardtest.Forwardtest{}, err
+	}
+	replayOf, err := toOptionalUUID(data.ReplayOf)
+	if err != nil {
+		return forwardtest.Forwardtest{}, err
+	}
 
 	// Parse status
 	status := forwardtest.Status(data.Status)
@@ -48,22 +83,57 @@ func (ft Forwardtest) ToModel() (forwardtest.Forwardtest, error) {
 	}
 
 	return forwardtest.Forwardtest{
-		ID:        id,
-		UpdatedAt: ft.UpdatedAt,
-		Accounts:  ToAccountModels(data.Accounts),
-		Orders:    orders,
-		Callbacks: data.Callbacks.ToCallbacksModel(),
-		Status:    status,
+		ID:                          id,
+		ParentID:                    parentID,
+		UpdatedAt:                   ft.UpdatedAt,
+		InitialAccounts:             toOptionalAccountModels(data.InitialAccounts),
+		Accounts:                    ToAccountModels(data.Accounts),
+		Orders:                      orders,
+		OrderTriggers:               orderTriggers,
+		Callbacks:                   data.Callbacks.ToCallbacksModel(),
+		OptionalCallbacks:           data.Callbacks.ToOptionalCallbacksModel(),
+		Risk:                        data.Risk.ToModel(),
+		Delivery:                    data.Delivery.ToModel(),
+		FeedHealth:                  data.FeedHealth.ToModel(),
+		TickFilter:                  data.TickFilter.ToModel(),
+		Execution:                   data.Execution.ToModel(),
+		Failures:                    data.Failures.ToModel(),
+		RecordTicks:                 data.RecordTicks,
+		ReplayOf:                    replayOf,
+		Status:                      status,
+		StatusReason:                data.StatusReason,
+		ConsecutiveCallbackFailures: ft.ConsecutiveCallbackFailures,
+		Archived:                    data.Archived,
+		Audit:                       ToAuditEntryModels(data.Audit),
 	}, nil
 }
 
 // FromForwardtestModel converts a Forwardtest model to a Forwardtest entity.
 func FromForwardtestModel(ft forwardtest.Forwardtest) (Forwardtest, error) {
+	var initialAccounts map[string]Account
+	if ft.InitialAccounts != nil {
+		initialAccounts = FromAccountModels(ft.InitialAccounts)
+	}
+
 	data := ForwardtestData{
-		Accounts:  FromAccountModels(ft.Accounts),
-		Orders:    FromOrderModels(ft.Orders),
-		Callbacks: FromCallbacksModel(ft.Callbacks),
-		Status:    ft.Status.String(),
+		ParentID:        fromOptionalUUID(ft.ParentID),
+		InitialAccounts: initialAccounts,
+		Accounts:        FromAccountModels(ft.Accounts),
+		Orders:          FromOrderModels(ft.Orders),
+		OrderTriggers:   FromOrderTriggerModels(ft.OrderTriggers),
+		Callbacks:       FromCallbacksModel(ft.Callbacks).WithOptionalCallbacksModel(ft.OptionalCallbacks),
+		Risk:            FromRiskLimitsModel(ft.Risk),
+		Delivery:        FromDeliveryPolicyModel(ft.Delivery),
+		FeedHealth:      FromFeedHealthModel(ft.FeedHealth),
+		TickFilter:      FromTickFilterModel(ft.TickFilter),
+		Execution:       FromExecutionPolicyModel(ft.Execution),
+		Failures:        FromFailurePolicyModel(ft.Failures),
+		RecordTicks:     ft.RecordTicks,
+		ReplayOf:        fromOptionalUUID(ft.ReplayOf),
+		Status:          ft.Status.String(),
+		StatusReason:    ft.StatusReason,
+		Archived:        ft.Archived,
+		Audit:           FromAuditEntryModels(ft.Audit),
 	}
 
 	dataBytes, err := json.Marshal(data)
@@ -77,3 +147,35 @@ func FromForwardtestModel(ft forwardtest.Forwardtest) (Forwardtest, error) {
 		Data:      dataBytes,
 	}, nil
 }
+
+// toOptionalAccountModels converts the accounts, keeping them nil when not
+// saved.
+func toOptionalAccountModels(accounts map[string]Account) map[string]account.Account {
+	if accounts == nil {
+		return nil
+	}
+
+	return ToAccountModels(accounts)
+}
+
+func toOptionalUUID(s *string) (*uuid.UUID, error) {
+	if s == nil {
+		return nil, nil
+	}
+
+	id, err := uuid.Parse(*s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &id, nil
+}
+
+func fromOptionalUUID(id *uuid.UUID) *string {
+	if id == nil {
+		return nil
+	}
+
+	s := id.String()
+	return &s
+}
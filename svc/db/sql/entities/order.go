@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
 	"github.com/google/uuid"
 )
 
@@ -82,3 +83,53 @@ func FromOrderModel(m order.Order) Order {
 		Price:         m.Price,
 	}
 }
+
+// OrderTrigger is the entity for the tick that triggered an order.
+type OrderTrigger struct {
+	Exchange string    `json:"exchange"`
+	Pair     string    `json:"pair"`
+	Time     time.Time `json:"time"`
+	Price    float64   `json:"price"`
+}
+
+// ToOrderTriggerModels converts the order triggers, by order ID, to ticks.
+func ToOrderTriggerModels(triggers map[string]OrderTrigger) (map[uuid.UUID]tick.Tick, error) {
+	if triggers == nil {
+		return nil, nil
+	}
+
+	models := make(map[uuid.UUID]tick.Tick, len(triggers))
+	for id, t := range triggers {
+		orderID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, err
+		}
+
+		models[orderID] = tick.Tick{
+			Time:     t.Time,
+			Pair:     t.Pair,
+			Price:    t.Price,
+			Exchange: t.Exchange,
+		}
+	}
+	return models, nil
+}
+
+// FromOrderTriggerModels converts the ticks that triggered orders, by order
+// ID, to entities.
+func FromOrderTriggerModels(models map[uuid.UUID]tick.Tick) map[string]OrderTrigger {
+	if models == nil {
+		return nil
+	}
+
+	entities := make(map[string]OrderTrigger, len(models))
+	for id, t := range models {
+		entities[id.String()] = OrderTrigger{
+			Exchange: t.Exchange,
+			Pair:     t.Pair,
+			Time:     t.Time,
+			Price:    t.Price,
+		}
+	}
+	return entities
+}
@@ -0,0 +1,62 @@
+package entities
+
+import "github.com/cryptellation/forwardtests/pkg/forwardtest"
+
+// PairRules is the entity for the quantity rules of a pair.
+type PairRules struct {
+	MinQuantity float64 `json:"min_quantity,omitempty"`
+	StepSize    float64 `json:"step_size,omitempty"`
+}
+
+// RiskLimits is the entity for the risk limits of a forwardtest.
+type RiskLimits struct {
+	MaxOrderNotional float64              `json:"max_order_notional,omitempty"`
+	MaxPositions     map[string]float64   `json:"max_positions,omitempty"`
+	MaxOpenOrders    int                  `json:"max_open_orders,omitempty"`
+	AllowedPairs     map[string][]string  `json:"allowed_pairs,omitempty"`
+	PairRules        map[string]PairRules `json:"pair_rules,omitempty"`
+}
+
+// ToModel converts a RiskLimits entity to a forwardtest.RiskLimits model.
+func (rl RiskLimits) ToModel() forwardtest.RiskLimits {
+	var rules map[string]forwardtest.PairRules
+	if rl.PairRules != nil {
+		rules = make(map[string]forwardtest.PairRules, len(rl.PairRules))
+		for p, r := range rl.PairRules {
+			rules[p] = forwardtest.PairRules{
+				MinQuantity: r.MinQuantity,
+				StepSize:    r.StepSize,
+			}
+		}
+	}
+
+	return forwardtest.RiskLimits{
+		MaxOrderNotional: rl.MaxOrderNotional,
+		MaxPositions:     rl.MaxPositions,
+		MaxOpenOrders:    rl.MaxOpenOrders,
+		AllowedPairs:     rl.AllowedPairs,
+		PairRules:        rules,
+	}
+}
+
+// FromRiskLimitsModel converts a forwardtest.RiskLimits model to a RiskLimits entity.
+func FromRiskLimitsModel(rl forwardtest.RiskLimits) RiskLimits {
+	var rules map[string]PairRules
+	if rl.PairRules != nil {
+		rules = make(map[string]PairRules, len(rl.PairRules))
+		for p, r := range rl.PairRules {
+			rules[p] = PairRules{
+				MinQuantity: r.MinQuantity,
+				StepSize:    r.StepSize,
+			}
+		}
+	}
+
+	return RiskLimits{
+		MaxOrderNotional: rl.MaxOrderNotional,
+		MaxPositions:     rl.MaxPositions,
+		MaxOpenOrders:    rl.MaxOpenOrders,
+		AllowedPairs:     rl.AllowedPairs,
+		PairRules:        rules,
+	}
+}
@@ -0,0 +1,124 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/period"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/runtime"
+	"github.com/google/uuid"
+)
+
+// Subscription is the entity for a forwardtest price subscription.
+type Subscription struct {
+	ForwardtestID            string     `db:"forwardtest_id"`
+	Exchange                 string     `db:"exchange"`
+	Pair                     string     `db:"pair"`
+	CreatedAt                time.Time  `db:"created_at"`
+	LastTickAt               *time.Time `db:"last_tick_at"`
+	TickCount                int64      `db:"tick_count"`
+	Period                   *string    `db:"period"`
+	OnNewCandlestickCallback []byte     `db:"on_new_candlestick_callback"`
+	StaleSince               *time.Time `db:"stale_since"`
+	LastPrice                float64    `db:"last_price"`
+	RejectedTickCount        int64      `db:"rejected_tick_count"`
+	OrderBook                []byte     `db:"order_book"`
+}
+
+// ToModel converts a Subscription entity to a forwardtest.Subscription model.
+func (s Subscription) ToModel() (forwardtest.Subscription, error) {
+	id, err := uuid.Parse(s.ForwardtestID)
+	if err != nil {
+		return forwardtest.Subscription{}, err
+	}
+
+	// Parse period
+	var per *period.Symbol
+	if s.Period != nil {
+		p, err := period.FromString(*s.Period)
+		if err != nil {
+			return forwardtest.Subscription{}, err
+		}
+		per = &p
+	}
+
+	// Parse candlestick callback
+	var callback *runtime.CallbackWorkflow
+	if s.OnNewCandlestickCallback != nil {
+		var cw CallbackWorkflow
+		if err := json.Unmarshal(s.OnNewCandlestickCallback, &cw); err != nil {
+			return forwardtest.Subscription{}, err
+		}
+		model := cw.ToCallbackWorkflowModel()
+		callback = &model
+	}
+
+	// Parse order book
+	var book *forwardtest.OrderBook
+	if s.OrderBook != nil {
+		var ob OrderBook
+		if err := json.Unmarshal(s.OrderBook, &ob); err != nil {
+			return forwardtest.Subscription{}, err
+		}
+		model := ob.ToModel()
+		book = &model
+	}
+
+	return forwardtest.Subscription{
+		ForwardtestID:            id,
+		Exchange:                 s.Exchange,
+		Pair:                     s.Pair,
+		CreatedAt:                s.CreatedAt,
+		LastTickAt:               s.LastTickAt,
+		TickCount:                s.TickCount,
+		Period:                   per,
+		OnNewCandlestickCallback: callback,
+		StaleSince:               s.StaleSince,
+		LastPrice:                s.LastPrice,
+		RejectedTickCount:        s.RejectedTickCount,
+		OrderBook:                book,
+	}, nil
+}
+
+// FromSubscriptionModel converts a forwardtest.Subscription model to a Subscription entity.
+func FromSubscriptionModel(s forwardtest.Subscription) (Subscription, error) {
+	var per *string
+	if s.Period != nil {
+		p := s.Period.String()
+		per = &p
+	}
+
+	var callback []byte
+	if s.OnNewCandlestickCallback != nil {
+		var err error
+		callback, err = json.Marshal(FromCallbackWorkflowModel(*s.OnNewCandlestickCallback))
+		if err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	var book []byte
+	if s.OrderBook != nil {
+		var err error
+		book, err = json.Marshal(FromOrderBookModel(*s.OrderBook))
+		if err != nil {
+			return Subscription{}, err
+		}
+	}
+
+	return Subscription{
+		ForwardtestID:            s.ForwardtestID.String(),
+		Exchange:                 s.Exchange,
+		Pair:                     s.Pair,
+		CreatedAt:                s.CreatedAt,
+		LastTickAt:               s.LastTickAt,
+		TickCount:                s.TickCount,
+		Period:                   per,
+		OnNewCandlestickCallback: callback,
+		StaleSince:               s.StaleSince,
+		LastPrice:                s.LastPrice,
+		RejectedTickCount:        s.RejectedTickCount,
+		OrderBook:                book,
+	}, nil
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+// RecordedTick is the entity for a tick delivered to a forwardtest.
+type RecordedTick struct {
+	ForwardtestID string    `db:"forwardtest_id"`
+	Exchange      string    `db:"exchange"`
+	Pair          string    `db:"pair"`
+	Time          time.Time `db:"time"`
+	Price         float64   `db:"price"`
+}
+
+// ToModel converts a RecordedTick entity to a tick.Tick model.
+func (rt RecordedTick) ToModel() tick.Tick {
+	return tick.Tick{
+		Time:     rt.Time,
+		Pair:     rt.Pair,
+		Price:    rt.Price,
+		Exchange: rt.Exchange,
+	}
+}
+
+// FromRecordedTickModel converts a tick.Tick model delivered to a forwardtest
+// to a RecordedTick entity.
+func FromRecordedTickModel(forwardtestID uuid.UUID, t tick.Tick) RecordedTick {
+	return RecordedTick{
+		ForwardtestID: forwardtestID.String(),
+		Exchange:      t.Exchange,
+		Pair:          t.Pair,
+		Time:          t.Time,
+		Price:         t.Price,
+	}
+}
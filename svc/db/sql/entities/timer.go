@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/google/uuid"
+)
+
+// Timer is the entity for a recurring timer of a forwardtest.
+type Timer struct {
+	ForwardtestID string        `db:"forwardtest_id"`
+	Name          string        `db:"name"`
+	Interval      time.Duration `db:"interval"`
+	Cron          string        `db:"cron"`
+	Callback      []byte        `db:"callback"`
+	CreatedAt     time.Time     `db:"created_at"`
+}
+
+// ToModel converts a Timer entity to a forwardtest.Timer model.
+func (t Timer) ToModel() (forwardtest.Timer, error) {
+	id, err := uuid.Parse(t.ForwardtestID)
+	if err != nil {
+		return forwardtest.Timer{}, err
+	}
+
+	var cw CallbackWorkflow
+	if err := json.Unmarshal(t.Callback, &cw); err != nil {
+		return forwardtest.Timer{}, err
+	}
+
+	return forwardtest.Timer{
+		ForwardtestID: id,
+		Name:          t.Name,
+		Interval:      t.Interval,
+		Cron:          t.Cron,
+		Callback:      cw.ToCallbackWorkflowModel(),
+		CreatedAt:     t.CreatedAt,
+	}, nil
+}
+
+// FromTimerModel converts a forwardtest.Timer model to a Timer entity.
+func FromTimerModel(t forwardtest.Timer) (Timer, error) {
+	callback, err := json.Marshal(FromCallbackWorkflowModel(t.Callback))
+	if err != nil {
+		return Timer{}, err
+	}
+
+	return Timer{
+		ForwardtestID: t.ForwardtestID.String(),
+		Name:          t.Name,
+		Interval:      t.Interval,
+		Cron:          t.Cron,
+		Callback:      callback,
+		CreatedAt:     t.CreatedAt,
+	}, nil
+}
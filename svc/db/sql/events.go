@@ -0,0 +1,142 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
+	"github.com/google/uuid"
+)
+
+// RecordEventActivity appends an event to the event log of a forwardtest.
+// Consecutive ticks processed events are coalesced into one.
+func (a *Activities) RecordEventActivity(
+	ctx context.Context,
+	params db.RecordEventActivityParams,
+) (db.RecordEventActivityResult, error) {
+	// Check ID is not nil
+	if params.Event.ForwardtestID == uuid.Nil {
+		return db.RecordEventActivityResult{}, db.ErrNilID
+	}
+
+	entity, err := entities.FromEventModel(params.Event)
+	if err != nil {
+		return db.RecordEventActivityResult{}, fmt.Errorf("converting event model to entity: %w", err)
+	}
+
+	// The sequence of the forwardtest is incremented under its row lock, so
+	// the events are committed in the order of their sequence. A ticks
+	// processed event following another one is merged into it, and moved to
+	// the end of the log.
+	var seq int64
+	err = a.db.GetContext(ctx, &seq, `
+		WITH next AS (
+			UPDATE forwardtests
+			SET event_seq = event_seq + 1
+			WHERE id = $1
+			RETURNING event_seq
+		), coalesced AS (
+			UPDATE forwardtest_events AS e
+			SET seq = next.event_seq, time = $2::timestamp,
+				data = $4::jsonb || jsonb_build_object('ticks',
+					COALESCE((e.data->>'ticks')::int, 0) + COALESCE(($4::jsonb->>'ticks')::int, 0))
+			FROM next
+			WHERE $5::boolean AND e.forwardtest_id = $1 AND e.seq = next.event_seq - 1 AND e.type = $3::varchar
+			RETURNING e.seq
+		), inserted AS (
+			INSERT INTO forwardtest_events (forwardtest_id, seq, time, type, data)
+			SELECT $1::varchar, event_seq, $2::timestamp, $3::varchar, $4::jsonb
+			FROM next
+			WHERE NOT EXISTS (SELECT 1 FROM coalesced)
+			RETURNING seq
+		)
+		SELECT seq FROM coalesced
+		UNION ALL
+		SELECT seq FROM inserted
+	`, entity.ForwardtestID, entity.Time, entity.Type, entity.Data,
+		params.Event.Type == forwardtest.EventTypeTicksProcessed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.RecordEventActivityResult{}, db.ErrRecordNotFound
+	} else if err != nil {
+		return db.RecordEventActivityResult{}, fmt.Errorf("inserting event row: %w", err)
+	}
+
+	return db.RecordEventActivityResult{
+		ID: seq,
+	}, nil
+}
+
+// ListEventsActivity lists the events of a forwardtest after a cursor, the
+// oldest first.
+func (a *Activities) ListEventsActivity(
+	ctx context.Context,
+	params db.ListEventsActivityParams,
+) (db.ListEventsActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ListEventsActivityResult{}, db.ErrNilID
+	}
+
+	// A NULL limit returns all the rows
+	var limit *int
+	if params.Limit > 0 {
+		limit = &params.Limit
+	}
+
+	var ents []entities.Event
+	err := a.db.SelectContext(ctx, &ents, `
+		SELECT seq, forwardtest_id, time, type, data
+		FROM forwardtest_events
+		WHERE forwardtest_id = $1 AND seq > $2
+		ORDER BY seq ASC
+		LIMIT $3
+	`, params.ForwardtestID, params.After, limit)
+	if err != nil {
+		return db.ListEventsActivityResult{}, fmt.Errorf("querying events rows: %w", err)
+	}
+
+	models := make([]forwardtest.Event, 0, len(ents))
+	for _, entity := range ents {
+		model, err := entity.ToModel()
+		if err != nil {
+			return db.ListEventsActivityResult{}, fmt.Errorf("converting event entity to model: %w", err)
+		}
+		models = append(models, model)
+	}
+
+	return db.ListEventsActivityResult{
+		Events: models,
+	}, nil
+}
+
+// PruneEventsActivity deletes the events recorded before the retention
+// duration, whatever their forwardtest.
+func (a *Activities) PruneEventsActivity(
+	ctx context.Context,
+	params db.PruneEventsActivityParams,
+) (db.PruneEventsActivityResult, error) {
+	if params.Retention <= 0 {
+		return db.PruneEventsActivityResult{}, fmt.Errorf("non positive events retention: %s", params.Retention)
+	}
+
+	res, err := a.db.ExecContext(ctx, `
+		DELETE FROM forwardtest_events
+		WHERE recorded_at < (NOW() AT TIME ZONE 'UTC') - make_interval(secs => $1)
+	`, params.Retention.Seconds())
+	if err != nil {
+		return db.PruneEventsActivityResult{}, fmt.Errorf("deleting events rows: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return db.PruneEventsActivityResult{}, fmt.Errorf("counting deleted events rows: %w", err)
+	}
+
+	return db.PruneEventsActivityResult{
+		Deleted: count,
+	}, nil
+}
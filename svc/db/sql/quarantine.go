@@ -0,0 +1,85 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
+	"github.com/google/uuid"
+)
+
+// QuarantineTickActivity saves a tick rejected by the tick filter of a
+// forwardtest and increments the rejected ticks count of its subscription.
+func (a *Activities) QuarantineTickActivity(
+	ctx context.Context,
+	params db.QuarantineTickActivityParams,
+) (db.QuarantineTickActivityResult, error) {
+	// Check ID is not nil
+	if params.Tick.ForwardtestID == uuid.Nil {
+		return db.QuarantineTickActivityResult{}, db.ErrNilID
+	}
+
+	entity := entities.FromQuarantinedTickModel(params.Tick)
+	_, err := a.db.NamedExecContext(ctx, `
+		WITH quarantined AS (
+			INSERT INTO forwardtest_quarantined_ticks (
+				forwardtest_id, exchange, pair, time, price, rule, reason, quarantined_at)
+			VALUES (
+				:forwardtest_id, :exchange, :pair, :time, :price, :rule, :reason, :quarantined_at)
+		)
+		UPDATE forwardtest_subscriptions
+		SET rejected_tick_count = rejected_tick_count + 1
+		WHERE forwardtest_id = :forwardtest_id AND exchange = :exchange AND pair = :pair
+	`, entity)
+	if err != nil {
+		return db.QuarantineTickActivityResult{}, fmt.Errorf("inserting quarantined tick row: %w", err)
+	}
+
+	return db.QuarantineTickActivityResult{}, nil
+}
+
+// ListQuarantinedTicksActivity lists the quarantined ticks of a forwardtest,
+// the most recent first.
+func (a *Activities) ListQuarantinedTicksActivity(
+	ctx context.Context,
+	params db.ListQuarantinedTicksActivityParams,
+) (db.ListQuarantinedTicksActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ListQuarantinedTicksActivityResult{}, db.ErrNilID
+	}
+
+	// A NULL limit returns all the rows
+	var limit *int
+	if params.Limit > 0 {
+		limit = &params.Limit
+	}
+
+	var ents []entities.QuarantinedTick
+	err := a.db.SelectContext(ctx, &ents, `
+		SELECT forwardtest_id, exchange, pair, time, price, rule, reason, quarantined_at
+		FROM forwardtest_quarantined_ticks
+		WHERE forwardtest_id = $1
+		ORDER BY quarantined_at DESC, id DESC
+		LIMIT $2
+	`, params.ForwardtestID, limit)
+	if err != nil {
+		return db.ListQuarantinedTicksActivityResult{}, fmt.Errorf("querying quarantined ticks rows: %w", err)
+	}
+
+	models := make([]forwardtest.QuarantinedTick, 0, len(ents))
+	for _, entity := range ents {
+		model, err := entity.ToModel()
+		if err != nil {
+			return db.ListQuarantinedTicksActivityResult{},
+				fmt.Errorf("converting quarantined tick entity to model: %w", err)
+		}
+		models = append(models, model)
+	}
+
+	return db.ListQuarantinedTicksActivityResult{
+		Ticks: models,
+	}, nil
+}
@@ -0,0 +1,243 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
+	"github.com/google/uuid"
+)
+
+// CreateSubscriptionActivity saves a forwardtest subscription in the database.
+// Nothing is done if the subscription already exists.
+func (a *Activities) CreateSubscriptionActivity(
+	ctx context.Context,
+	params db.CreateSubscriptionActivityParams,
+) (db.CreateSubscriptionActivityResult, error) {
+	// Check ID is not nil
+	if params.Subscription.ForwardtestID == uuid.Nil {
+		return db.CreateSubscriptionActivityResult{}, db.ErrNilID
+	}
+
+	entity, err := entities.FromSubscriptionModel(params.Subscription)
+	if err != nil {
+		return db.CreateSubscriptionActivityResult{}, fmt.Errorf("converting subscription model to entity: %w", err)
+	}
+
+	_, err = a.db.NamedExecContext(ctx, `
+		INSERT INTO forwardtest_subscriptions (
+			forwardtest_id, exchange, pair, created_at, last_tick_at, tick_count,
+			period, on_new_candlestick_callback, stale_since, last_price, rejected_tick_count,
+			order_book)
+		VALUES (
+			:forwardtest_id, :exchange, :pair, :created_at, :last_tick_at, :tick_count,
+			:period, :on_new_candlestick_callback, :stale_since, :last_price, :rejected_tick_count,
+			:order_book)
+		ON CONFLICT (forwardtest_id, exchange, pair) DO NOTHING
+	`, entity)
+	if err != nil {
+		return db.CreateSubscriptionActivityResult{}, fmt.Errorf("inserting subscription row: %w", err)
+	}
+
+	return db.CreateSubscriptionActivityResult{}, nil
+}
+
+// ListSubscriptionsActivity lists the subscriptions of a forwardtest from the database.
+func (a *Activities) ListSubscriptionsActivity(
+	ctx context.Context,
+	params db.ListSubscriptionsActivityParams,
+) (db.ListSubscriptionsActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ListSubscriptionsActivityResult{}, db.ErrNilID
+	}
+
+	var ents []entities.Subscription
+	err := a.db.SelectContext(ctx, &ents, `
+		SELECT *
+		FROM forwardtest_subscriptions
+		WHERE forwardtest_id = $1
+		ORDER BY created_at ASC, exchange ASC, pair ASC
+	`, params.ForwardtestID)
+	if err != nil {
+		return db.ListSubscriptionsActivityResult{}, fmt.Errorf("querying subscriptions rows: %w", err)
+	}
+
+	models := make([]forwardtest.Subscription, 0, len(ents))
+	for _, entity := range ents {
+		model, err := entity.ToModel()
+		if err != nil {
+			return db.ListSubscriptionsActivityResult{}, fmt.Errorf("converting subscription entity to model: %w", err)
+		}
+		models = append(models, model)
+	}
+
+	return db.ListSubscriptionsActivityResult{
+		Subscriptions: models,
+	}, nil
+}
+
+// GetSubscriptionActivity gets the subscription of a forwardtest to a pair
+// from the database, if it exists.
+func (a *Activities) GetSubscriptionActivity(
+	ctx context.Context,
+	params db.GetSubscriptionActivityParams,
+) (db.GetSubscriptionActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.GetSubscriptionActivityResult{}, db.ErrNilID
+	}
+
+	var entity entities.Subscription
+	err := a.db.GetContext(ctx, &entity, `
+		SELECT *
+		FROM forwardtest_subscriptions
+		WHERE forwardtest_id = $1 AND exchange = $2 AND pair = $3
+	`, params.ForwardtestID, params.Exchange, params.Pair)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.GetSubscriptionActivityResult{}, nil
+	} else if err != nil {
+		return db.GetSubscriptionActivityResult{}, fmt.Errorf("querying subscription row: %w", err)
+	}
+
+	model, err := entity.ToModel()
+	if err != nil {
+		return db.GetSubscriptionActivityResult{}, fmt.Errorf("converting subscription entity to model: %w", err)
+	}
+
+	return db.GetSubscriptionActivityResult{
+		Subscription: &model,
+	}, nil
+}
+
+// UpdateSubscriptionLastTickActivity sets the time and price of the last tick
+// received on a forwardtest subscription, increments its tick count and clears
+// its stale mark. The updated subscription is returned, if it exists.
+func (a *Activities) UpdateSubscriptionLastTickActivity(
+	ctx context.Context,
+	params db.UpdateSubscriptionLastTickActivityParams,
+) (db.UpdateSubscriptionLastTickActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.UpdateSubscriptionLastTickActivityResult{}, db.ErrNilID
+	}
+
+	var entity entities.Subscription
+	err := a.db.GetContext(ctx, &entity, `
+		UPDATE forwardtest_subscriptions
+		SET last_tick_at = GREATEST(COALESCE(last_tick_at, $1), $1),
+			last_price = CASE WHEN last_tick_at IS NULL OR last_tick_at <= $1
+				THEN $5 ELSE last_price END,
+			tick_count = tick_count + 1,
+			stale_since = NULL
+		WHERE forwardtest_id = $2 AND exchange = $3 AND pair = $4
+		RETURNING *
+	`, params.Time, params.ForwardtestID, params.Exchange, params.Pair, params.Price)
+	if errors.Is(err, sql.ErrNoRows) {
+		return db.UpdateSubscriptionLastTickActivityResult{}, nil
+	} else if err != nil {
+		return db.UpdateSubscriptionLastTickActivityResult{}, fmt.Errorf("updating subscription row: %w", err)
+	}
+
+	model, err := entity.ToModel()
+	if err != nil {
+		return db.UpdateSubscriptionLastTickActivityResult{}, fmt.Errorf("converting subscription entity to model: %w", err)
+	}
+
+	return db.UpdateSubscriptionLastTickActivityResult{
+		Subscription: &model,
+	}, nil
+}
+
+// DeleteSubscriptionActivity deletes a forwardtest subscription from the database.
+// Nothing is done if the subscription does not exist.
+func (a *Activities) DeleteSubscriptionActivity(
+	ctx context.Context,
+	params db.DeleteSubscriptionActivityParams,
+) (db.DeleteSubscriptionActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.DeleteSubscriptionActivityResult{}, db.ErrNilID
+	}
+
+	_, err := a.db.ExecContext(ctx, `
+		DELETE FROM forwardtest_subscriptions
+		WHERE forwardtest_id = $1 AND exchange = $2 AND pair = $3
+	`, params.ForwardtestID, params.Exchange, params.Pair)
+	if err != nil {
+		return db.DeleteSubscriptionActivityResult{}, fmt.Errorf("deleting subscription row: %w", err)
+	}
+
+	return db.DeleteSubscriptionActivityResult{}, nil
+}
+
+// MarkSubscriptionStaleActivity marks the price feed of a forwardtest
+// subscription as stale, if it is not already.
+func (a *Activities) MarkSubscriptionStaleActivity(
+	ctx context.Context,
+	params db.MarkSubscriptionStaleActivityParams,
+) (db.MarkSubscriptionStaleActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.MarkSubscriptionStaleActivityResult{}, db.ErrNilID
+	}
+
+	res, err := a.db.ExecContext(ctx, `
+		UPDATE forwardtest_subscriptions
+		SET stale_since = $1
+		WHERE forwardtest_id = $2 AND exchange = $3 AND pair = $4 AND stale_since IS NULL
+	`, params.Since, params.ForwardtestID, params.Exchange, params.Pair)
+	if err != nil {
+		return db.MarkSubscriptionStaleActivityResult{}, fmt.Errorf("updating subscription row: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return db.MarkSubscriptionStaleActivityResult{}, fmt.Errorf("counting updated subscription rows: %w", err)
+	}
+
+	return db.MarkSubscriptionStaleActivityResult{
+		Marked: count > 0,
+	}, nil
+}
+
+// UpdateSubscriptionOrderBookActivity sets the last order book snapshot of a
+// forwardtest subscription, unless a more recent one is already saved.
+func (a *Activities) UpdateSubscriptionOrderBookActivity(
+	ctx context.Context,
+	params db.UpdateSubscriptionOrderBookActivityParams,
+) (db.UpdateSubscriptionOrderBookActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.UpdateSubscriptionOrderBookActivityResult{}, db.ErrNilID
+	}
+
+	book, err := json.Marshal(entities.FromOrderBookModel(params.OrderBook))
+	if err != nil {
+		return db.UpdateSubscriptionOrderBookActivityResult{}, fmt.Errorf("converting order book model to entity: %w", err)
+	}
+
+	res, err := a.db.ExecContext(ctx, `
+		UPDATE forwardtest_subscriptions
+		SET order_book = $1
+		WHERE forwardtest_id = $2 AND exchange = $3 AND pair = $4
+			AND (order_book IS NULL OR (order_book->>'time')::TIMESTAMPTZ <= $5)
+	`, book, params.ForwardtestID, params.Exchange, params.Pair, params.OrderBook.Time)
+	if err != nil {
+		return db.UpdateSubscriptionOrderBookActivityResult{}, fmt.Errorf("updating subscription row: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return db.UpdateSubscriptionOrderBookActivityResult{}, fmt.Errorf("counting updated subscription rows: %w", err)
+	}
+
+	return db.UpdateSubscriptionOrderBookActivityResult{
+		Updated: count > 0,
+	}, nil
+}
@@ -0,0 +1,73 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+)
+
+// RecordTickActivity saves a tick delivered to a forwardtest.
+func (a *Activities) RecordTickActivity(
+	ctx context.Context,
+	params db.RecordTickActivityParams,
+) (db.RecordTickActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.RecordTickActivityResult{}, db.ErrNilID
+	}
+
+	entity := entities.FromRecordedTickModel(params.ForwardtestID, params.Tick)
+	_, err := a.db.NamedExecContext(ctx, `
+		INSERT INTO forwardtest_ticks (forwardtest_id, exchange, pair, time, price)
+		VALUES (:forwardtest_id, :exchange, :pair, :time, :price)
+	`, entity)
+	if err != nil {
+		return db.RecordTickActivityResult{}, fmt.Errorf("inserting recorded tick row: %w", err)
+	}
+
+	return db.RecordTickActivityResult{}, nil
+}
+
+// ListRecordedTicksActivity lists the ticks recorded on a forwardtest, in the
+// order they have been delivered.
+func (a *Activities) ListRecordedTicksActivity(
+	ctx context.Context,
+	params db.ListRecordedTicksActivityParams,
+) (db.ListRecordedTicksActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ListRecordedTicksActivityResult{}, db.ErrNilID
+	}
+
+	// A NULL limit returns all the rows
+	var limit *int
+	if params.Limit > 0 {
+		limit = &params.Limit
+	}
+
+	var ents []entities.RecordedTick
+	err := a.db.SelectContext(ctx, &ents, `
+		SELECT forwardtest_id, exchange, pair, time, price
+		FROM forwardtest_ticks
+		WHERE forwardtest_id = $1
+		ORDER BY id ASC
+		OFFSET $2
+		LIMIT $3
+	`, params.ForwardtestID, params.Offset, limit)
+	if err != nil {
+		return db.ListRecordedTicksActivityResult{}, fmt.Errorf("querying recorded ticks rows: %w", err)
+	}
+
+	ticks := make([]tick.Tick, 0, len(ents))
+	for _, entity := range ents {
+		ticks = append(ticks, entity.ToModel())
+	}
+
+	return db.ListRecordedTicksActivityResult{
+		Ticks: ticks,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package sql
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/db/sql/entities"
+	"github.com/google/uuid"
+)
+
+// CreateTimerActivity saves a forwardtest timer in the database. Nothing is
+// done if a timer with the same name already exists on the forwardtest.
+func (a *Activities) CreateTimerActivity(
+	ctx context.Context,
+	params db.CreateTimerActivityParams,
+) (db.CreateTimerActivityResult, error) {
+	// Check ID is not nil
+	if params.Timer.ForwardtestID == uuid.Nil {
+		return db.CreateTimerActivityResult{}, db.ErrNilID
+	}
+
+	entity, err := entities.FromTimerModel(params.Timer)
+	if err != nil {
+		return db.CreateTimerActivityResult{}, fmt.Errorf("converting timer model to entity: %w", err)
+	}
+
+	res, err := a.db.NamedExecContext(ctx, `
+		INSERT INTO forwardtest_timers (forwardtest_id, name, interval, cron, callback, created_at)
+		VALUES (:forwardtest_id, :name, :interval, :cron, :callback, :created_at)
+		ON CONFLICT (forwardtest_id, name) DO NOTHING
+	`, entity)
+	if err != nil {
+		return db.CreateTimerActivityResult{}, fmt.Errorf("inserting timer row: %w", err)
+	}
+
+	count, err := res.RowsAffected()
+	if err != nil {
+		return db.CreateTimerActivityResult{}, fmt.Errorf("counting inserted timer rows: %w", err)
+	}
+
+	return db.CreateTimerActivityResult{
+		Created: count > 0,
+	}, nil
+}
+
+// ListTimersActivity lists the timers of a forwardtest from the database.
+func (a *Activities) ListTimersActivity(
+	ctx context.Context,
+	params db.ListTimersActivityParams,
+) (db.ListTimersActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.ListTimersActivityResult{}, db.ErrNilID
+	}
+
+	var ents []entities.Timer
+	err := a.db.SelectContext(ctx, &ents, `
+		SELECT *
+		FROM forwardtest_timers
+		WHERE forwardtest_id = $1
+		ORDER BY created_at ASC, name ASC
+	`, params.ForwardtestID)
+	if err != nil {
+		return db.ListTimersActivityResult{}, fmt.Errorf("querying timers rows: %w", err)
+	}
+
+	models := make([]forwardtest.Timer, 0, len(ents))
+	for _, entity := range ents {
+		model, err := entity.ToModel()
+		if err != nil {
+			return db.ListTimersActivityResult{}, fmt.Errorf("converting timer entity to model: %w", err)
+		}
+		models = append(models, model)
+	}
+
+	return db.ListTimersActivityResult{
+		Timers: models,
+	}, nil
+}
+
+// DeleteTimerActivity deletes a forwardtest timer from the database.
+// Nothing is done if the timer does not exist.
+func (a *Activities) DeleteTimerActivity(
+	ctx context.Context,
+	params db.DeleteTimerActivityParams,
+) (db.DeleteTimerActivityResult, error) {
+	// Check ID is not nil
+	if params.ForwardtestID == uuid.Nil {
+		return db.DeleteTimerActivityResult{}, db.ErrNilID
+	}
+
+	_, err := a.db.ExecContext(ctx, `
+		DELETE FROM forwardtest_timers
+		WHERE forwardtest_id = $1 AND name = $2
+	`, params.ForwardtestID, params.Name)
+	if err != nil {
+		return db.DeleteTimerActivityResult{}, fmt.Errorf("deleting timer row: %w", err)
+	}
+
+	return db.DeleteTimerActivityResult{}, nil
+}
@@ -4,9 +4,12 @@ import (
 	"context"
 	"time"
 
+	"github.com/cryptellation/candlesticks/pkg/period"
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
 	"github.com/cryptellation/runtime"
 	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
+	"github.com/cryptellation/ticks/pkg/tick"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/suite"
 )
@@ -68,6 +71,75 @@ func (suite *ForwardtestSuite) TestCreateReadForwardtestActivities() {
 	suite.Require().Equal(ft.Status, rp.Forwardtest.Status)
 }
 
+// TestCreateReadForwardtestRiskLimits tests that the risk limits are persisted.
+func (suite *ForwardtestSuite) TestCreateReadForwardtestRiskLimits() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"USDT": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Risk: forwardtest.RiskLimits{
+			MaxOrderNotional: 500,
+			MaxPositions:     map[string]float64{"BTC": 1},
+			MaxOpenOrders:    3,
+			AllowedPairs:     map[string][]string{"exchange": {"BTC-USDT"}},
+			PairRules: map[string]forwardtest.PairRules{
+				"BTC-USDT": {MinQuantity: 0.001, StepSize: 0.001},
+			},
+		},
+		Status: forwardtest.StatusReady,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+	rp, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.Risk, rp.Forwardtest.Risk)
+}
+
+// TestCreateReadForwardtestOrderTriggers tests that the ticks that triggered
+// the orders are persisted.
+func (suite *ForwardtestSuite) TestCreateReadForwardtestOrderTriggers() {
+	orderID := uuid.New()
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"USDT": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		OrderTriggers: map[uuid.UUID]tick.Tick{
+			orderID: {
+				Time:     time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+				Pair:     "ETH-USDT",
+				Price:    100,
+				Exchange: "exchange",
+			},
+		},
+		Status: forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+	rp, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.OrderTriggers, rp.Forwardtest.OrderTriggers)
+}
+
 // TestListForwardtestsActivity tests the list operation.
 func (suite *ForwardtestSuite) TestListForwardtestsActivity() {
 	ft1 := forwardtest.Forwardtest{
@@ -176,6 +248,79 @@ func (suite *ForwardtestSuite) TestUpdateForwardtestActivity() {
 	suite.Require().Equal(ft2.Status, rp2.Forwardtest.Status)
 }
 
+// TestSaveForwardtestOrdersActivity tests that orders are only saved once and
+// never overwrite a concurrent update.
+func (suite *ForwardtestSuite) TestSaveForwardtestOrdersActivity() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"DAI": 1000}},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+	read := suite.readTestForwardtest(ft.ID)
+
+	// Save an order
+	o, other := newTestOrder(), newTestOrder()
+	suite.Require().True(suite.saveTestOrders(read, o))
+
+	// Saving it again from the previous state does nothing
+	suite.Require().False(suite.saveTestOrders(read, o, o))
+
+	// Saving another order from the previous state does nothing either
+	suite.Require().False(suite.saveTestOrders(read, other))
+
+	current := suite.readTestForwardtest(ft.ID)
+	suite.Require().Len(current.Orders, 1)
+	suite.Require().Equal(o.ID, current.Orders[0].ID)
+
+	// Saving it from the current state succeeds
+	suite.Require().True(suite.saveTestOrders(current, other))
+}
+
+// readTestForwardtest reads a forwardtest from the database.
+func (suite *ForwardtestSuite) readTestForwardtest(id uuid.UUID) forwardtest.Forwardtest {
+	rp, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: id,
+	})
+	suite.Require().NoError(err)
+	return rp.Forwardtest
+}
+
+// saveTestOrders saves the forwardtest with the orders appended to it and
+// returns if they have been saved.
+func (suite *ForwardtestSuite) saveTestOrders(ft forwardtest.Forwardtest, orders ...order.Order) bool {
+	ids := make([]uuid.UUID, len(orders))
+	for i, o := range orders {
+		ids[i] = o.ID
+	}
+
+	ft.Orders = append(append([]order.Order{}, ft.Orders...), orders...)
+	res, err := suite.DB.SaveForwardtestOrdersActivity(context.Background(), SaveForwardtestOrdersActivityParams{
+		Forwardtest: ft,
+		OrderIDs:    ids,
+	})
+	suite.Require().NoError(err)
+	return res.Saved
+}
+
+// newTestOrder creates a market order for the tests.
+func newTestOrder() order.Order {
+	return order.Order{
+		ID:       uuid.New(),
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "exchange",
+		Pair:     "ETH-DAI",
+		Quantity: 1,
+	}
+}
+
 // TestDeleteForwardtestActivity tests the delete operation.
 func (suite *ForwardtestSuite) TestDeleteForwardtestActivity() {
 	ft := forwardtest.Forwardtest{
@@ -202,4 +347,879 @@ func (suite *ForwardtestSuite) TestDeleteForwardtestActivity() {
 		ID: ft.ID,
 	})
 	suite.Error(err)
+	suite.True(IsRecordNotFound(err))
+}
+
+// TestListForwardtestsActivityArchived tests that archived forwardtests are
+// only listed on demand.
+func (suite *ForwardtestSuite) TestListForwardtestsActivityArchived() {
+	ft1 := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusFinished,
+		Archived:  true,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft1,
+	})
+	suite.Require().NoError(err)
+	ft2 := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1500,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusReady,
+	}
+	_, err = suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft2,
+	})
+	suite.Require().NoError(err)
+
+	rp, err := suite.DB.ListForwardtestsActivity(context.Background(), ListForwardtestsActivityParams{})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Forwardtests, 1)
+	suite.Require().Equal(ft2.ID, rp.Forwardtests[0].ID)
+
+	rp, err = suite.DB.ListForwardtestsActivity(context.Background(), ListForwardtestsActivityParams{
+		IncludeArchived: true,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Forwardtests, 2)
+	suite.Require().True(rp.Forwardtests[1].Archived)
+}
+
+// TestSubscriptionActivities tests the subscription operations.
+func (suite *ForwardtestSuite) TestSubscriptionActivities() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Create subscription twice to check it is idempotent
+	sub := forwardtest.Subscription{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+		CreatedAt:     time.Unix(0, 0).UTC(),
+	}
+	for i := 0; i < 2; i++ {
+		_, err = suite.DB.CreateSubscriptionActivity(context.Background(), CreateSubscriptionActivityParams{
+			Subscription: sub,
+		})
+		suite.Require().NoError(err)
+	}
+
+	rp, err := suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 1)
+	suite.Require().Equal("ETH-USDT", rp.Subscriptions[0].Pair)
+	suite.Require().Nil(rp.Subscriptions[0].LastTickAt)
+
+	// Update last tick, an older tick should not move it back
+	lastTick := time.Unix(120, 0).UTC()
+	for _, t := range []time.Time{lastTick, time.Unix(60, 0).UTC()} {
+		up, err := suite.DB.UpdateSubscriptionLastTickActivity(context.Background(), UpdateSubscriptionLastTickActivityParams{
+			ForwardtestID: ft.ID,
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+			Time:          t,
+		})
+		suite.Require().NoError(err)
+		suite.Require().NotNil(up.Subscription)
+	}
+
+	// Updating an unknown subscription returns no subscription
+	up, err := suite.DB.UpdateSubscriptionLastTickActivity(context.Background(), UpdateSubscriptionLastTickActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "BTC-USDT",
+		Time:          lastTick,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Nil(up.Subscription)
+
+	rp, err = suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 1)
+	suite.Require().NotNil(rp.Subscriptions[0].LastTickAt)
+	suite.Require().WithinDuration(lastTick, *rp.Subscriptions[0].LastTickAt, time.Millisecond)
+	suite.Require().Equal(int64(2), rp.Subscriptions[0].TickCount)
+
+	// Delete subscription
+	_, err = suite.DB.DeleteSubscriptionActivity(context.Background(), DeleteSubscriptionActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+	})
+	suite.Require().NoError(err)
+
+	rp, err = suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 0)
+}
+
+// TestCandlestickSubscription tests that the candlesticks delivery of a
+// subscription is persisted.
+func (suite *ForwardtestSuite) TestCandlestickSubscription() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	callback := runtime.CallbackWorkflow{
+		Name:          "test-candlestick-workflow",
+		TaskQueueName: "test-queue",
+	}
+	_, err = suite.DB.CreateSubscriptionActivity(context.Background(), CreateSubscriptionActivityParams{
+		Subscription: forwardtest.Subscription{
+			ForwardtestID:            ft.ID,
+			Exchange:                 "exchange",
+			Pair:                     "ETH-USDT",
+			CreatedAt:                time.Unix(0, 0).UTC(),
+			Period:                   period.M15.Opt(),
+			OnNewCandlestickCallback: &callback,
+		},
+	})
+	suite.Require().NoError(err)
+
+	rp, err := suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 1)
+	suite.Require().NotNil(rp.Subscriptions[0].Period)
+	suite.Require().Equal(period.M15, *rp.Subscriptions[0].Period)
+	suite.Require().NotNil(rp.Subscriptions[0].OnNewCandlestickCallback)
+	suite.Require().Equal(callback, *rp.Subscriptions[0].OnNewCandlestickCallback)
+}
+
+// TestGetSubscription tests that a single subscription can be retrieved.
+func (suite *ForwardtestSuite) TestGetSubscription() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.DB.CreateSubscriptionActivity(context.Background(), CreateSubscriptionActivityParams{
+		Subscription: forwardtest.Subscription{
+			ForwardtestID: ft.ID,
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+			CreatedAt:     time.Unix(0, 0).UTC(),
+		},
+	})
+	suite.Require().NoError(err)
+
+	rp, err := suite.DB.GetSubscriptionActivity(context.Background(), GetSubscriptionActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+	})
+	suite.Require().NoError(err)
+	suite.Require().NotNil(rp.Subscription)
+	suite.Require().Equal("ETH-USDT", rp.Subscription.Pair)
+
+	// Getting an unknown subscription returns no subscription
+	rp, err = suite.DB.GetSubscriptionActivity(context.Background(), GetSubscriptionActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "BTC-USDT",
+	})
+	suite.Require().NoError(err)
+	suite.Require().Nil(rp.Subscription)
+}
+
+// TestStaleSubscription tests that a subscription is marked as stale only once
+// and that the mark is cleared by a new tick.
+func (suite *ForwardtestSuite) TestStaleSubscription() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		OptionalCallbacks: forwardtest.OptionalCallbacks{
+			OnFeedStaleCallback: &runtime.CallbackWorkflow{
+				Name:          "test-feed-stale-workflow",
+				TaskQueueName: "test-queue",
+			},
+		},
+		FeedHealth: forwardtest.FeedHealth{
+			StaleAfter:           time.Minute,
+			BlockOrdersWhenStale: true,
+		},
+		Status: forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Check feed health settings are persisted
+	rft, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.FeedHealth, rft.Forwardtest.FeedHealth)
+	suite.Require().Equal(ft.OptionalCallbacks, rft.Forwardtest.OptionalCallbacks)
+
+	_, err = suite.DB.CreateSubscriptionActivity(context.Background(), CreateSubscriptionActivityParams{
+		Subscription: forwardtest.Subscription{
+			ForwardtestID: ft.ID,
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+			CreatedAt:     time.Unix(0, 0).UTC(),
+		},
+	})
+	suite.Require().NoError(err)
+
+	// Mark twice, only the first one should mark the subscription
+	since := time.Unix(300, 0).UTC()
+	for _, expected := range []bool{true, false} {
+		mp, err := suite.DB.MarkSubscriptionStaleActivity(context.Background(), MarkSubscriptionStaleActivityParams{
+			ForwardtestID: ft.ID,
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+			Since:         since,
+		})
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, mp.Marked)
+	}
+
+	rp, err := suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 1)
+	suite.Require().NotNil(rp.Subscriptions[0].StaleSince)
+	suite.Require().WithinDuration(since, *rp.Subscriptions[0].StaleSince, time.Millisecond)
+
+	// A new tick clears the mark
+	up, err := suite.DB.UpdateSubscriptionLastTickActivity(context.Background(), UpdateSubscriptionLastTickActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+		Time:          time.Unix(360, 0).UTC(),
+	})
+	suite.Require().NoError(err)
+	suite.Require().NotNil(up.Subscription)
+	suite.Require().Nil(up.Subscription.StaleSince)
+}
+
+// TestQuarantineTickActivities tests that the quarantined ticks are saved and
+// counted on their subscription.
+func (suite *ForwardtestSuite) TestQuarantineTickActivities() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		TickFilter: forwardtest.TickFilter{
+			RejectNonPositivePrice: true,
+			MaxJump:                0.5,
+			RejectOutOfOrder:       true,
+		},
+		Status: forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Check tick filter is persisted
+	rft, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.TickFilter, rft.Forwardtest.TickFilter)
+
+	_, err = suite.DB.CreateSubscriptionActivity(context.Background(), CreateSubscriptionActivityParams{
+		Subscription: forwardtest.Subscription{
+			ForwardtestID: ft.ID,
+			Exchange:      "exchange",
+			Pair:          "ETH-USDT",
+			CreatedAt:     time.Unix(0, 0).UTC(),
+		},
+	})
+	suite.Require().NoError(err)
+
+	// Accept a tick to set the last price
+	up, err := suite.DB.UpdateSubscriptionLastTickActivity(context.Background(), UpdateSubscriptionLastTickActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+		Time:          time.Unix(60, 0).UTC(),
+		Price:         1000,
+	})
+	suite.Require().NoError(err)
+	suite.Require().NotNil(up.Subscription)
+	suite.Require().Equal(1000.0, up.Subscription.LastPrice)
+
+	// Quarantine two ticks
+	for i, price := range []float64{0, 2000} {
+		_, err = suite.DB.QuarantineTickActivity(context.Background(), QuarantineTickActivityParams{
+			Tick: forwardtest.QuarantinedTick{
+				ForwardtestID: ft.ID,
+				Tick: tick.Tick{
+					Time:     time.Unix(int64(120+i*60), 0).UTC(),
+					Exchange: "exchange",
+					Pair:     "ETH-USDT",
+					Price:    price,
+				},
+				Rule:          forwardtest.TickRuleMaxJump,
+				Reason:        "test",
+				QuarantinedAt: time.Unix(int64(120+i*60), 0).UTC(),
+			},
+		})
+		suite.Require().NoError(err)
+	}
+
+	// Check the count on the subscription
+	rp, err := suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 1)
+	suite.Require().Equal(int64(2), rp.Subscriptions[0].RejectedTickCount)
+	suite.Require().Equal(int64(1), rp.Subscriptions[0].TickCount)
+
+	// List the quarantined ticks, the most recent first
+	lq, err := suite.DB.ListQuarantinedTicksActivity(context.Background(), ListQuarantinedTicksActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(lq.Ticks, 2)
+	suite.Require().Equal(2000.0, lq.Ticks[0].Tick.Price)
+	suite.Require().Equal(forwardtest.TickRuleMaxJump, lq.Ticks[0].Rule)
+
+	lq, err = suite.DB.ListQuarantinedTicksActivity(context.Background(), ListQuarantinedTicksActivityParams{
+		ForwardtestID: ft.ID,
+		Limit:         1,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(lq.Ticks, 1)
+}
+
+// TestRecordedTicksActivities tests that the recorded ticks are listed in the
+// order they have been recorded.
+func (suite *ForwardtestSuite) TestRecordedTicksActivities() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks:   createTestCallbacks(),
+		RecordTicks: true,
+		Status:      forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Record ticks, the last one being older than the others
+	ticks := []tick.Tick{
+		{Time: time.Unix(60, 0).UTC(), Exchange: "exchange", Pair: "ETH-USDT", Price: 1000},
+		{Time: time.Unix(120, 0).UTC(), Exchange: "exchange", Pair: "BTC-USDT", Price: 20000},
+		{Time: time.Unix(30, 0).UTC(), Exchange: "exchange", Pair: "ETH-USDT", Price: 1001},
+	}
+	for _, t := range ticks {
+		_, err := suite.DB.RecordTickActivity(context.Background(), RecordTickActivityParams{
+			ForwardtestID: ft.ID,
+			Tick:          t,
+		})
+		suite.Require().NoError(err)
+	}
+
+	// List all ticks
+	res, err := suite.DB.ListRecordedTicksActivity(context.Background(), ListRecordedTicksActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(res.Ticks, 3)
+	for i, t := range ticks {
+		suite.Require().Equal(t.Pair, res.Ticks[i].Pair)
+		suite.Require().Equal(t.Price, res.Ticks[i].Price)
+		suite.Require().WithinDuration(t.Time, res.Ticks[i].Time, time.Millisecond)
+	}
+
+	// List with offset and limit
+	res, err = suite.DB.ListRecordedTicksActivity(context.Background(), ListRecordedTicksActivityParams{
+		ForwardtestID: ft.ID,
+		Offset:        1,
+		Limit:         1,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(res.Ticks, 1)
+	suite.Require().Equal("BTC-USDT", res.Ticks[0].Pair)
+
+	// Check recording is persisted on the forwardtest
+	rft, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().True(rft.Forwardtest.RecordTicks)
+	suite.Require().False(rft.Forwardtest.IsReplay())
+}
+
+// createForwardtestWithSubscription saves the forwardtest with a subscription
+// to the pair.
+func (suite *ForwardtestSuite) createForwardtestWithSubscription(ft forwardtest.Forwardtest, exchange, pair string) {
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.DB.CreateSubscriptionActivity(context.Background(), CreateSubscriptionActivityParams{
+		Subscription: forwardtest.Subscription{
+			ForwardtestID: ft.ID,
+			Exchange:      exchange,
+			Pair:          pair,
+			CreatedAt:     time.Unix(0, 0).UTC(),
+		},
+	})
+	suite.Require().NoError(err)
+}
+
+// TestOrderBookActivity tests that the order book of a subscription is only
+// replaced by more recent snapshots.
+func (suite *ForwardtestSuite) TestOrderBookActivity() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {
+				Balances: map[string]float64{
+					"DAI": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Execution: forwardtest.ExecutionPolicy{Mode: forwardtest.ExecutionModeBidAsk, MaxBookAge: time.Minute},
+		Status:    forwardtest.StatusRunning,
+	}
+	suite.createForwardtestWithSubscription(ft, "exchange", "ETH-USDT")
+
+	// Update with a snapshot
+	book := forwardtest.OrderBook{
+		Time: time.Unix(60, 0).UTC(),
+		Bids: []forwardtest.BookLevel{{Price: 999, Quantity: 1}},
+		Asks: []forwardtest.BookLevel{{Price: 1001, Quantity: 1}, {Price: 1002, Quantity: 2}},
+	}
+	res, err := suite.DB.UpdateSubscriptionOrderBookActivity(context.Background(), UpdateSubscriptionOrderBookActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+		OrderBook:     book,
+	})
+	suite.Require().NoError(err)
+	suite.Require().True(res.Updated)
+
+	// Older snapshots are ignored
+	res, err = suite.DB.UpdateSubscriptionOrderBookActivity(context.Background(), UpdateSubscriptionOrderBookActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "ETH-USDT",
+		OrderBook: forwardtest.OrderBook{
+			Time: time.Unix(30, 0).UTC(),
+			Asks: []forwardtest.BookLevel{{Price: 900}},
+		},
+	})
+	suite.Require().NoError(err)
+	suite.Require().False(res.Updated)
+
+	// Unknown subscriptions are not updated
+	res, err = suite.DB.UpdateSubscriptionOrderBookActivity(context.Background(), UpdateSubscriptionOrderBookActivityParams{
+		ForwardtestID: ft.ID,
+		Exchange:      "exchange",
+		Pair:          "BTC-USDT",
+		OrderBook:     book,
+	})
+	suite.Require().NoError(err)
+	suite.Require().False(res.Updated)
+
+	// Check the saved snapshot
+	suite.requireOrderBook(ft.ID, book)
+
+	// Check the execution policy is persisted on the forwardtest
+	rft, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.Execution, rft.Forwardtest.Execution)
+}
+
+// requireOrderBook checks the order book of the only subscription of the forwardtest.
+func (suite *ForwardtestSuite) requireOrderBook(forwardtestID uuid.UUID, book forwardtest.OrderBook) {
+	rp, err := suite.DB.ListSubscriptionsActivity(context.Background(), ListSubscriptionsActivityParams{
+		ForwardtestID: forwardtestID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(rp.Subscriptions, 1)
+	suite.Require().NotNil(rp.Subscriptions[0].OrderBook)
+	suite.Require().Equal(book.Asks, rp.Subscriptions[0].OrderBook.Asks)
+	suite.Require().Equal(book.Bids, rp.Subscriptions[0].OrderBook.Bids)
+	suite.Require().WithinDuration(book.Time, rp.Subscriptions[0].OrderBook.Time, time.Millisecond)
+}
+
+// TestTimerActivities tests the creation, listing and deletion of timers.
+func (suite *ForwardtestSuite) TestTimerActivities() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"DAI": 1000}},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Create timers
+	timers := []forwardtest.Timer{
+		{
+			ForwardtestID: ft.ID,
+			Name:          "rebalance",
+			Interval:      time.Hour,
+			Callback:      runtime.CallbackWorkflow{Name: "rebalance-workflow", TaskQueueName: "test-queue"},
+			CreatedAt:     time.Unix(0, 0).UTC(),
+		}, {
+			ForwardtestID: ft.ID,
+			Name:          "report",
+			Cron:          "0 0 * * *",
+			Callback:      runtime.CallbackWorkflow{Name: "report-workflow", TaskQueueName: "test-queue"},
+			CreatedAt:     time.Unix(60, 0).UTC(),
+		},
+	}
+	for _, t := range timers {
+		res, err := suite.DB.CreateTimerActivity(context.Background(), CreateTimerActivityParams{
+			Timer: t,
+		})
+		suite.Require().NoError(err)
+		suite.Require().True(res.Created)
+	}
+
+	// Create a timer with the same name
+	res, err := suite.DB.CreateTimerActivity(context.Background(), CreateTimerActivityParams{
+		Timer: timers[0],
+	})
+	suite.Require().NoError(err)
+	suite.Require().False(res.Created)
+
+	// List timers
+	lr, err := suite.DB.ListTimersActivity(context.Background(), ListTimersActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(timers, lr.Timers)
+
+	// Delete a timer
+	_, err = suite.DB.DeleteTimerActivity(context.Background(), DeleteTimerActivityParams{
+		ForwardtestID: ft.ID,
+		Name:          "rebalance",
+	})
+	suite.Require().NoError(err)
+
+	lr, err = suite.DB.ListTimersActivity(context.Background(), ListTimersActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(timers[1:], lr.Timers)
+}
+
+// TestCallbackErrorActivities tests that the callback errors are saved and
+// counted as consecutive failures on their forwardtest.
+func (suite *ForwardtestSuite) TestCallbackErrorActivities() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"DAI": 1000}},
+		},
+		Callbacks: createTestCallbacks(),
+		OptionalCallbacks: forwardtest.OptionalCallbacks{
+			OnErrorCallback: &runtime.CallbackWorkflow{Name: "error-workflow", TaskQueueName: "test-queue"},
+		},
+		Failures: forwardtest.FailurePolicy{
+			Retries: map[forwardtest.CallbackKind]forwardtest.RetryPolicy{
+				forwardtest.CallbackKindOnNewPrices: {MaxAttempts: 3, InitialInterval: time.Second},
+			},
+			MaxConsecutiveFailures: 5,
+		},
+		Status:       forwardtest.StatusFinished,
+		StatusReason: forwardtest.StatusReasonCallbackFailures,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Record two errors
+	for i, kind := range []forwardtest.CallbackKind{
+		forwardtest.CallbackKindOnNewPrices, forwardtest.CallbackKindOnTimer,
+	} {
+		suite.Require().Equal(i+1, suite.recordCallbackError(ft.ID, kind, time.Unix(int64(60*i), 0).UTC()))
+	}
+
+	// Check the forwardtest with its failures
+	rft, err := suite.DB.ReadForwardtestActivity(context.Background(), ReadForwardtestActivityParams{
+		ID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.OptionalCallbacks, rft.Forwardtest.OptionalCallbacks)
+	suite.Require().Equal(ft.Failures, rft.Forwardtest.Failures)
+	suite.Require().Equal(ft.StatusReason, rft.Forwardtest.StatusReason)
+	suite.Require().Equal(2, rft.Forwardtest.ConsecutiveCallbackFailures)
+
+	// Reset the failures and record a new one
+	_, err = suite.DB.ResetCallbackFailuresActivity(context.Background(), ResetCallbackFailuresActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	failures := suite.recordCallbackError(ft.ID, forwardtest.CallbackKindOnNewPrices, time.Unix(120, 0).UTC())
+	suite.Require().Equal(1, failures)
+
+	// List the errors, the most recent first
+	lr, err := suite.DB.ListCallbackErrorsActivity(context.Background(), ListCallbackErrorsActivityParams{
+		ForwardtestID: ft.ID,
+		Limit:         2,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(lr.Errors, 2)
+	suite.Require().Equal(time.Unix(120, 0).UTC(), lr.Errors[0].Time)
+	suite.Require().Equal(forwardtest.CallbackKindOnTimer, lr.Errors[1].Callback)
+	suite.Require().Equal("callback failed", lr.Errors[1].Error)
+}
+
+// recordCallbackError records a callback error and returns the consecutive
+// failures of the forwardtest.
+func (suite *ForwardtestSuite) recordCallbackError(id uuid.UUID, kind forwardtest.CallbackKind, t time.Time) int {
+	res, err := suite.DB.RecordCallbackErrorActivity(context.Background(), RecordCallbackErrorActivityParams{
+		Error: forwardtest.CallbackError{
+			ForwardtestID: id,
+			Time:          t,
+			Callback:      kind,
+			Error:         "callback failed",
+		},
+	})
+	suite.Require().NoError(err)
+	return res.ConsecutiveFailures
+}
+
+func (suite *ForwardtestSuite) TestEventActivities() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"DAI": 1000}},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	// Record events
+	o := order.Order{
+		ID:       uuid.New(),
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "exchange",
+		Pair:     "ETH-DAI",
+		Quantity: 1,
+	}
+	events := []forwardtest.Event{
+		forwardtest.NewStatusChangedEvent(ft, time.Unix(0, 0).UTC()),
+		forwardtest.NewOrderEvent(ft.ID, time.Unix(60, 0).UTC(), o, &forwardtest.RiskRejectionError{
+			Rule:    forwardtest.RiskRuleMaxPosition,
+			Message: "position above limit",
+		}),
+		forwardtest.NewTicksProcessedEvent(ft.ID, []tick.Tick{
+			{Time: time.Unix(120, 0).UTC(), Pair: "ETH-DAI", Price: 1500, Exchange: "exchange"},
+		}),
+	}
+	for i, e := range events {
+		res, err := suite.DB.RecordEventActivity(context.Background(), RecordEventActivityParams{
+			Event: e,
+		})
+		suite.Require().NoError(err)
+		events[i].ID = res.ID
+	}
+
+	// List all the events, the oldest first
+	lr, err := suite.DB.ListEventsActivity(context.Background(), ListEventsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(events, lr.Events)
+
+	// Resume after the first event
+	lr, err = suite.DB.ListEventsActivity(context.Background(), ListEventsActivityParams{
+		ForwardtestID: ft.ID,
+		After:         events[0].ID,
+		Limit:         1,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal(events[1:2], lr.Events)
+
+	// Following ticks processed events are coalesced at the end of the log
+	res, err := suite.DB.RecordEventActivity(context.Background(), RecordEventActivityParams{
+		Event: forwardtest.NewTicksProcessedEvent(ft.ID, []tick.Tick{
+			{Time: time.Unix(180, 0).UTC(), Pair: "ETH-DAI", Price: 1510, Exchange: "exchange"},
+			{Time: time.Unix(240, 0).UTC(), Pair: "ETH-DAI", Price: 1520, Exchange: "exchange"},
+		}),
+	})
+	suite.Require().NoError(err)
+	suite.Require().Greater(res.ID, events[2].ID)
+
+	lr, err = suite.DB.ListEventsActivity(context.Background(), ListEventsActivityParams{
+		ForwardtestID: ft.ID,
+		After:         events[1].ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(lr.Events, 1)
+	suite.Require().Equal(res.ID, lr.Events[0].ID)
+	suite.Require().Equal(3, lr.Events[0].Ticks)
+	suite.Require().Equal(1520.0, lr.Events[0].LastTick.Price)
+	suite.Require().Equal(time.Unix(240, 0).UTC(), lr.Events[0].Time)
+}
+
+// TestRecordEventReadForwardtest tests that a forwardtest is still read and
+// listed once events have been recorded on it.
+func (suite *ForwardtestSuite) TestRecordEventReadForwardtest() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"DAI": 1000}},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.DB.RecordEventActivity(context.Background(), RecordEventActivityParams{
+		Event: forwardtest.NewStatusChangedEvent(ft, time.Unix(0, 0).UTC()),
+	})
+	suite.Require().NoError(err)
+
+	suite.Require().Equal(ft.ID, suite.readTestForwardtest(ft.ID).ID)
+	lr, err := suite.DB.ListForwardtestsActivity(context.Background(), ListForwardtestsActivityParams{})
+	suite.Require().NoError(err)
+	suite.Require().Len(lr.Forwardtests, 1)
+}
+
+// TestPruneEventsActivity tests that only the old events are pruned.
+func (suite *ForwardtestSuite) TestPruneEventsActivity() {
+	ft := forwardtest.Forwardtest{
+		ID: uuid.New(),
+		Accounts: map[string]account.Account{
+			"exchange": {Balances: map[string]float64{"DAI": 1000}},
+		},
+		Callbacks: createTestCallbacks(),
+		Status:    forwardtest.StatusRunning,
+	}
+	_, err := suite.DB.CreateForwardtestActivity(context.Background(), CreateForwardtestActivityParams{
+		Forwardtest: ft,
+	})
+	suite.Require().NoError(err)
+
+	_, err = suite.DB.RecordEventActivity(context.Background(), RecordEventActivityParams{
+		Event: forwardtest.NewStatusChangedEvent(ft, time.Unix(0, 0).UTC()),
+	})
+	suite.Require().NoError(err)
+
+	// Events recorded within the retention are kept
+	_, err = suite.DB.PruneEventsActivity(context.Background(), PruneEventsActivityParams{
+		Retention: time.Hour,
+	})
+	suite.Require().NoError(err)
+	lr, err := suite.DB.ListEventsActivity(context.Background(), ListEventsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Len(lr.Events, 1)
+
+	// Events recorded before the retention are deleted
+	time.Sleep(10 * time.Millisecond)
+	res, err := suite.DB.PruneEventsActivity(context.Background(), PruneEventsActivityParams{
+		Retention: time.Millisecond,
+	})
+	suite.Require().NoError(err)
+	suite.Require().GreaterOrEqual(res.Deleted, int64(1))
+	lr, err = suite.DB.ListEventsActivity(context.Background(), ListEventsActivityParams{
+		ForwardtestID: ft.ID,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Empty(lr.Events)
 }
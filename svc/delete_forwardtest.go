@@ -0,0 +1,51 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// DeleteForwardtestWorkflow deletes a forwardtest from the database, after
+// removing its tick subscriptions and timers.
+func (wf *workflows) DeleteForwardtestWorkflow(
+	ctx workflow.Context,
+	params api.DeleteForwardtestWorkflowParams,
+) (api.DeleteForwardtestWorkflowResults, error) {
+	logger := workflow.GetLogger(ctx)
+
+	// Read forwardtest from database
+	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.DeleteForwardtestWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// Refuse to delete a running forwardtest, unless forced
+	if ft.Status == forwardtest.StatusRunning && !params.Force {
+		return api.DeleteForwardtestWorkflowResults{},
+			fmt.Errorf("deleting forwardtest %s: %w", params.ForwardtestID, forwardtest.ErrRunning)
+	}
+
+	// Stop prices and timers, as long as they are still persisted; what is
+	// left behind stops once the forwardtest can't be found anymore
+	wf.teardown(ctx, params.ForwardtestID)
+
+	logger.Info("Deleting forwardtest",
+		"forwardtest_id", params.ForwardtestID.String(),
+		"status", ft.Status.String())
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.DeleteForwardtestActivity, db.DeleteForwardtestActivityParams{
+			ID: params.ForwardtestID,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.DeleteForwardtestWorkflowResults{},
+			fmt.Errorf("deleting forwardtest from db: %w", err)
+	}
+
+	return api.DeleteForwardtestWorkflowResults{}, nil
+}
@@ -0,0 +1,293 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	candlesticksapi "github.com/cryptellation/candlesticks/api"
+	"github.com/cryptellation/candlesticks/pkg/candlestick"
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// deliverCandlesticksWorkflowName is the name of the DeliverCandlesticksWorkflow.
+	deliverCandlesticksWorkflowName = "DeliverCandlesticksWorkflow"
+	// candlestickCloseDelay is the delay after a period boundary before
+	// fetching the closed candlestick, to let the exchange settle it.
+	candlestickCloseDelay = 5 * time.Second
+	// candlestickFetchAttempts is the number of attempts to get a closed
+	// candlestick before skipping it.
+	candlestickFetchAttempts = 3
+	// candlesticksBeforeContinueAsNew is the number of periods handled by the
+	// delivery workflow before continuing as new.
+	candlesticksBeforeContinueAsNew = 200
+)
+
+// deliverCandlesticksWorkflowParams is the input of the DeliverCandlesticksWorkflow.
+type deliverCandlesticksWorkflowParams struct {
+	Subscription forwardtest.Subscription
+	// NextOpenTime is the open time of the next candlestick to deliver,
+	// carried over when continuing as new.
+	NextOpenTime *time.Time
+}
+
+// candlesticksWorkflowID returns the workflow ID of the candlesticks delivery
+// of a forwardtest subscription.
+func candlesticksWorkflowID(forwardtestID uuid.UUID, exchange, pair string) string {
+	return fmt.Sprintf("forwardtest-%s-candlesticks-%s-%s", forwardtestID.String(), exchange, pair)
+}
+
+// deliverCandlesticksWorkflow is a private workflow that executes the
+// OnNewCandlestickCallback of a subscription with each closed candlestick,
+// as long as the forwardtest is running.
+func (wf *workflows) deliverCandlesticksWorkflow(
+	ctx workflow.Context,
+	params deliverCandlesticksWorkflowParams,
+) error {
+	logger := workflow.GetLogger(ctx)
+	sub := params.Subscription
+	per := *sub.Period
+
+	// Start with the current candlestick, then follow the delivered ones so
+	// that a late wake up doesn't skip a candlestick
+	openTime := per.RoundTime(workflow.Now(ctx))
+	if params.NextOpenTime != nil {
+		openTime = *params.NextOpenTime
+	}
+
+	for i := 0; ; i++ {
+		// Wait for the candlestick to be closed
+		closeTime := openTime.Add(per.Duration())
+		if wait := closeTime.Add(candlestickCloseDelay).Sub(workflow.Now(ctx)); wait > 0 {
+			if err := workflow.Sleep(ctx, wait); err != nil {
+				return err
+			}
+		}
+
+		// Stop when the forwardtest is not running anymore
+		ft, err := wf.readForwardtestFromDB(ctx, sub.ForwardtestID)
+		if db.IsRecordNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not read forwardtest from db: %w", err)
+		} else if ft.Status != forwardtest.StatusRunning {
+			return nil
+		}
+
+		// Deliver the closed candlestick
+		if err := wf.deliverCandlestick(ctx, &ft, sub, openTime, closeTime); err != nil {
+			logger.Error("Failed to deliver candlestick to forwardtest",
+				"forwardtest_id", sub.ForwardtestID.String(),
+				"exchange", sub.Exchange,
+				"pair", sub.Pair,
+				"period", per.String(),
+				"error", err.Error())
+		}
+
+		openTime = closeTime
+
+		// Continue as new to keep the history small
+		if i+1 >= candlesticksBeforeContinueAsNew || workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			params.NextOpenTime = &openTime
+			return workflow.NewContinueAsNewError(ctx, deliverCandlesticksWorkflowName, params)
+		}
+	}
+}
+
+// deliverCandlestick gets the candlestick opened at the given time and
+// executes the OnNewCandlestickCallback with it.
+func (wf *workflows) deliverCandlestick(
+	ctx workflow.Context,
+	ft *forwardtest.Forwardtest,
+	sub forwardtest.Subscription,
+	openTime, closeTime time.Time,
+) error {
+	cs, ok, err := wf.fetchClosedCandlestick(ctx, sub, openTime)
+	if err != nil {
+		return err
+	} else if !ok {
+		return nil
+	}
+
+	if err := wf.saveCandlestickPrice(ctx, sub, closeTime, cs.Close); err != nil {
+		return err
+	}
+
+	return wf.executeOnNewCandlestickCallback(ctx, ft, sub, cs)
+}
+
+// executeOnNewCandlestickCallback executes the OnNewCandlestickCallback of the
+// subscription with a closed candlestick, then the orders it returns. Its
+// failures are handled with the failure policy of the forwardtest.
+func (wf *workflows) executeOnNewCandlestickCallback(
+	ctx workflow.Context,
+	ft *forwardtest.Forwardtest,
+	sub forwardtest.Subscription,
+	cs candlestick.Candlestick,
+) error {
+	openTime, closeTime := cs.Time, cs.Time.Add(sub.Period.Duration())
+	callback := *sub.OnNewCandlestickCallback
+	opts := workflow.ChildWorkflowOptions{
+		// Unique identifier for this child workflow execution
+		WorkflowID: fmt.Sprintf("forwardtest-%s-on-new-candlestick-%s-%s-%s",
+			sub.ForwardtestID.String(), sub.Exchange, sub.Pair, openTime.UTC().Format(time.RFC3339)),
+		// Task queue where the child workflow will be executed
+		TaskQueue: callback.TaskQueueName,
+		// Maximum time allowed for the child workflow to complete
+		WorkflowExecutionTimeout: time.Second * 30,
+	}
+
+	// Check if the timeout is set
+	if callback.ExecutionTimeout > 0 {
+		opts.WorkflowExecutionTimeout = callback.ExecutionTimeout
+	}
+
+	opts = withCallbackRetries(opts, *ft, forwardtest.CallbackKindOnNewCandlestick)
+	var res api.OnNewCandlestickCallbackWorkflowResults
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		callback.Name,
+		api.OnNewCandlestickCallbackWorkflowParams{
+			Context: runtime.Context{
+				ID:              sub.ForwardtestID,
+				Mode:            runtime.ModeForwardtest,
+				Now:             closeTime,
+				ParentTaskQueue: workflow.GetInfo(ctx).TaskQueueName,
+			},
+			Exchange:    sub.Exchange,
+			Pair:        sub.Pair,
+			Period:      *sub.Period,
+			Candlestick: cs,
+		}).Get(ctx, &res)
+	if err != nil {
+		wf.callbackFailed(ctx, ft, forwardtest.CallbackKindOnNewCandlestick, err)
+		return fmt.Errorf("could not execute OnNewCandlestickCallback workflow: %w", err)
+	}
+	wf.callbackSucceeded(ctx, ft)
+
+	// Execute the orders returned by the callback, if any, at the close price
+	if len(res.Orders) > 0 {
+		return wf.applyCallbackOrders(ctx, ft.ID, []tick.Tick{{
+			Time:     closeTime,
+			Pair:     sub.Pair,
+			Price:    cs.Close,
+			Exchange: sub.Exchange,
+		}}, res.Orders)
+	}
+
+	return nil
+}
+
+// saveCandlestickPrice saves the close price of a closed candlestick as the
+// last price of the subscription, to quote orders and detect stale feeds.
+func (wf *workflows) saveCandlestickPrice(
+	ctx workflow.Context,
+	sub forwardtest.Subscription,
+	closeTime time.Time,
+	price float64,
+) error {
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.UpdateSubscriptionLastTickActivity, db.UpdateSubscriptionLastTickActivityParams{
+			ForwardtestID: sub.ForwardtestID,
+			Exchange:      sub.Exchange,
+			Pair:          sub.Pair,
+			Time:          closeTime,
+			Price:         price,
+		}).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("updating subscription last price: %w", err)
+	}
+
+	return nil
+}
+
+// fetchClosedCandlestick gets the closed candlestick opened at the given time
+// from the candlesticks service. It returns false if the candlestick is still
+// not complete after a few attempts, in which case it is skipped.
+func (wf *workflows) fetchClosedCandlestick(
+	ctx workflow.Context,
+	sub forwardtest.Subscription,
+	openTime time.Time,
+) (candlestick.Candlestick, bool, error) {
+	for attempt := 0; attempt < candlestickFetchAttempts; attempt++ {
+		if attempt > 0 {
+			if err := workflow.Sleep(ctx, candlestickCloseDelay); err != nil {
+				return candlestick.Candlestick{}, false, err
+			}
+		}
+
+		res, err := wf.candlesticks.ListCandlesticks(ctx, candlesticksapi.ListCandlesticksWorkflowParams{
+			Exchange: sub.Exchange,
+			Pair:     sub.Pair,
+			Period:   *sub.Period,
+			Start:    &openTime,
+			End:      &openTime,
+			Limit:    1,
+		}, &workflow.ChildWorkflowOptions{
+			TaskQueue: candlesticksapi.WorkerTaskQueueName,
+		})
+		if err != nil {
+			return candlestick.Candlestick{}, false, fmt.Errorf("could not get candlesticks from service: %w", err)
+		}
+
+		if len(res.List) > 0 && res.List[0].Time.Equal(openTime) && !res.List[0].Uncomplete {
+			return res.List[0], true, nil
+		}
+	}
+
+	workflow.GetLogger(ctx).Warn("No closed candlestick available, skipping it",
+		"forwardtest_id", sub.ForwardtestID.String(),
+		"exchange", sub.Exchange,
+		"pair", sub.Pair,
+		"time", openTime)
+	return candlestick.Candlestick{}, false, nil
+}
+
+// startCandlesticksDelivery starts the delivery of closed candlesticks for a
+// subscription. It is not an error if the delivery is already running.
+func (wf *workflows) startCandlesticksDelivery(ctx workflow.Context, sub forwardtest.Subscription) error {
+	opts := workflow.ChildWorkflowOptions{
+		// Only one delivery per subscription
+		WorkflowID: candlesticksWorkflowID(sub.ForwardtestID, sub.Exchange, sub.Pair),
+		// The delivery outlives the workflow that starts it
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+		// A new delivery can be started after the previous one has ended
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		deliverCandlesticksWorkflowName,
+		deliverCandlesticksWorkflowParams{
+			Subscription: sub,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		return fmt.Errorf("starting candlesticks delivery: %w", err)
+	}
+
+	return nil
+}
+
+// stopCandlesticksDelivery cancels the delivery of closed candlesticks for a
+// subscription, if any.
+func (wf *workflows) stopCandlesticksDelivery(ctx workflow.Context, forwardtestID uuid.UUID, exchange, pair string) {
+	wfID := candlesticksWorkflowID(forwardtestID, exchange, pair)
+	err := workflow.RequestCancelExternalWorkflow(ctx, wfID, "").Get(ctx, nil)
+	if err != nil {
+		// Only subscriptions with a period have a candlesticks delivery
+		workflow.GetLogger(ctx).Debug("No candlesticks delivery to cancel",
+			"forwardtest_id", forwardtestID.String(),
+			"exchange", exchange,
+			"pair", pair,
+			"error", err.Error())
+	}
+}
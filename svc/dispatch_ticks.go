@@ -0,0 +1,286 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// dispatchTicksWorkflowName is the name of the DispatchTicksWorkflow.
+	dispatchTicksWorkflowName = "DispatchTicksWorkflow"
+	// newTickSignalName is the name of the signal used to send a tick to the dispatcher.
+	newTickSignalName = "new-tick"
+	// deliveriesBeforeContinueAsNew is the number of deliveries executed by
+	// the dispatcher before continuing as new.
+	deliveriesBeforeContinueAsNew = 500
+
+	// droppedTicksMetricName is the name of the counter of ticks dropped by
+	// the concurrency policy of a forwardtest.
+	droppedTicksMetricName = "forwardtests_dropped_ticks"
+	// coalescedTicksMetricName is the name of the counter of ticks coalesced
+	// by the concurrency policy of a forwardtest.
+	coalescedTicksMetricName = "forwardtests_coalesced_ticks"
+	// concurrencyMetricTag is the tag of the concurrency policy on the
+	// dropped and coalesced ticks counters.
+	concurrencyMetricTag = "concurrency"
+)
+
+// dispatchTicksWorkflowParams is the input of the DispatchTicksWorkflow.
+type dispatchTicksWorkflowParams struct {
+	ForwardtestID uuid.UUID
+	// Buffer is the buffer carried over when continuing as new.
+	Buffer *forwardtest.TickBuffer
+	// Queue is the queue carried over when continuing as new.
+	Queue *forwardtest.DeliveryQueue
+}
+
+// dispatcherWorkflowID returns the workflow ID of the ticks dispatcher of a forwardtest.
+func dispatcherWorkflowID(forwardtestID uuid.UUID) string {
+	return fmt.Sprintf("forwardtest-%s-ticks-dispatcher", forwardtestID.String())
+}
+
+// dispatchTicksWorkflow is a private workflow that gathers the ticks of a
+// forwardtest and delivers them to its OnNewPricesCallback according to its
+// delivery policy. There is at most one dispatcher per forwardtest.
+func (wf *workflows) dispatchTicksWorkflow(
+	ctx workflow.Context,
+	params dispatchTicksWorkflowParams,
+) error {
+	// Read forwardtest from database to get callbacks and delivery policy
+	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	d := newTicksDispatcher(ctx, wf, ft, params)
+	ticksCh := workflow.GetSignalChannel(ctx, newTickSignalName)
+	for {
+		// Deliver ticks that are due, if the callback is available
+		d.flush(ctx)
+		d.deliver(ctx)
+
+		// Continue as new to keep the history small, without losing ticks
+		if d.deliveries >= deliveriesBeforeContinueAsNew || workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			if err := workflow.Await(ctx, func() bool { return d.inFlight == 0 }); err != nil {
+				return err
+			}
+			for {
+				var t tick.Tick
+				if !ticksCh.ReceiveAsync(&t) {
+					break
+				}
+				d.buffer.Add(t, workflow.Now(ctx))
+			}
+			return workflow.NewContinueAsNewError(ctx, dispatchTicksWorkflowName, dispatchTicksWorkflowParams{
+				ForwardtestID: params.ForwardtestID,
+				Buffer:        d.buffer,
+				Queue:         d.queue,
+			})
+		}
+
+		// Wait for a new tick, the next delivery, the end of a callback or
+		// the cancellation
+		if cancelled := d.wait(ctx, ticksCh); cancelled {
+			workflow.GetLogger(ctx).Debug("Ticks dispatcher cancelled",
+				"forwardtest_id", ft.ID.String(),
+				"pending_ticks", len(d.buffer.Ticks),
+				"pending_deliveries", len(d.queue.Pending))
+			return ctx.Err()
+		}
+	}
+}
+
+// ticksDispatcher is the state of the DispatchTicksWorkflow.
+type ticksDispatcher struct {
+	wf         *workflows
+	ft         forwardtest.Forwardtest
+	buffer     *forwardtest.TickBuffer
+	queue      *forwardtest.DeliveryQueue
+	inFlight   int
+	deliveries int
+	// done is notified each time a callback ends.
+	done workflow.Channel
+}
+
+func newTicksDispatcher(
+	ctx workflow.Context,
+	wf *workflows,
+	ft forwardtest.Forwardtest,
+	params dispatchTicksWorkflowParams,
+) *ticksDispatcher {
+	d := &ticksDispatcher{
+		wf:     wf,
+		ft:     ft,
+		buffer: params.Buffer,
+		queue:  params.Queue,
+		done:   workflow.NewBufferedChannel(ctx, 1),
+	}
+
+	if d.buffer == nil {
+		d.buffer = &forwardtest.TickBuffer{}
+	}
+	d.buffer.Policy = ft.Delivery
+
+	if d.queue == nil {
+		d.queue = &forwardtest.DeliveryQueue{}
+	}
+	d.queue.Policy = ft.Delivery
+
+	return d
+}
+
+// flush moves the buffered ticks to the delivery queue if they are due.
+func (d *ticksDispatcher) flush(ctx workflow.Context) {
+	ticks := d.buffer.Flush(workflow.Now(ctx))
+	if len(ticks) == 0 {
+		return
+	}
+
+	dropped, coalesced := d.queue.Push(ticks, d.inFlight > 0)
+	if dropped == 0 && coalesced == 0 {
+		return
+	}
+
+	// Tag with the concurrency policy rather than the forwardtest ID, to keep
+	// the cardinality of the metrics bounded
+	metrics := workflow.GetMetricsHandler(ctx).WithTags(map[string]string{
+		concurrencyMetricTag: d.ft.Delivery.Concurrency.String(),
+	})
+	workflow.GetLogger(ctx).Debug("Ticks dropped or coalesced by the concurrency policy",
+		"forwardtest_id", d.ft.ID.String(),
+		"dropped", dropped,
+		"coalesced", coalesced)
+	if dropped > 0 {
+		metrics.Counter(droppedTicksMetricName).Inc(int64(dropped))
+	}
+	if coalesced > 0 {
+		metrics.Counter(coalescedTicksMetricName).Inc(int64(coalesced))
+	}
+}
+
+// deliver executes the callback with the queued ticks, as long as the
+// concurrency policy allows it.
+func (d *ticksDispatcher) deliver(ctx workflow.Context) {
+	for {
+		ticks, ok := d.queue.Next(d.inFlight > 0)
+		if !ok {
+			return
+		}
+
+		d.deliveries++
+		d.inFlight++
+		deliveryID := d.deliveryID(ctx)
+		workflow.Go(ctx, func(ctx workflow.Context) {
+			defer func() {
+				d.inFlight--
+				d.done.SendAsync(struct{}{})
+			}()
+
+			if err := d.wf.executeOnNewPricesCallback(ctx, &d.ft, deliveryID, ticks); err != nil {
+				workflow.GetLogger(ctx).Error("Failed to deliver ticks to forwardtest",
+					"forwardtest_id", d.ft.ID.String(),
+					"ticks", len(ticks),
+					"error", err.Error())
+			}
+		})
+	}
+}
+
+// deliveryID returns the ID of the last delivery, from the run of the
+// dispatcher and the number of deliveries it has executed, as several
+// deliveries may end with ticks of the same time.
+func (d *ticksDispatcher) deliveryID(ctx workflow.Context) string {
+	return fmt.Sprintf("%s-%d", workflow.GetInfo(ctx).WorkflowExecution.RunID, d.deliveries)
+}
+
+// wait blocks until something happens on the dispatcher. It returns true if
+// the dispatcher has been cancelled.
+func (d *ticksDispatcher) wait(ctx workflow.Context, ticksCh workflow.ReceiveChannel) bool {
+	cancelled := false
+	timerCtx, cancelTimer := workflow.WithCancel(ctx)
+	defer cancelTimer()
+
+	selector := workflow.NewSelector(ctx)
+	selector.AddReceive(ticksCh, func(c workflow.ReceiveChannel, _ bool) {
+		var t tick.Tick
+		c.Receive(ctx, &t)
+		d.buffer.Add(t, workflow.Now(ctx))
+	})
+	selector.AddReceive(d.done, func(c workflow.ReceiveChannel, _ bool) {
+		c.Receive(ctx, nil)
+	})
+	selector.AddReceive(ctx.Done(), func(workflow.ReceiveChannel, bool) {
+		cancelled = true
+	})
+	if due, ok := d.buffer.DueAt(); ok {
+		selector.AddFuture(workflow.NewTimer(timerCtx, due.Sub(workflow.Now(ctx))), func(workflow.Future) {})
+	}
+	selector.Select(ctx)
+
+	return cancelled
+}
+
+// dispatchTick sends a tick to the dispatcher of the forwardtest, starting
+// the dispatcher if it is not running.
+func (wf *workflows) dispatchTick(ctx workflow.Context, forwardtestID uuid.UUID, t tick.Tick) error {
+	wfID := dispatcherWorkflowID(forwardtestID)
+	err := workflow.SignalExternalWorkflow(ctx, wfID, "", newTickSignalName, t).Get(ctx, nil)
+	if err == nil {
+		return nil
+	}
+
+	// Start the dispatcher and send the tick again
+	if err := wf.startDispatcher(ctx, forwardtestID); err != nil {
+		return err
+	}
+
+	err = workflow.SignalExternalWorkflow(ctx, wfID, "", newTickSignalName, t).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sending tick to dispatcher: %w", err)
+	}
+
+	return nil
+}
+
+// startDispatcher starts the dispatcher of the forwardtest. It is not an error
+// if the dispatcher has already been started concurrently.
+func (wf *workflows) startDispatcher(ctx workflow.Context, forwardtestID uuid.UUID) error {
+	opts := workflow.ChildWorkflowOptions{
+		// Only one dispatcher per forwardtest
+		WorkflowID: dispatcherWorkflowID(forwardtestID),
+		// The dispatcher outlives the proxy workflow that starts it
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+		// A new dispatcher can be started after the previous one has been cancelled
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		dispatchTicksWorkflowName,
+		dispatchTicksWorkflowParams{
+			ForwardtestID: forwardtestID,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		return fmt.Errorf("starting ticks dispatcher: %w", err)
+	}
+
+	return nil
+}
+
+// stopDispatcher cancels the dispatcher of the forwardtest, if any. Pending
+// ticks are not delivered.
+func (wf *workflows) stopDispatcher(ctx workflow.Context, forwardtestID uuid.UUID) {
+	err := workflow.RequestCancelExternalWorkflow(ctx, dispatcherWorkflowID(forwardtestID), "").Get(ctx, nil)
+	if err != nil {
+		// The dispatcher is only started for forwardtests with a delivery policy
+		workflow.GetLogger(ctx).Debug("No ticks dispatcher to cancel",
+			"forwardtest_id", forwardtestID.String(),
+			"error", err.Error())
+	}
+}
@@ -0,0 +1,84 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// defaultEventLimit is the default number of events returned when listing
+// the events of a forwardtest.
+const defaultEventLimit = 100
+
+// ListForwardtestEventsWorkflow lists the events of a forwardtest after a
+// cursor, the oldest first, so consumers can resume from the last event they
+// have seen.
+func (wf *workflows) ListForwardtestEventsWorkflow(
+	ctx workflow.Context,
+	params api.ListForwardtestEventsWorkflowParams,
+) (api.ListForwardtestEventsWorkflowResults, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultEventLimit
+	}
+
+	var res db.ListEventsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListEventsActivity, db.ListEventsActivityParams{
+			ForwardtestID: params.ForwardtestID,
+			After:         params.After,
+			Limit:         limit,
+		}).Get(ctx, &res)
+	if err != nil {
+		return api.ListForwardtestEventsWorkflowResults{},
+			fmt.Errorf("listing events from db: %w", err)
+	}
+
+	return api.ListForwardtestEventsWorkflowResults{
+		Events: res.Events,
+	}, nil
+}
+
+// recordEvent appends an event to the event log of the forwardtest. Failures
+// are only logged as the event log must not interrupt the forwardtest.
+func (wf *workflows) recordEvent(ctx workflow.Context, event forwardtest.Event) {
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.RecordEventActivity, db.RecordEventActivityParams{
+			Event: event,
+		}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Could not record forwardtest event",
+			"forwardtest_id", event.ForwardtestID.String(),
+			"type", event.Type.String(),
+			"error", err.Error())
+	}
+}
+
+// pruneEvents deletes the events recorded before the retention, if any.
+// Failures are only logged as the next pruning may succeed.
+func (wf *workflows) pruneEvents(ctx workflow.Context, retention time.Duration) {
+	if retention <= 0 {
+		return
+	}
+
+	var res db.PruneEventsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.PruneEventsActivity, db.PruneEventsActivityParams{
+			Retention: retention,
+		}).Get(ctx, &res)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Could not prune forwardtest events",
+			"error", err.Error())
+		return
+	}
+
+	workflow.GetLogger(ctx).Debug("Pruned forwardtest events",
+		"deleted", res.Deleted)
+}
@@ -0,0 +1,115 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/order"
+	"go.temporal.io/sdk/workflow"
+)
+
+// handleStaleFeed marks the price feed of the subscription as stale and
+// executes the OnFeedStaleCallback of the forwardtest, if any. Nothing is done
+// if the feed was already marked as stale.
+func (wf *workflows) handleStaleFeed(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	sub forwardtest.Subscription,
+) error {
+	now := workflow.Now(ctx)
+
+	// Mark subscription as stale
+	var res db.MarkSubscriptionStaleActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.MarkSubscriptionStaleActivity, db.MarkSubscriptionStaleActivityParams{
+			ForwardtestID: ft.ID,
+			Exchange:      sub.Exchange,
+			Pair:          sub.Pair,
+			Since:         now,
+		}).Get(ctx, &res)
+	if err != nil {
+		return fmt.Errorf("marking subscription as stale: %w", err)
+	} else if !res.Marked {
+		return nil
+	}
+
+	workflow.GetLogger(ctx).Warn("Forwardtest price feed is stale",
+		"forwardtest_id", ft.ID.String(),
+		"exchange", sub.Exchange,
+		"pair", sub.Pair,
+		"last_activity", sub.LastActivity())
+
+	// Execute the OnFeedStaleCallback workflow, if any
+	callback := ft.OptionalCallbacks.OnFeedStaleCallback
+	if callback == nil {
+		return nil
+	}
+
+	opts := workflow.ChildWorkflowOptions{
+		// Unique identifier for this child workflow execution
+		WorkflowID: fmt.Sprintf("forwardtest-%s-on-feed-stale-%s-%s-%s",
+			ft.ID.String(), sub.Exchange, sub.Pair, now.Format(time.RFC3339Nano)),
+		// Task queue where the child workflow will be executed
+		TaskQueue: callback.TaskQueueName,
+		// Maximum time allowed for the child workflow to complete
+		WorkflowExecutionTimeout: time.Second * 30,
+	}
+
+	// Check if the timeout is set
+	if callback.ExecutionTimeout > 0 {
+		opts.WorkflowExecutionTimeout = callback.ExecutionTimeout
+	}
+
+	err = workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		callback.Name,
+		api.OnFeedStaleCallbackWorkflowParams{
+			Context: runtime.Context{
+				ID:              ft.ID,
+				Mode:            runtime.ModeForwardtest,
+				Now:             now,
+				ParentTaskQueue: workflow.GetInfo(ctx).TaskQueueName,
+			},
+			Exchange:   sub.Exchange,
+			Pair:       sub.Pair,
+			LastTickAt: sub.LastTickAt,
+		}).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("could not execute OnFeedStaleCallback workflow: %w", err)
+	}
+
+	return nil
+}
+
+// checkOrderFeed checks that the price feed of the order pair is not stale,
+// if the forwardtest blocks orders in this case. Pairs without subscription
+// and replays, whose ticks are from the past, are not checked.
+func (wf *workflows) checkOrderFeed(ctx workflow.Context, ft forwardtest.Forwardtest, o order.Order) error {
+	if !ft.FeedHealth.BlockOrdersWhenStale || ft.IsReplay() {
+		return nil
+	}
+
+	// List forwardtest subscriptions
+	var res db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: ft.ID,
+		}).Get(ctx, &res)
+	if err != nil {
+		return fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	for _, sub := range res.Subscriptions {
+		if sub.Exchange == o.Exchange && sub.Pair == o.Pair {
+			return ft.FeedHealth.CheckFeed(sub, workflow.Now(ctx))
+		}
+	}
+
+	return nil
+}
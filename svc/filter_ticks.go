@@ -0,0 +1,100 @@
+package svc
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/workflow"
+)
+
+// acceptTick checks the tick against the tick filter of the forwardtest, if
+// any. It returns false if the tick has been rejected and quarantined.
+func (wf *workflows) acceptTick(ctx workflow.Context, ft forwardtest.Forwardtest, t tick.Tick) (bool, error) {
+	if !ft.TickFilter.IsEnabled() {
+		return true, nil
+	}
+
+	// Ticks without subscription are not filtered, as they are not delivered
+	sub, err := wf.getSubscription(ctx, ft.ID, t.Exchange, t.Pair)
+	if err != nil {
+		return false, err
+	} else if sub == nil {
+		return true, nil
+	}
+
+	accepted, err := wf.filterTick(ctx, ft, *sub, t)
+	if err != nil {
+		return false, fmt.Errorf("filtering tick: %w", err)
+	}
+
+	return accepted, nil
+}
+
+// filterTick checks the tick against the tick filter of the forwardtest, by
+// comparing it with the last accepted tick of its subscription. It returns
+// false if the tick has been rejected, in which case it is quarantined and
+// must not be forwarded to the bot.
+func (wf *workflows) filterTick(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	sub forwardtest.Subscription,
+	t tick.Tick,
+) (bool, error) {
+	// Check the tick
+	var rejection *forwardtest.TickRejectionError
+	if err := ft.TickFilter.Check(t, sub); err == nil {
+		return true, nil
+	} else if !errors.As(err, &rejection) {
+		return false, err
+	}
+
+	workflow.GetLogger(ctx).Warn("Tick rejected by price filter",
+		"forwardtest_id", ft.ID.String(),
+		"tick", t,
+		"rule", rejection.Rule,
+		"reason", rejection.Message)
+
+	// Quarantine the tick
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.QuarantineTickActivity, db.QuarantineTickActivityParams{
+			Tick: forwardtest.QuarantinedTick{
+				ForwardtestID: ft.ID,
+				Tick:          t,
+				Rule:          rejection.Rule,
+				Reason:        rejection.Message,
+				QuarantinedAt: workflow.Now(ctx),
+			},
+		}).Get(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("quarantining tick: %w", err)
+	}
+
+	return false, nil
+}
+
+// getSubscription gets the subscription of the forwardtest to a pair. It
+// returns nil if there is none.
+func (wf *workflows) getSubscription(
+	ctx workflow.Context,
+	forwardtestID uuid.UUID,
+	exchange, pair string,
+) (*forwardtest.Subscription, error) {
+	var res db.GetSubscriptionActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.GetSubscriptionActivity, db.GetSubscriptionActivityParams{
+			ForwardtestID: forwardtestID,
+			Exchange:      exchange,
+			Pair:          pair,
+		}).Get(ctx, &res)
+	if err != nil {
+		return nil, fmt.Errorf("getting subscription: %w", err)
+	}
+
+	return res.Subscription, nil
+}
@@ -4,6 +4,7 @@ import (
 	candlesticksclients "github.com/cryptellation/candlesticks/pkg/clients"
 	"github.com/cryptellation/forwardtests/api"
 	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/forwardtests/svc/preflight"
 	tickclients "github.com/cryptellation/ticks/pkg/clients"
 	"go.temporal.io/sdk/worker"
 	"go.temporal.io/sdk/workflow"
@@ -57,6 +58,76 @@ type Forwardtests interface {
 		ctx workflow.Context,
 		params api.SubscribeToPriceWorkflowParams,
 	) (api.SubscribeToPriceWorkflowResults, error)
+
+	UnsubscribeFromPriceWorkflow(
+		ctx workflow.Context,
+		params api.UnsubscribeFromPriceWorkflowParams,
+	) (api.UnsubscribeFromPriceWorkflowResults, error)
+
+	ListForwardtestSubscriptionsWorkflow(
+		ctx workflow.Context,
+		params api.ListForwardtestSubscriptionsWorkflowParams,
+	) (api.ListForwardtestSubscriptionsWorkflowResults, error)
+
+	UpdateForwardtestOrderBookWorkflow(
+		ctx workflow.Context,
+		params api.UpdateForwardtestOrderBookWorkflowParams,
+	) (api.UpdateForwardtestOrderBookWorkflowResults, error)
+
+	RegisterForwardtestTimerWorkflow(
+		ctx workflow.Context,
+		params api.RegisterForwardtestTimerWorkflowParams,
+	) (api.RegisterForwardtestTimerWorkflowResults, error)
+
+	UnregisterForwardtestTimerWorkflow(
+		ctx workflow.Context,
+		params api.UnregisterForwardtestTimerWorkflowParams,
+	) (api.UnregisterForwardtestTimerWorkflowResults, error)
+
+	ListForwardtestTimersWorkflow(
+		ctx workflow.Context,
+		params api.ListForwardtestTimersWorkflowParams,
+	) (api.ListForwardtestTimersWorkflowResults, error)
+
+	ReplayForwardtestWorkflow(
+		ctx workflow.Context,
+		params api.ReplayForwardtestWorkflowParams,
+	) (api.ReplayForwardtestWorkflowResults, error)
+
+	GetForwardtestDiagnosticsWorkflow(
+		ctx workflow.Context,
+		params api.GetForwardtestDiagnosticsWorkflowParams,
+	) (api.GetForwardtestDiagnosticsWorkflowResults, error)
+
+	ListForwardtestEventsWorkflow(
+		ctx workflow.Context,
+		params api.ListForwardtestEventsWorkflowParams,
+	) (api.ListForwardtestEventsWorkflowResults, error)
+
+	DeleteForwardtestWorkflow(
+		ctx workflow.Context,
+		params api.DeleteForwardtestWorkflowParams,
+	) (api.DeleteForwardtestWorkflowResults, error)
+
+	ArchiveForwardtestWorkflow(
+		ctx workflow.Context,
+		params api.ArchiveForwardtestWorkflowParams,
+	) (api.ArchiveForwardtestWorkflowResults, error)
+
+	CloneForwardtestWorkflow(
+		ctx workflow.Context,
+		params api.CloneForwardtestWorkflowParams,
+	) (api.CloneForwardtestWorkflowResults, error)
+
+	ResetForwardtestWorkflow(
+		ctx workflow.Context,
+		params api.ResetForwardtestWorkflowParams,
+	) (api.ResetForwardtestWorkflowResults, error)
+
+	ReconcileForwardtestsWorkflow(
+		ctx workflow.Context,
+		params api.ReconcileForwardtestsWorkflowParams,
+	) (api.ReconcileForwardtestsWorkflowResults, error)
 }
 
 var _ Forwardtests = &workflows{}
@@ -65,23 +136,56 @@ type workflows struct {
 	db           db.DB
 	candlesticks candlesticksclients.WfClient
 	ticks        tickclients.WfClient
+	preflight    preflight.Preflight
+}
+
+// Option is an option of the Forwardtests instance.
+type Option func(wf *workflows)
+
+// WithTicksClient sets the client used to listen to ticks, instead of the
+// ticks service.
+func WithTicksClient(ticks tickclients.WfClient) Option {
+	return func(wf *workflows) {
+		wf.ticks = ticks
+	}
+}
+
+// WithCandlesticksClient sets the client used to get candlesticks, instead
+// of the candlesticks service.
+func WithCandlesticksClient(candlesticks candlesticksclients.WfClient) Option {
+	return func(wf *workflows) {
+		wf.candlesticks = candlesticks
+	}
+}
+
+// WithPreflight sets the checks used to find the unreachable callbacks when
+// a preflight is requested. Without it, preflights are refused.
+func WithPreflight(p preflight.Preflight) Option {
+	return func(wf *workflows) {
+		wf.preflight = p
+	}
 }
 
 // New creates a new Forwardtests instance.
-func New(db db.DB) Forwardtests {
-	return &workflows{
+func New(db db.DB, opts ...Option) Forwardtests {
+	wf := &workflows{
 		candlesticks: candlesticksclients.NewWfClient(),
 		ticks:        tickclients.NewWfClient(),
 		db:           db,
 	}
+
+	for _, opt := range opts {
+		opt(wf)
+	}
+
+	return wf
 }
 
 // Register registers the workflows to the worker.
 func (wf *workflows) Register(worker worker.Worker) {
-	// Private workflows
-	worker.RegisterWorkflowWithOptions(wf.forwardNewPriceToForwardTestWorkflow, workflow.RegisterOptions{
-		Name: forwardNewPriceToForwardTestWorkflowName,
-	})
+	wf.registerPrivateWorkflows(worker)
+	wf.registerPriceWorkflows(worker)
+	wf.registerTimerWorkflows(worker)
 
 	// Public workflows
 	worker.RegisterWorkflowWithOptions(wf.CreateForwardtestWorkflow, workflow.RegisterOptions{
@@ -108,11 +212,86 @@ func (wf *workflows) Register(worker worker.Worker) {
 	worker.RegisterWorkflowWithOptions(wf.StopForwardtestWorkflow, workflow.RegisterOptions{
 		Name: api.StopForwardtestWorkflowName,
 	})
-	worker.RegisterWorkflowWithOptions(wf.SubscribeToPriceWorkflow, workflow.RegisterOptions{
-		Name: api.SubscribeToPriceWorkflowName,
+	worker.RegisterWorkflowWithOptions(wf.DeleteForwardtestWorkflow, workflow.RegisterOptions{
+		Name: api.DeleteForwardtestWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ArchiveForwardtestWorkflow, workflow.RegisterOptions{
+		Name: api.ArchiveForwardtestWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.CloneForwardtestWorkflow, workflow.RegisterOptions{
+		Name: api.CloneForwardtestWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ResetForwardtestWorkflow, workflow.RegisterOptions{
+		Name: api.ResetForwardtestWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ReconcileForwardtestsWorkflow, workflow.RegisterOptions{
+		Name: api.ReconcileForwardtestsWorkflowName,
 	})
 
 	worker.RegisterWorkflowWithOptions(ServiceInfoWorkflow, workflow.RegisterOptions{
 		Name: api.ServiceInfoWorkflowName,
 	})
 }
+
+// registerPriceWorkflows registers the public workflows managing the price
+// feeds of the forwardtests.
+func (wf *workflows) registerPriceWorkflows(worker worker.Worker) {
+	worker.RegisterWorkflowWithOptions(wf.SubscribeToPriceWorkflow, workflow.RegisterOptions{
+		Name: api.SubscribeToPriceWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.UnsubscribeFromPriceWorkflow, workflow.RegisterOptions{
+		Name: api.UnsubscribeFromPriceWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ListForwardtestSubscriptionsWorkflow, workflow.RegisterOptions{
+		Name: api.ListForwardtestSubscriptionsWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.UpdateForwardtestOrderBookWorkflow, workflow.RegisterOptions{
+		Name: api.UpdateForwardtestOrderBookWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ReplayForwardtestWorkflow, workflow.RegisterOptions{
+		Name: api.ReplayForwardtestWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.GetForwardtestDiagnosticsWorkflow, workflow.RegisterOptions{
+		Name: api.GetForwardtestDiagnosticsWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ListForwardtestEventsWorkflow, workflow.RegisterOptions{
+		Name: api.ListForwardtestEventsWorkflowName,
+	})
+}
+
+// registerTimerWorkflows registers the public workflows managing the timers
+// of the forwardtests.
+func (wf *workflows) registerTimerWorkflows(worker worker.Worker) {
+	worker.RegisterWorkflowWithOptions(wf.RegisterForwardtestTimerWorkflow, workflow.RegisterOptions{
+		Name: api.RegisterForwardtestTimerWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.UnregisterForwardtestTimerWorkflow, workflow.RegisterOptions{
+		Name: api.UnregisterForwardtestTimerWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.ListForwardtestTimersWorkflow, workflow.RegisterOptions{
+		Name: api.ListForwardtestTimersWorkflowName,
+	})
+}
+
+// registerPrivateWorkflows registers the workflows only executed by the
+// service itself.
+func (wf *workflows) registerPrivateWorkflows(worker worker.Worker) {
+	worker.RegisterWorkflowWithOptions(wf.forwardNewPriceToForwardTestWorkflow, workflow.RegisterOptions{
+		Name: forwardNewPriceToForwardTestWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.dispatchTicksWorkflow, workflow.RegisterOptions{
+		Name: dispatchTicksWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.deliverCandlesticksWorkflow, workflow.RegisterOptions{
+		Name: deliverCandlesticksWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.replayTicksWorkflow, workflow.RegisterOptions{
+		Name: replayTicksWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.timerWorkflow, workflow.RegisterOptions{
+		Name: timerWorkflowName,
+	})
+	worker.RegisterWorkflowWithOptions(wf.watchFeedHealthWorkflow, workflow.RegisterOptions{
+		Name: watchFeedHealthWorkflowName,
+	})
+}
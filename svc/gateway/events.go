@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+)
+
+// heartbeatInterval is the interval between two comments sent on an idle
+// events stream, to keep it open through proxies.
+const heartbeatInterval = 15 * time.Second
+
+// streamForwardtestEvents streams the events of a forwardtest as server-sent
+// events, after the event ID of the Last-Event-ID header or of the after
+// query parameter, so that the consumers resume where they stopped.
+func (g *Gateway) streamForwardtestEvents(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	after, err := eventsCursor(r)
+	if err != nil {
+		writeBadRequest(w, err)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeJSON(w, http.StatusInternalServerError, errorResponse{Error: "streaming is not supported"})
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+
+	events, errs := g.client.Forwardtest(id).Watch(r.Context(), after)
+	for {
+		select {
+		case e, ok := <-events:
+			if !ok {
+				return
+			}
+			if err := writeEvent(w, e); err != nil {
+				return
+			}
+		case err, ok := <-errs:
+			if !ok {
+				return
+			}
+			// Errors are retried by the watch, only report them as comments
+			_, _ = fmt.Fprintf(w, ": %s\n\n", err.Error())
+		case <-heartbeat.C:
+			_, _ = fmt.Fprint(w, ": heartbeat\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// eventsCursor returns the ID of the last event seen by the consumer.
+func eventsCursor(r *http.Request) (int64, error) {
+	v := r.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = r.URL.Query().Get("after")
+	}
+	if v == "" {
+		return 0, nil
+	}
+
+	after, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("parsing last event ID: %w", err)
+	}
+	if after < 0 {
+		return 0, errors.New("last event ID must not be negative")
+	}
+
+	return after, nil
+}
+
+// writeEvent writes a forwardtest event as a server-sent event.
+func writeEvent(w http.ResponseWriter, e forwardtest.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+	return err
+}
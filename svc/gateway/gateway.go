@@ -0,0 +1,263 @@
+// Package gateway exposes the forwardtests API over HTTP/JSON for the tools
+// that cannot start Temporal workflows, by executing them with the Go client.
+package gateway
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/clients"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime/order"
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
+)
+
+// IdempotencyKeyHeader is the header setting the idempotency key of the
+// forwardtest and order creations.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// errorStatuses are the HTTP statuses of the workflows application errors.
+var errorStatuses = map[string]int{
+	db.ErrRecordNotFound.Error():      http.StatusNotFound,
+	api.UnreachableCallbacksErrorType: http.StatusUnprocessableEntity,
+	api.RiskRejectedErrorType:         http.StatusUnprocessableEntity,
+	api.FeedStaleErrorType:            http.StatusUnprocessableEntity,
+	api.InsufficientDepthErrorType:    http.StatusUnprocessableEntity,
+}
+
+//go:embed openapi.yaml
+var openAPIDocument []byte
+
+// Gateway is the HTTP handler of the forwardtests API.
+type Gateway struct {
+	client clients.Client
+	mux    *http.ServeMux
+}
+
+var _ http.Handler = &Gateway{}
+
+// New creates a new gateway executing the requests with the client.
+func New(client clients.Client) *Gateway {
+	g := &Gateway{
+		client: client,
+		mux:    http.NewServeMux(),
+	}
+
+	g.mux.HandleFunc("GET /openapi.yaml", g.getOpenAPIDocument)
+	g.mux.HandleFunc("POST /forwardtests", g.createForwardtest)
+	g.mux.HandleFunc("GET /forwardtests", g.listForwardtests)
+	g.mux.HandleFunc("GET /forwardtests/{id}", g.getForwardtest)
+	g.mux.HandleFunc("POST /forwardtests/{id}/run", g.runForwardtest)
+	g.mux.HandleFunc("POST /forwardtests/{id}/stop", g.stopForwardtest)
+	g.mux.HandleFunc("POST /forwardtests/{id}/orders", g.createForwardtestOrder)
+	g.mux.HandleFunc("GET /forwardtests/{id}/balance", g.getForwardtestBalance)
+	g.mux.HandleFunc("GET /forwardtests/{id}/accounts", g.listForwardtestAccounts)
+	g.mux.HandleFunc("GET /forwardtests/{id}/events", g.streamForwardtestEvents)
+
+	return g
+}
+
+// ServeHTTP serves the HTTP requests.
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) getOpenAPIDocument(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPIDocument)
+}
+
+func (g *Gateway) createForwardtest(w http.ResponseWriter, r *http.Request) {
+	var params api.CreateForwardtestWorkflowParams
+	if err := json.NewDecoder(r.Body).Decode(&params); err != nil {
+		writeBadRequest(w, fmt.Errorf("decoding forwardtest: %w", err))
+		return
+	}
+
+	ft, err := g.client.NewForwardtest(r.Context(), params, idempotencyOptions(r)...)
+	writeResponse(w, http.StatusCreated, api.CreateForwardtestWorkflowResults{ID: ft.ID}, err)
+}
+
+func (g *Gateway) listForwardtests(w http.ResponseWriter, r *http.Request) {
+	var params api.ListForwardtestsWorkflowParams
+	if v := r.URL.Query().Get("include_archived"); v != "" {
+		includeArchived, err := strconv.ParseBool(v)
+		if err != nil {
+			writeBadRequest(w, fmt.Errorf("parsing include_archived: %w", err))
+			return
+		}
+		params.IncludeArchived = includeArchived
+	}
+
+	handles, err := g.client.ListForwardtests(r.Context(), params)
+	if err != nil {
+		writeResponse(w, http.StatusOK, nil, err)
+		return
+	}
+
+	// Get the data of each listed forwardtest
+	res := api.ListForwardtestsWorkflowResults{
+		Forwardtests: make([]forwardtest.Forwardtest, 0, len(handles)),
+	}
+	for _, h := range handles {
+		ft, err := h.Get(r.Context())
+		if err != nil {
+			writeResponse(w, http.StatusOK, nil, err)
+			return
+		}
+		res.Forwardtests = append(res.Forwardtests, ft)
+	}
+
+	writeResponse(w, http.StatusOK, res, nil)
+}
+
+func (g *Gateway) getForwardtest(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	ft, err := g.client.Forwardtest(id).Get(r.Context())
+	writeResponse(w, http.StatusOK, api.GetForwardtestWorkflowResults{Forwardtest: ft}, err)
+}
+
+func (g *Gateway) runForwardtest(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	preflight := false
+	if v := r.URL.Query().Get("preflight"); v != "" {
+		var err error
+		if preflight, err = strconv.ParseBool(v); err != nil {
+			writeBadRequest(w, fmt.Errorf("parsing preflight: %w", err))
+			return
+		}
+	}
+
+	ft := g.client.Forwardtest(id)
+	run := ft.Run
+	if preflight {
+		run = ft.RunWithPreflight
+	}
+	writeResponse(w, http.StatusOK, api.RunForwardtestWorkflowResults{}, run(r.Context()))
+}
+
+func (g *Gateway) stopForwardtest(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	err := g.client.Forwardtest(id).Stop(r.Context())
+	writeResponse(w, http.StatusOK, api.StopForwardtestWorkflowResults{}, err)
+}
+
+func (g *Gateway) createForwardtestOrder(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	var o order.Order
+	if err := json.NewDecoder(r.Body).Decode(&o); err != nil {
+		writeBadRequest(w, fmt.Errorf("decoding order: %w", err))
+		return
+	}
+
+	res, err := g.client.Forwardtest(id).CreateOrder(r.Context(), o, idempotencyOptions(r)...)
+	writeResponse(w, http.StatusCreated, res, err)
+}
+
+func (g *Gateway) getForwardtestBalance(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	balance, err := g.client.Forwardtest(id).GetBalance(r.Context())
+	writeResponse(w, http.StatusOK, api.GetForwardtestBalanceWorkflowResults{Balance: balance}, err)
+}
+
+func (g *Gateway) listForwardtestAccounts(w http.ResponseWriter, r *http.Request) {
+	id, ok := forwardtestID(w, r)
+	if !ok {
+		return
+	}
+
+	accounts, err := g.client.Forwardtest(id).ListAccounts(r.Context())
+	writeResponse(w, http.StatusOK, api.ListForwardtestAccountsWorkflowResults{Accounts: accounts}, err)
+}
+
+// forwardtestID parses the forwardtest ID of the request path, writing a bad
+// request response if it is invalid.
+func forwardtestID(w http.ResponseWriter, r *http.Request) (uuid.UUID, bool) {
+	id, err := uuid.Parse(r.PathValue("id"))
+	if err != nil {
+		writeBadRequest(w, fmt.Errorf("parsing forwardtest ID: %w", err))
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+// idempotencyOptions returns the call options from the idempotency key of
+// the request, if any.
+func idempotencyOptions(r *http.Request) []clients.Option {
+	key := r.Header.Get(IdempotencyKeyHeader)
+	if key == "" {
+		return nil
+	}
+
+	return []clients.Option{clients.WithIdempotencyKey(key)}
+}
+
+// errorResponse is the body of the error responses.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// writeResponse writes the JSON response of a call to the service, or its
+// error with the status of its application error type.
+func writeResponse(w http.ResponseWriter, status int, res any, err error) {
+	if err != nil {
+		writeJSON(w, errorStatus(err), errorResponse{Error: err.Error()})
+		return
+	}
+
+	writeJSON(w, status, res)
+}
+
+func writeBadRequest(w http.ResponseWriter, err error) {
+	writeJSON(w, http.StatusBadRequest, errorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// errorStatus returns the HTTP status of the first application error of the
+// error chain with a known type. Other errors are internal errors.
+func errorStatus(err error) int {
+	for {
+		var appErr *temporal.ApplicationError
+		if !errors.As(err, &appErr) {
+			return http.StatusInternalServerError
+		}
+
+		if status, ok := errorStatuses[appErr.Type()]; ok {
+			return status
+		}
+		err = appErr.Unwrap()
+	}
+}
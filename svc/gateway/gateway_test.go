@@ -0,0 +1,193 @@
+//go:build unit
+// +build unit
+
+package gateway
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/clients"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	temporalclient "go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"go.temporal.io/sdk/temporal"
+)
+
+func TestGatewaySuite(t *testing.T) {
+	suite.Run(t, new(GatewaySuite))
+}
+
+type GatewaySuite struct {
+	suite.Suite
+	temporal *mocks.Client
+	run      *mocks.WorkflowRun
+	gateway  *Gateway
+}
+
+func (suite *GatewaySuite) SetupTest() {
+	suite.temporal = mocks.NewClient(suite.T())
+	suite.run = mocks.NewWorkflowRun(suite.T())
+	suite.gateway = New(clients.New(suite.temporal))
+}
+
+// serve serves a request and returns the response.
+func (suite *GatewaySuite) serve(method, target, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	suite.gateway.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+	return rec
+}
+
+func (suite *GatewaySuite) TestCreateForwardtest() {
+	id := uuid.New()
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.CreateForwardtestWorkflowName, mock.MatchedBy(func(params api.CreateForwardtestWorkflowParams) bool {
+			return params.Accounts["binance"].Balances["USDT"] == 1000
+		})).Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.CreateForwardtestWorkflowResults).ID = id
+	}).Return(nil).Once()
+
+	rec := suite.serve(http.MethodPost, "/forwardtests", `{"Accounts":{"binance":{"balances":{"USDT":1000}}}}`)
+	suite.Require().Equal(http.StatusCreated, rec.Code)
+
+	var res api.CreateForwardtestWorkflowResults
+	suite.Require().NoError(json.NewDecoder(rec.Body).Decode(&res))
+	suite.Require().Equal(id, res.ID)
+}
+
+func (suite *GatewaySuite) TestListForwardtests() {
+	ft := forwardtest.Forwardtest{ID: uuid.New(), Status: forwardtest.StatusRunning}
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.ListForwardtestsWorkflowName, api.ListForwardtestsWorkflowParams{IncludeArchived: true}).
+		Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.ListForwardtestsWorkflowResults).Forwardtests = []forwardtest.Forwardtest{{ID: ft.ID}}
+	}).Return(nil).Once()
+
+	// The data of each forwardtest is read from its handle
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.GetForwardtestWorkflowName, api.GetForwardtestWorkflowParams{ForwardtestID: ft.ID}).
+		Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.GetForwardtestWorkflowResults).Forwardtest = ft
+	}).Return(nil).Once()
+
+	rec := suite.serve(http.MethodGet, "/forwardtests?include_archived=true", "")
+	suite.Require().Equal(http.StatusOK, rec.Code)
+
+	var res api.ListForwardtestsWorkflowResults
+	suite.Require().NoError(json.NewDecoder(rec.Body).Decode(&res))
+	suite.Require().Len(res.Forwardtests, 1)
+	suite.Require().Equal(ft.ID, res.Forwardtests[0].ID)
+	suite.Require().Equal(forwardtest.StatusRunning, res.Forwardtests[0].Status)
+}
+
+func (suite *GatewaySuite) TestCreateOrderIdempotencyKey() {
+	id := uuid.New()
+	// The workflow ID is derived from the idempotency key
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.MatchedBy(
+		func(opts temporalclient.StartWorkflowOptions) bool {
+			return opts.ID == api.CreateForwardtestOrderWorkflowName+"-key"
+		}), api.CreateForwardtestOrderWorkflowName, mock.Anything).Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(nil).Once()
+
+	req := httptest.NewRequest(http.MethodPost, "/forwardtests/"+id.String()+"/orders",
+		strings.NewReader(`{"type":"market","side":"buy","exchange":"binance","pair":"BTC-USDT","quantity":1}`))
+	req.Header.Set(IdempotencyKeyHeader, "key")
+	rec := httptest.NewRecorder()
+	suite.gateway.ServeHTTP(rec, req)
+	suite.Require().Equal(http.StatusCreated, rec.Code)
+}
+
+func (suite *GatewaySuite) TestErrors() {
+	id := uuid.New()
+
+	// Invalid ID
+	rec := suite.serve(http.MethodGet, "/forwardtests/invalid", "")
+	suite.Require().Equal(http.StatusBadRequest, rec.Code)
+
+	// Unknown forwardtest
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.GetForwardtestWorkflowName, api.GetForwardtestWorkflowParams{ForwardtestID: id}).
+		Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(
+		temporal.NewNonRetryableApplicationError("no forwardtest", db.ErrRecordNotFound.Error(), nil)).Once()
+	rec = suite.serve(http.MethodGet, "/forwardtests/"+id.String(), "")
+	suite.Require().Equal(http.StatusNotFound, rec.Code)
+
+	// Rejected order, wrapped by the workflow
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.CreateForwardtestOrderWorkflowName, mock.Anything).Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(
+		temporal.NewApplicationErrorWithCause("creating order", "wrapError",
+			temporal.NewNonRetryableApplicationError("too many orders", api.RiskRejectedErrorType, nil))).Once()
+	rec = suite.serve(http.MethodPost, "/forwardtests/"+id.String()+"/orders", `{"quantity":1}`)
+	suite.Require().Equal(http.StatusUnprocessableEntity, rec.Code)
+
+	var res errorResponse
+	suite.Require().NoError(json.NewDecoder(rec.Body).Decode(&res))
+	suite.Require().Contains(res.Error, "too many orders")
+}
+
+func (suite *GatewaySuite) TestOpenAPIDocument() {
+	rec := suite.serve(http.MethodGet, "/openapi.yaml", "")
+	suite.Require().Equal(http.StatusOK, rec.Code)
+	suite.Require().Contains(rec.Body.String(), "/forwardtests/{id}/events:")
+}
+
+func (suite *GatewaySuite) TestStreamEvents() {
+	id := uuid.New()
+	event := forwardtest.Event{ID: 5, ForwardtestID: id, Type: forwardtest.EventTypeOrderFilled}
+
+	// The events are listed after the last event ID
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.ListForwardtestEventsWorkflowName, mock.MatchedBy(func(params api.ListForwardtestEventsWorkflowParams) bool {
+			return params.After == 4
+		})).Return(suite.run, nil).Once()
+	suite.run.On("Get", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(1).(*api.ListForwardtestEventsWorkflowResults).Events = []forwardtest.Event{event}
+	}).Return(nil).Once()
+	suite.temporal.On("ExecuteWorkflow", mock.Anything, mock.Anything,
+		api.ListForwardtestEventsWorkflowName, mock.Anything).Return(suite.run, nil).Maybe()
+	suite.run.On("Get", mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	server := httptest.NewServer(suite.gateway)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/forwardtests/"+id.String()+"/events", nil)
+	suite.Require().NoError(err)
+	req.Header.Set("Last-Event-ID", "4")
+
+	resp, err := http.DefaultClient.Do(req)
+	suite.Require().NoError(err)
+	defer resp.Body.Close()
+	suite.Require().Equal(http.StatusOK, resp.StatusCode)
+	suite.Require().Equal("text/event-stream", resp.Header.Get("Content-Type"))
+
+	// Read the first event
+	scanner := bufio.NewScanner(resp.Body)
+	var lines []string
+	for scanner.Scan() && scanner.Text() != "" {
+		lines = append(lines, scanner.Text())
+	}
+	suite.Require().Len(lines, 3)
+	suite.Require().Equal("id: 5", lines[0])
+	suite.Require().Equal("event: order_filled", lines[1])
+
+	var received forwardtest.Event
+	suite.Require().NoError(json.Unmarshal([]byte(strings.TrimPrefix(lines[2], "data: ")), &received))
+	suite.Require().Equal(event, received)
+}
@@ -0,0 +1,102 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// defaultQuarantineLimit is the default number of quarantined ticks returned
+// in the forwardtest diagnostics.
+const defaultQuarantineLimit = 100
+
+// defaultCallbackErrorLimit is the default number of callback errors returned
+// in the forwardtest diagnostics.
+const defaultCallbackErrorLimit = 100
+
+// GetForwardtestDiagnosticsWorkflow gets the state of the price feeds of a
+// forwardtest: its subscriptions and the ticks rejected by its tick filter,
+// along with the failures of its callbacks.
+func (wf *workflows) GetForwardtestDiagnosticsWorkflow(
+	ctx workflow.Context,
+	params api.GetForwardtestDiagnosticsWorkflowParams,
+) (api.GetForwardtestDiagnosticsWorkflowResults, error) {
+	// Get subscriptions, with their stale marks
+	subs, err := wf.ListForwardtestSubscriptionsWorkflow(ctx, api.ListForwardtestSubscriptionsWorkflowParams{
+		ForwardtestID: params.ForwardtestID,
+	})
+	if err != nil {
+		return api.GetForwardtestDiagnosticsWorkflowResults{}, err
+	}
+
+	// List the last quarantined ticks
+	limit := params.QuarantineLimit
+	if limit <= 0 {
+		limit = defaultQuarantineLimit
+	}
+
+	var quarantineRes db.ListQuarantinedTicksActivityResult
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListQuarantinedTicksActivity, db.ListQuarantinedTicksActivityParams{
+			ForwardtestID: params.ForwardtestID,
+			Limit:         limit,
+		}).Get(ctx, &quarantineRes)
+	if err != nil {
+		return api.GetForwardtestDiagnosticsWorkflowResults{},
+			fmt.Errorf("listing quarantined ticks from db: %w", err)
+	}
+
+	// List the last callback errors
+	callbackErrors, failures, err := wf.getCallbackErrors(ctx, params)
+	if err != nil {
+		return api.GetForwardtestDiagnosticsWorkflowResults{}, err
+	}
+
+	// Count the rejected ticks
+	var rejected int64
+	for _, sub := range subs.Subscriptions {
+		rejected += sub.RejectedTickCount
+	}
+
+	return api.GetForwardtestDiagnosticsWorkflowResults{
+		Subscriptions:               subs.Subscriptions,
+		RejectedTickCount:           rejected,
+		QuarantinedTicks:            quarantineRes.Ticks,
+		CallbackErrors:              callbackErrors,
+		ConsecutiveCallbackFailures: failures,
+	}, nil
+}
+
+// getCallbackErrors returns the last callback errors of the forwardtest and
+// its current number of consecutive failures.
+func (wf *workflows) getCallbackErrors(
+	ctx workflow.Context,
+	params api.GetForwardtestDiagnosticsWorkflowParams,
+) ([]forwardtest.CallbackError, int, error) {
+	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	limit := params.CallbackErrorLimit
+	if limit <= 0 {
+		limit = defaultCallbackErrorLimit
+	}
+
+	var res db.ListCallbackErrorsActivityResult
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListCallbackErrorsActivity, db.ListCallbackErrorsActivityParams{
+			ForwardtestID: params.ForwardtestID,
+			Limit:         limit,
+		}).Get(ctx, &res)
+	if err != nil {
+		return nil, 0, fmt.Errorf("listing callback errors from db: %w", err)
+	}
+
+	return res.Errors, ft.ConsecutiveCallbackFailures, nil
+}
@@ -0,0 +1,38 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ListForwardtestSubscriptionsWorkflow lists the price subscriptions of a forwardtest.
+func (wf *workflows) ListForwardtestSubscriptionsWorkflow(
+	ctx workflow.Context,
+	params api.ListForwardtestSubscriptionsWorkflowParams,
+) (api.ListForwardtestSubscriptionsWorkflowResults, error) {
+	// Check that the forwardtest exists
+	_, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.ListForwardtestSubscriptionsWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// List subscriptions from database
+	var res db.ListSubscriptionsActivityResult
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: params.ForwardtestID,
+		}).Get(ctx, &res)
+	if err != nil {
+		return api.ListForwardtestSubscriptionsWorkflowResults{},
+			fmt.Errorf("listing subscriptions from db: %w", err)
+	}
+
+	return api.ListForwardtestSubscriptionsWorkflowResults{
+		Subscriptions: res.Subscriptions,
+	}, nil
+}
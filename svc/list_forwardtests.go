@@ -9,7 +9,7 @@ import (
 // ListForwardtestsWorkflow lists the forwardtests present in the system.
 func (wf *workflows) ListForwardtestsWorkflow(
 	ctx workflow.Context,
-	_ api.ListForwardtestsWorkflowParams,
+	params api.ListForwardtestsWorkflowParams,
 ) (api.ListForwardtestsWorkflowResults, error) {
 	logger := workflow.GetLogger(ctx)
 	logger.Info("Listing forwardtests")
@@ -18,7 +18,9 @@ func (wf *workflows) ListForwardtestsWorkflow(
 	var res db.ListForwardtestsActivityResult
 	err := workflow.ExecuteActivity(
 		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
-		wf.db.ListForwardtestsActivity, db.ListForwardtestsActivityParams{}).Get(ctx, &res)
+		wf.db.ListForwardtestsActivity, db.ListForwardtestsActivityParams{
+			IncludeArchived: params.IncludeArchived,
+		}).Get(ctx, &res)
 	if err != nil {
 		logger.Error("Error listing forwardtests",
 			"error", err.Error())
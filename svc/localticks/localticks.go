@@ -0,0 +1,251 @@
+// Package localticks is a tick source built into the worker, to be used
+// instead of the ticks and candlesticks services for development and tests.
+package localticks
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	candlesticksapi "github.com/cryptellation/candlesticks/api"
+	"github.com/cryptellation/candlesticks/pkg/candlestick"
+	candlesticksclients "github.com/cryptellation/candlesticks/pkg/clients"
+	ticksapi "github.com/cryptellation/ticks/api"
+	tickclients "github.com/cryptellation/ticks/pkg/clients"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// ListenToTicksWorkflowName is the name of the ListenToTicksWorkflow.
+	ListenToTicksWorkflowName = "LocalListenToTicksWorkflow"
+	// GetPriceActivityName is the name of the GetPriceActivity.
+	GetPriceActivityName = "LocalGetPriceActivity"
+
+	// ticksBeforeContinueAsNew is the number of ticks sent by the
+	// ListenToTicksWorkflow before continuing as new.
+	ticksBeforeContinueAsNew = 500
+)
+
+// ListenToTicksWorkflowParams is the input of the ListenToTicksWorkflow.
+type ListenToTicksWorkflowParams struct {
+	Registration ticksapi.RegisterForTicksListeningWorkflowParams
+	Interval     time.Duration
+}
+
+// GetPriceActivityParams is the input of the GetPriceActivity.
+type GetPriceActivityParams struct {
+	Exchange string
+	Pair     string
+	Time     time.Time
+}
+
+// GetPriceActivityResult is the output of the GetPriceActivity.
+type GetPriceActivityResult struct {
+	Price float64
+}
+
+// Ticks produces ticks from a local source and sends them to the registered
+// callbacks, the same way the ticks service does.
+type Ticks struct {
+	source   Source
+	interval time.Duration
+}
+
+// New creates new local ticks, sent every interval.
+func New(source Source, interval time.Duration) *Ticks {
+	return &Ticks{
+		source:   source,
+		interval: interval,
+	}
+}
+
+// Register registers the local ticks workflows and activities to the worker.
+func (lt *Ticks) Register(w worker.Worker) {
+	w.RegisterWorkflowWithOptions(lt.ListenToTicksWorkflow, workflow.RegisterOptions{
+		Name: ListenToTicksWorkflowName,
+	})
+	w.RegisterActivityWithOptions(lt.GetPriceActivity, activity.RegisterOptions{
+		Name: GetPriceActivityName,
+	})
+}
+
+// GetPriceActivity gets the price of a pair from the source.
+func (lt *Ticks) GetPriceActivity(
+	_ context.Context,
+	params GetPriceActivityParams,
+) (GetPriceActivityResult, error) {
+	price, err := lt.source.Price(params.Exchange, params.Pair, params.Time)
+	if err != nil {
+		return GetPriceActivityResult{}, temporal.NewNonRetryableApplicationError(err.Error(), "LocalTicksError", err)
+	}
+
+	return GetPriceActivityResult{Price: price}, nil
+}
+
+// ListenToTicksWorkflow sends a tick of the pair to the callback on each
+// interval, until it is cancelled.
+func (lt *Ticks) ListenToTicksWorkflow(ctx workflow.Context, params ListenToTicksWorkflowParams) error {
+	reg := params.Registration
+	for i := 0; ; i++ {
+		if err := workflow.Sleep(ctx, params.Interval); err != nil {
+			return err
+		}
+
+		// Get the tick
+		now := workflow.Now(ctx)
+		p, err := getPrice(ctx, reg.Exchange, reg.Pair, now)
+		if err != nil {
+			return err
+		}
+
+		// Send it to the callback
+		opts := workflow.ChildWorkflowOptions{
+			TaskQueue:                reg.Callback.TaskQueueName,
+			WorkflowExecutionTimeout: reg.Callback.ExecutionTimeout,
+		}
+		err = workflow.ExecuteChildWorkflow(
+			workflow.WithChildOptions(ctx, opts),
+			reg.Callback.Name,
+			ticksapi.ListenToTicksCallbackWorkflowParams{
+				RequesterID: reg.RequesterID,
+				Tick: tick.Tick{
+					Time:     now,
+					Exchange: reg.Exchange,
+					Pair:     reg.Pair,
+					Price:    p,
+				},
+			}).Get(ctx, nil)
+		if err != nil {
+			workflow.GetLogger(ctx).Error("Failed to send local tick", "error", err.Error())
+		}
+
+		// Continue as new to keep the history small
+		if i+1 >= ticksBeforeContinueAsNew || workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			return workflow.NewContinueAsNewError(ctx, ListenToTicksWorkflowName, params)
+		}
+	}
+}
+
+func getPrice(ctx workflow.Context, exchange, pair string, t time.Time) (float64, error) {
+	var res GetPriceActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+			StartToCloseTimeout: 10 * time.Second,
+		}),
+		GetPriceActivityName, GetPriceActivityParams{
+			Exchange: exchange,
+			Pair:     pair,
+			Time:     t,
+		}).Get(ctx, &res)
+	return res.Price, err
+}
+
+func listenerWorkflowID(requesterID uuid.UUID, exchange, pair string) string {
+	return fmt.Sprintf("local-ticks-%s-%s-%s", requesterID.String(), exchange, pair)
+}
+
+var _ tickclients.WfClient = (*Ticks)(nil)
+
+// ListenToTicks starts sending local ticks of the pair to the callback.
+func (lt *Ticks) ListenToTicks(
+	ctx workflow.Context,
+	params ticksapi.RegisterForTicksListeningWorkflowParams,
+) (ticksapi.RegisterForTicksListeningWorkflowResults, error) {
+	opts := workflow.ChildWorkflowOptions{
+		WorkflowID:            listenerWorkflowID(params.RequesterID, params.Exchange, params.Pair),
+		ParentClosePolicy:     enums.PARENT_CLOSE_POLICY_ABANDON,
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+	}
+
+	future := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		ListenToTicksWorkflowName,
+		ListenToTicksWorkflowParams{
+			Registration: params,
+			Interval:     lt.interval,
+		})
+	err := future.GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		return ticksapi.RegisterForTicksListeningWorkflowResults{}, err
+	}
+
+	return ticksapi.RegisterForTicksListeningWorkflowResults{}, nil
+}
+
+// StopListeningToTicks stops sending local ticks of the pair.
+func (lt *Ticks) StopListeningToTicks(
+	ctx workflow.Context,
+	params ticksapi.UnregisterFromTicksListeningWorkflowParams,
+) (ticksapi.UnregisterFromTicksListeningWorkflowResults, error) {
+	id := listenerWorkflowID(params.RequesterID, params.Exchange, params.Pair)
+	if err := workflow.RequestCancelExternalWorkflow(ctx, id, "").Get(ctx, nil); err != nil {
+		// The listener may already be stopped
+		workflow.GetLogger(ctx).Debug("Could not cancel local ticks listener",
+			"workflow_id", id, "error", err.Error())
+	}
+
+	return ticksapi.UnregisterFromTicksListeningWorkflowResults{}, nil
+}
+
+// CandlesticksClient returns a candlesticks client building the candlesticks
+// from the local source.
+func (lt *Ticks) CandlesticksClient() candlesticksclients.WfClient {
+	return candlesticksClient{}
+}
+
+type candlesticksClient struct{}
+
+// ListCandlesticks lists the candlesticks of the local source. Their prices
+// are the prices of the source at their opening and closing times.
+func (candlesticksClient) ListCandlesticks(
+	ctx workflow.Context,
+	params candlesticksapi.ListCandlesticksWorkflowParams,
+	_ *workflow.ChildWorkflowOptions,
+) (candlesticksapi.ListCandlesticksWorkflowResults, error) {
+	end := workflow.Now(ctx)
+	if params.End != nil && params.End.Before(end) {
+		end = *params.End
+	}
+	start := end
+	if params.Start != nil {
+		start = *params.Start
+	}
+
+	var list []candlestick.Candlestick
+	for t := params.Period.RoundTime(start); !t.After(end); t = t.Add(params.Period.Duration()) {
+		if params.Limit > 0 && uint(len(list)) >= params.Limit {
+			break
+		}
+
+		open, err := getPrice(ctx, params.Exchange, params.Pair, t)
+		if err != nil {
+			return candlesticksapi.ListCandlesticksWorkflowResults{}, err
+		}
+		closeTime := t.Add(params.Period.Duration())
+		if closeTime.After(end) {
+			closeTime = end
+		}
+		closePrice, err := getPrice(ctx, params.Exchange, params.Pair, closeTime)
+		if err != nil {
+			return candlesticksapi.ListCandlesticksWorkflowResults{}, err
+		}
+
+		list = append(list, candlestick.Candlestick{
+			Time:  t,
+			Open:  open,
+			High:  max(open, closePrice),
+			Low:   min(open, closePrice),
+			Close: closePrice,
+		})
+	}
+
+	return candlesticksapi.ListCandlesticksWorkflowResults{
+		List: list,
+	}, nil
+}
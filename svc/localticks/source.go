@@ -0,0 +1,271 @@
+package localticks
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"math"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+)
+
+var (
+	// ErrUnknownPair is returned when the source has no price for a pair.
+	ErrUnknownPair = errors.New("unknown pair")
+	// ErrInvalidSource is returned when the source settings or file are invalid.
+	ErrInvalidSource = errors.New("invalid tick source")
+)
+
+const (
+	// DefaultInitialPrice is the default initial price of a random walk.
+	DefaultInitialPrice = 100
+	// DefaultVolatility is the default volatility of a random walk.
+	DefaultVolatility = 0.001
+)
+
+// Source produces the prices of the local ticks.
+type Source interface {
+	// Price returns the price of the pair at the given time. It must always
+	// return the same price for the same parameters.
+	Price(exchange, pair string, t time.Time) (float64, error)
+}
+
+// RandomWalkParams are the parameters of a random walk source.
+type RandomWalkParams struct {
+	// Seed is the seed of the random walk, the same seed produces the same prices.
+	Seed int64
+	// Start is the time of the first step of the walk.
+	Start time.Time
+	// Interval is the duration of a step of the walk.
+	Interval time.Duration
+	// InitialPrice is the price of every pair at the start of the walk.
+	InitialPrice float64
+	// Volatility is the standard deviation of the relative change of the
+	// price on each step.
+	Volatility float64
+}
+
+// Validate validates the random walk parameters.
+func (p RandomWalkParams) Validate() error {
+	switch {
+	case p.Interval <= 0:
+		return fmt.Errorf("%w: non positive interval", ErrInvalidSource)
+	case p.InitialPrice <= 0:
+		return fmt.Errorf("%w: non positive initial price", ErrInvalidSource)
+	case p.Volatility < 0 || p.Volatility >= 1:
+		return fmt.Errorf("%w: volatility must be in [0, 1)", ErrInvalidSource)
+	}
+
+	return nil
+}
+
+// walkLevels is the number of halvings of the walk span, which lasts 2^walkLevels
+// steps (more than 30000 years with 1 second steps).
+const walkLevels = 40
+
+// RandomWalk is a source producing prices with a seeded random walk for any
+// pair. Each pair has its own walk.
+//
+// The walk is stateless: the price of each step is derived from the seed, the
+// pair and the step, by splitting the walk span in halves down to the step
+// (Brownian bridge). It costs walkLevels random numbers, whatever the step and
+// the order of the requests.
+type RandomWalk struct {
+	params RandomWalkParams
+}
+
+// NewRandomWalk creates a new random walk source.
+func NewRandomWalk(params RandomWalkParams) (*RandomWalk, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &RandomWalk{
+		params: params,
+	}, nil
+}
+
+// Price returns the price of the pair at the given time.
+func (rw *RandomWalk) Price(exchange, pair string, t time.Time) (float64, error) {
+	step := uint64(0)
+	if t.After(rw.params.Start) {
+		step = min(uint64(t.Sub(rw.params.Start)/rw.params.Interval), 1<<walkLevels)
+	}
+
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(exchange + "/" + pair))
+	seed := uint64(rw.params.Seed) ^ h.Sum64()
+
+	// The walk is on the logarithm of the price, to keep it positive
+	return rw.params.InitialPrice * math.Exp(rw.params.Volatility*walkSum(seed, step)), nil
+}
+
+// walkSum returns the sum of the standard normal changes of the walk before
+// the given step.
+func walkSum(seed, step uint64) float64 {
+	// Sum of the whole walk span
+	total := math.Sqrt(1<<walkLevels) * walkNormal(seed, walkLevels, 0)
+	if step == 1<<walkLevels {
+		return total
+	}
+
+	// Split the block containing the step in halves until it is the step,
+	// knowing the sum of the block: the sum before the block is accumulated
+	sum, lo := 0.0, uint64(0)
+	for level := uint64(walkLevels); level > 0; level-- {
+		half := uint64(1) << (level - 1)
+		left := total/2 + math.Sqrt(float64(half)/2)*walkNormal(seed, level, lo>>level)
+		if step < lo+half {
+			total = left
+		} else {
+			sum, total, lo = sum+left, total-left, lo+half
+		}
+	}
+
+	return sum
+}
+
+// walkNormal returns the standard normal random number of a block of the walk,
+// identified by its level and its index in the level.
+func walkNormal(seed, level, index uint64) float64 {
+	return rand.New(rand.NewPCG(seed, index<<6|level)).NormFloat64()
+}
+
+// File is a source producing prices from the ticks of a file. The ticks of
+// each pair are played in a loop, the first one being at the start time.
+type File struct {
+	start time.Time
+	ticks map[string][]tick.Tick
+}
+
+// LoadFile loads the ticks of a CSV or JSON file, depending on its extension.
+// CSV files have a header and the "time,exchange,pair,price" columns, with
+// RFC3339 times. JSON files contain an array of ticks.
+func LoadFile(path string, start time.Time) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening ticks file: %w", err)
+	}
+	defer f.Close()
+
+	var ticks []tick.Tick
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		ticks, err = readCSV(f)
+	case ".json":
+		err = json.NewDecoder(f).Decode(&ticks)
+	default:
+		err = fmt.Errorf("%w: unsupported file extension %q", ErrInvalidSource, filepath.Ext(path))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading ticks file: %w", err)
+	}
+
+	return NewFile(ticks, start)
+}
+
+// NewFile creates a new file source from ticks.
+func NewFile(ticks []tick.Tick, start time.Time) (*File, error) {
+	if len(ticks) == 0 {
+		return nil, fmt.Errorf("%w: no tick", ErrInvalidSource)
+	}
+
+	byPair := make(map[string][]tick.Tick)
+	for _, t := range ticks {
+		if t.Price <= 0 {
+			return nil, fmt.Errorf("%w: non positive price on tick %s", ErrInvalidSource, t)
+		}
+		key := t.Exchange + "/" + t.Pair
+		byPair[key] = append(byPair[key], t)
+	}
+
+	for _, pairTicks := range byPair {
+		slices.SortStableFunc(pairTicks, func(a, b tick.Tick) int {
+			return a.Time.Compare(b.Time)
+		})
+	}
+
+	return &File{
+		start: start,
+		ticks: byPair,
+	}, nil
+}
+
+// Price returns the price of the pair at the given time.
+func (f *File) Price(exchange, pair string, t time.Time) (float64, error) {
+	ticks, ok := f.ticks[exchange+"/"+pair]
+	if !ok {
+		return 0, fmt.Errorf("%w: %s %s", ErrUnknownPair, exchange, pair)
+	}
+
+	// Get the position in the loop of the ticks, the last tick lasting as
+	// long as the average interval between ticks
+	first, last := ticks[0].Time, ticks[len(ticks)-1].Time
+	span := last.Sub(first)
+	if len(ticks) > 1 {
+		span += span / time.Duration(len(ticks)-1)
+	}
+
+	offset := time.Duration(0)
+	if span > 0 && t.After(f.start) {
+		offset = t.Sub(f.start) % span
+	}
+
+	// Get the last tick before the position
+	i, found := slices.BinarySearchFunc(ticks, first.Add(offset), func(e tick.Tick, target time.Time) int {
+		return e.Time.Compare(target)
+	})
+	if !found {
+		i--
+	}
+
+	return ticks[max(i, 0)].Price, nil
+}
+
+func readCSV(r io.Reader) ([]tick.Tick, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	// Skip header
+	ticks := make([]tick.Tick, 0, len(records)-1)
+	for i, record := range records[1:] {
+		if len(record) != 4 {
+			return nil, fmt.Errorf("%w: line %d: expected 4 columns", ErrInvalidSource, i+2)
+		}
+
+		t, err := time.Parse(time.RFC3339, record[0])
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidSource, i+2, err)
+		}
+
+		price, err := strconv.ParseFloat(record[3], 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d: %w", ErrInvalidSource, i+2, err)
+		}
+
+		ticks = append(ticks, tick.Tick{
+			Time:     t,
+			Exchange: record[1],
+			Pair:     record[2],
+			Price:    price,
+		})
+	}
+
+	return ticks, nil
+}
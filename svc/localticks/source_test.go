@@ -0,0 +1,148 @@
+//go:build unit
+// +build unit
+
+package localticks
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/stretchr/testify/suite"
+)
+
+func TestSourceSuite(t *testing.T) {
+	suite.Run(t, new(SourceSuite))
+}
+
+type SourceSuite struct {
+	suite.Suite
+}
+
+func (suite *SourceSuite) TestRandomWalk() {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	params := RandomWalkParams{
+		Seed:         42,
+		Start:        start,
+		Interval:     time.Second,
+		InitialPrice: 100,
+		Volatility:   0.01,
+	}
+	rw, err := NewRandomWalk(params)
+	suite.Require().NoError(err)
+
+	// Initial price at start and before
+	p, err := rw.Price("exchange", "ETH-USDT", start.Add(-time.Hour))
+	suite.Require().NoError(err)
+	suite.Require().Equal(100.0, p)
+
+	// Same price for the same time, whatever the order of the requests
+	later, err := rw.Price("exchange", "ETH-USDT", start.Add(time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().Greater(later, 0.0)
+	sooner, err := rw.Price("exchange", "ETH-USDT", start.Add(30*time.Second))
+	suite.Require().NoError(err)
+	again, err := rw.Price("exchange", "ETH-USDT", start.Add(time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().Equal(later, again)
+
+	// Same prices with another source with the same seed
+	other, err := NewRandomWalk(params)
+	suite.Require().NoError(err)
+	p, err = other.Price("exchange", "ETH-USDT", start.Add(30*time.Second))
+	suite.Require().NoError(err)
+	suite.Require().Equal(sooner, p)
+
+	// Consecutive steps are close
+	next, err := rw.Price("exchange", "ETH-USDT", start.Add(31*time.Second))
+	suite.Require().NoError(err)
+	suite.Require().InDelta(1, next/sooner, 10*params.Volatility)
+
+	// Far steps are computed without walking from the start
+	far, err := rw.Price("exchange", "ETH-USDT", start.Add(10*365*24*time.Hour))
+	suite.Require().NoError(err)
+	suite.Require().Greater(far, 0.0)
+
+	// Pairs have their own walk
+	p, err = rw.Price("exchange", "BTC-USDT", start.Add(time.Minute))
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(later, p)
+}
+
+func (suite *SourceSuite) TestRandomWalkValidate() {
+	_, err := NewRandomWalk(RandomWalkParams{InitialPrice: 100})
+	suite.Require().ErrorIs(err, ErrInvalidSource)
+
+	_, err = NewRandomWalk(RandomWalkParams{Interval: time.Second})
+	suite.Require().ErrorIs(err, ErrInvalidSource)
+
+	_, err = NewRandomWalk(RandomWalkParams{Interval: time.Second, InitialPrice: 100, Volatility: 1})
+	suite.Require().ErrorIs(err, ErrInvalidSource)
+}
+
+func (suite *SourceSuite) TestFile() {
+	first := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	f, err := NewFile([]tick.Tick{
+		{Time: first.Add(20 * time.Second), Exchange: "exchange", Pair: "ETH-USDT", Price: 3},
+		{Time: first, Exchange: "exchange", Pair: "ETH-USDT", Price: 1},
+		{Time: first.Add(10 * time.Second), Exchange: "exchange", Pair: "ETH-USDT", Price: 2},
+	}, start)
+	suite.Require().NoError(err)
+
+	// Ticks are played from the start, in a loop of 30 seconds
+	for offset, expected := range map[time.Duration]float64{
+		-time.Minute:     1,
+		0:                1,
+		5 * time.Second:  1,
+		10 * time.Second: 2,
+		25 * time.Second: 3,
+		30 * time.Second: 1,
+		41 * time.Second: 2,
+	} {
+		p, err := f.Price("exchange", "ETH-USDT", start.Add(offset))
+		suite.Require().NoError(err)
+		suite.Require().Equal(expected, p, offset.String())
+	}
+
+	// Unknown pair
+	_, err = f.Price("exchange", "BTC-USDT", start)
+	suite.Require().ErrorIs(err, ErrUnknownPair)
+}
+
+func (suite *SourceSuite) TestLoadFile() {
+	dir := suite.T().TempDir()
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// CSV file
+	csvPath := filepath.Join(dir, "ticks.csv")
+	suite.Require().NoError(os.WriteFile(csvPath, []byte(
+		"time,exchange,pair,price\n"+
+			"2020-01-01T00:00:00Z,exchange,ETH-USDT,1000\n"+
+			"2020-01-01T00:00:01Z,exchange,ETH-USDT,1001\n"), 0o600))
+	f, err := LoadFile(csvPath, start)
+	suite.Require().NoError(err)
+	p, err := f.Price("exchange", "ETH-USDT", start.Add(time.Second))
+	suite.Require().NoError(err)
+	suite.Require().Equal(1001.0, p)
+
+	// JSON file
+	jsonPath := filepath.Join(dir, "ticks.json")
+	suite.Require().NoError(os.WriteFile(jsonPath, []byte(
+		`[{"time":"2020-01-01T00:00:00Z","exchange":"exchange","pair":"ETH-USDT","price":1000}]`), 0o600))
+	f, err = LoadFile(jsonPath, start)
+	suite.Require().NoError(err)
+	p, err = f.Price("exchange", "ETH-USDT", start)
+	suite.Require().NoError(err)
+	suite.Require().Equal(1000.0, p)
+
+	// Invalid files
+	_, err = LoadFile(filepath.Join(dir, "ticks.txt"), start)
+	suite.Require().Error(err)
+	badPath := filepath.Join(dir, "bad.csv")
+	suite.Require().NoError(os.WriteFile(badPath, []byte("time,exchange,pair,price\nbad,exchange,ETH-USDT,1\n"), 0o600))
+	_, err = LoadFile(badPath, start)
+	suite.Require().ErrorIs(err, ErrInvalidSource)
+}
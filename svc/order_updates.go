@@ -0,0 +1,86 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/order"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+// notifyOrderUpdate records the event of the order, rejected if there is a
+// cause, and starts the OnOrderUpdateCallback workflow of the forwardtest, if
+// any, without waiting for its completion. Failures are only logged as the
+// order has already been processed.
+func (wf *workflows) notifyOrderUpdate(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	status api.OrderUpdateStatus,
+	o order.Order,
+	cause error,
+) {
+	event := forwardtest.NewOrderEvent(ft.ID, workflow.Now(ctx), o, cause)
+	wf.recordEvent(ctx, event)
+
+	callback := ft.OptionalCallbacks.OnOrderUpdateCallback
+	if callback == nil {
+		return
+	}
+
+	opts := workflow.ChildWorkflowOptions{
+		// Unique identifier for this child workflow execution
+		WorkflowID: fmt.Sprintf("forwardtest-%s-on-order-update-%s-%s",
+			ft.ID.String(), o.ID.String(), status),
+		// Task queue where the child workflow will be executed
+		TaskQueue: callback.TaskQueueName,
+		// Maximum time allowed for the child workflow to complete
+		WorkflowExecutionTimeout: time.Second * 30,
+		// Do not wait for the callback to complete
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+	}
+
+	// Check if the timeout is set
+	if callback.ExecutionTimeout > 0 {
+		opts.WorkflowExecutionTimeout = callback.ExecutionTimeout
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		callback.Name,
+		api.OnOrderUpdateCallbackWorkflowParams{
+			Context: runtime.Context{
+				ID:              ft.ID,
+				Mode:            runtime.ModeForwardtest,
+				Now:             workflow.Now(ctx),
+				ParentTaskQueue: workflow.GetInfo(ctx).TaskQueueName,
+			},
+			Status:   status,
+			Order:    o,
+			Reason:   event.Reason,
+			Accounts: ft.Accounts,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		workflow.GetLogger(ctx).Error("Could not start OnOrderUpdateCallback workflow",
+			"forwardtest_id", ft.ID.String(),
+			"order_id", o.ID.String(),
+			"status", status.String(),
+			"error", err)
+	}
+}
+
+// rejectOrder notifies the rejection of an order and returns the
+// corresponding typed error.
+func (wf *workflows) rejectOrder(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	o order.Order,
+	err error,
+) error {
+	wf.notifyOrderUpdate(ctx, ft, api.OrderUpdateStatusRejected, o, err)
+	return toOrderError(err)
+}
@@ -0,0 +1,103 @@
+// Package preflight checks that the callbacks of a forwardtest can be
+// executed before using them, by looking for workers polling their task
+// queues.
+package preflight
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// CheckTaskQueuesActivityName is the name of the CheckTaskQueuesActivity.
+const CheckTaskQueuesActivityName = "CheckTaskQueuesActivity"
+
+type (
+	// CheckTaskQueuesActivityParams is the parameters for the CheckTaskQueuesActivity.
+	CheckTaskQueuesActivityParams struct {
+		TaskQueues []string
+	}
+
+	// CheckTaskQueuesActivityResult is the result for the CheckTaskQueuesActivity.
+	CheckTaskQueuesActivityResult struct {
+		// Unreachable are the task queues without any workflow poller, in
+		// the order of the parameters.
+		Unreachable []string
+	}
+)
+
+// Preflight is the interface of the preflight checks.
+type Preflight interface {
+	Register(w worker.Worker)
+
+	CheckTaskQueuesActivity(
+		ctx context.Context,
+		params CheckTaskQueuesActivityParams,
+	) (CheckTaskQueuesActivityResult, error)
+}
+
+// Activities are the preflight activities, based on a Temporal client.
+type Activities struct {
+	client client.Client
+}
+
+var _ Preflight = &Activities{}
+
+// New creates new preflight activities with the Temporal client.
+func New(c client.Client) *Activities {
+	return &Activities{
+		client: c,
+	}
+}
+
+// Register registers the preflight activities to the worker.
+func (a *Activities) Register(w worker.Worker) {
+	w.RegisterActivityWithOptions(a.CheckTaskQueuesActivity,
+		activity.RegisterOptions{Name: CheckTaskQueuesActivityName})
+}
+
+// CheckTaskQueuesActivity lists the task queues that have no worker polling
+// them for workflow tasks.
+func (a *Activities) CheckTaskQueuesActivity(
+	ctx context.Context,
+	params CheckTaskQueuesActivityParams,
+) (CheckTaskQueuesActivityResult, error) {
+	var res CheckTaskQueuesActivityResult
+	checked := make(map[string]bool, len(params.TaskQueues))
+	for _, tq := range params.TaskQueues {
+		if _, ok := checked[tq]; ok {
+			continue
+		}
+
+		desc, err := a.client.DescribeTaskQueue(ctx, tq, enums.TASK_QUEUE_TYPE_WORKFLOW)
+		if err != nil {
+			return CheckTaskQueuesActivityResult{}, fmt.Errorf("describing task queue %q: %w", tq, err)
+		}
+
+		checked[tq] = true
+		if len(desc.GetPollers()) == 0 {
+			res.Unreachable = append(res.Unreachable, tq)
+		}
+	}
+
+	return res, nil
+}
+
+// DefaultActivityOptions returns the default preflight activities options.
+// The checks are short as they are done while a user is waiting.
+func DefaultActivityOptions() workflow.ActivityOptions {
+	return workflow.ActivityOptions{
+		RetryPolicy: &temporal.RetryPolicy{
+			MaximumAttempts: 3,
+		},
+		StartToCloseTimeout:    5 * time.Second,
+		ScheduleToCloseTimeout: 15 * time.Second,
+	}
+}
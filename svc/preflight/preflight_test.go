@@ -0,0 +1,61 @@
+//go:build unit
+// +build unit
+
+package preflight
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/api/taskqueue/v1"
+	"go.temporal.io/api/workflowservice/v1"
+	"go.temporal.io/sdk/mocks"
+)
+
+func TestPreflightSuite(t *testing.T) {
+	suite.Run(t, new(PreflightSuite))
+}
+
+type PreflightSuite struct {
+	suite.Suite
+	client *mocks.Client
+}
+
+func (suite *PreflightSuite) SetupTest() {
+	suite.client = mocks.NewClient(suite.T())
+}
+
+func (suite *PreflightSuite) describe(tq string, pollers int) {
+	res := &workflowservice.DescribeTaskQueueResponse{}
+	for i := 0; i < pollers; i++ {
+		res.Pollers = append(res.Pollers, &taskqueue.PollerInfo{Identity: "worker"})
+	}
+	suite.client.On("DescribeTaskQueue", mock.Anything, tq, enums.TASK_QUEUE_TYPE_WORKFLOW).
+		Return(res, nil).Once()
+}
+
+func (suite *PreflightSuite) TestCheckTaskQueues() {
+	suite.describe("polled", 1)
+	suite.describe("typo", 0)
+
+	// Task queues are only described once
+	res, err := New(suite.client).CheckTaskQueuesActivity(context.Background(), CheckTaskQueuesActivityParams{
+		TaskQueues: []string{"polled", "typo", "polled", "typo"},
+	})
+	suite.Require().NoError(err)
+	suite.Require().Equal([]string{"typo"}, res.Unreachable)
+}
+
+func (suite *PreflightSuite) TestCheckTaskQueuesError() {
+	suite.client.On("DescribeTaskQueue", mock.Anything, "queue", enums.TASK_QUEUE_TYPE_WORKFLOW).
+		Return(nil, errors.New("unavailable")).Once()
+
+	_, err := New(suite.client).CheckTaskQueuesActivity(context.Background(), CheckTaskQueuesActivityParams{
+		TaskQueues: []string{"queue"},
+	})
+	suite.Require().Error(err)
+}
@@ -0,0 +1,162 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// reconciliationsBeforeContinueAsNew is the number of periodic
+	// reconciliations executed before continuing as new.
+	reconciliationsBeforeContinueAsNew = 100
+)
+
+// ReconcileForwardtestsWorkflow checks the subscriptions of the running
+// forwardtests and registers them again on the ticks service when needed.
+// As subscriptions are saved before their registration, a registration that
+// failed shows up as a stalled subscription; the registrations without
+// subscription are removed when their ticks are received. The events past
+// their retention are pruned on each reconciliation.
+// If an interval is set, the reconciliation is executed periodically.
+func (wf *workflows) ReconcileForwardtestsWorkflow(
+	ctx workflow.Context,
+	params api.ReconcileForwardtestsWorkflowParams,
+) (api.ReconcileForwardtestsWorkflowResults, error) {
+	logger := workflow.GetLogger(ctx)
+
+	for i := 0; ; i++ {
+		res, err := wf.reconcileForwardtests(ctx, params)
+		wf.pruneEvents(ctx, params.EventsRetention)
+		if params.Interval <= 0 {
+			return res, err
+		} else if err != nil {
+			// Don't return error here as the next reconciliation may succeed
+			logger.Error("Error reconciling forwardtests", "error", err.Error())
+		}
+
+		// Only resubscribe everything on the first reconciliation
+		params.ResubscribeAll = false
+
+		if err := workflow.Sleep(ctx, params.Interval); err != nil {
+			return api.ReconcileForwardtestsWorkflowResults{}, err
+		}
+
+		// Continue as new to keep the history small
+		if i+1 >= reconciliationsBeforeContinueAsNew || workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			return api.ReconcileForwardtestsWorkflowResults{},
+				workflow.NewContinueAsNewError(ctx, api.ReconcileForwardtestsWorkflowName, params)
+		}
+	}
+}
+
+// reconcileForwardtests executes one reconciliation of the running forwardtests.
+func (wf *workflows) reconcileForwardtests(
+	ctx workflow.Context,
+	params api.ReconcileForwardtestsWorkflowParams,
+) (api.ReconcileForwardtestsWorkflowResults, error) {
+	logger := workflow.GetLogger(ctx)
+
+	// List forwardtests
+	var listRes db.ListForwardtestsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListForwardtestsActivity, db.ListForwardtestsActivityParams{
+			IncludeArchived: true,
+		}).Get(ctx, &listRes)
+	if err != nil {
+		return api.ReconcileForwardtestsWorkflowResults{}, fmt.Errorf("listing forwardtests: %w", err)
+	}
+
+	var res api.ReconcileForwardtestsWorkflowResults
+	for _, ft := range listRes.Forwardtests {
+		// Replays don't receive live ticks
+		if ft.Status != forwardtest.StatusRunning || ft.IsReplay() {
+			continue
+		}
+
+		resubscribed, stalled, err := wf.reconcileForwardtest(ctx, ft, params)
+		if err != nil {
+			return res, err
+		}
+
+		res.Resubscribed += resubscribed
+		if stalled {
+			res.Stalled = append(res.Stalled, ft.ID)
+		}
+	}
+
+	logger.Info("Reconciled forwardtests",
+		"resubscribed", res.Resubscribed,
+		"stalled", len(res.Stalled))
+	return res, nil
+}
+
+// reconcileForwardtest checks the subscriptions of a running forwardtest. It
+// returns the number of subscriptions registered again and whether one of
+// them is stalled.
+func (wf *workflows) reconcileForwardtest(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	params api.ReconcileForwardtestsWorkflowParams,
+) (int, bool, error) {
+	logger := workflow.GetLogger(ctx)
+
+	// List forwardtest subscriptions
+	var subsRes db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: ft.ID,
+		}).Get(ctx, &subsRes)
+	if err != nil {
+		return 0, false, fmt.Errorf("listing subscriptions of forwardtest %s: %w", ft.ID, err)
+	}
+
+	resubscribed, stalled := 0, false
+	for _, sub := range subsRes.Subscriptions {
+		isStalled := params.TickTimeout > 0 && sub.IsStalled(workflow.Now(ctx), params.TickTimeout)
+		if isStalled {
+			stalled = true
+			logger.Warn("Forwardtest subscription has not received any tick for too long",
+				"forwardtest_id", ft.ID.String(),
+				"exchange", sub.Exchange,
+				"pair", sub.Pair,
+				"last_activity", sub.LastActivity())
+		}
+
+		if !isStalled && !params.ResubscribeAll {
+			continue
+		}
+
+		if err := wf.startPricesDelivery(ctx, sub); err != nil {
+			logger.Error("Failed to subscribe again",
+				"forwardtest_id", ft.ID.String(),
+				"exchange", sub.Exchange,
+				"pair", sub.Pair,
+				"error", err.Error())
+			continue
+		}
+		resubscribed++
+	}
+
+	// Start again the timers and the feed health watch lost with the
+	// previous workers
+	if params.ResubscribeAll {
+		if err := wf.restartTimers(ctx, ft.ID); err != nil {
+			logger.Error("Failed to restart timers",
+				"forwardtest_id", ft.ID.String(),
+				"error", err.Error())
+		}
+		if err := wf.startFeedHealthWatch(ctx, ft); err != nil {
+			logger.Error("Failed to restart feed health watch",
+				"forwardtest_id", ft.ID.String(),
+				"error", err.Error())
+		}
+	}
+
+	return resubscribed, stalled, nil
+}
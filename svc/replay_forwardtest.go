@@ -0,0 +1,237 @@
+package svc
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/workflow"
+)
+
+var (
+	// ErrInvalidReplaySpeed is the error when the speed of a replay is negative.
+	ErrInvalidReplaySpeed = errors.New("invalid replay speed")
+	// ErrNoReplayedPrice is the error when an order is created on a replay
+	// before any tick of its pair has been replayed.
+	ErrNoReplayedPrice = errors.New("no replayed price")
+)
+
+const (
+	// replayTicksWorkflowName is the name of the ReplayTicksWorkflow.
+	replayTicksWorkflowName = "ReplayTicksWorkflow"
+	// replayPageSize is the number of recorded ticks read at once by the replay.
+	replayPageSize = 100
+	// replayPagesBeforeContinueAsNew is the number of pages of recorded ticks
+	// replayed before continuing as new.
+	replayPagesBeforeContinueAsNew = 10
+)
+
+// replayTicksWorkflowParams is the input of the ReplayTicksWorkflow.
+type replayTicksWorkflowParams struct {
+	ForwardtestID uuid.UUID
+	SourceID      uuid.UUID
+	Speed         float64
+	// Offset is the number of recorded ticks already replayed.
+	Offset int
+	// LastTickTime is the time of the last replayed tick.
+	LastTickTime *time.Time
+	// Candlesticks are the candlesticks built from the replayed ticks, by
+	// exchange and pair, for the subscriptions delivering candlesticks.
+	Candlesticks map[string]*forwardtest.CandlestickBuilder
+}
+
+// ReplayForwardtestWorkflow creates a new forwardtest with the configuration
+// of a forwardtest and replays on it the ticks recorded on the original one.
+// The replay is executed in the background.
+func (wf *workflows) ReplayForwardtestWorkflow(
+	ctx workflow.Context,
+	params api.ReplayForwardtestWorkflowParams,
+) (api.ReplayForwardtestWorkflowResults, error) {
+	if params.Speed < 0 {
+		return api.ReplayForwardtestWorkflowResults{},
+			fmt.Errorf("%w: %f", ErrInvalidReplaySpeed, params.Speed)
+	}
+
+	// Read source forwardtest from database
+	source, err := wf.readForwardtestFromDB(ctx, params.SourceID)
+	if err != nil {
+		return api.ReplayForwardtestWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// Create the replay and save it to database
+	ft, err := source.NewReplay(forwardtest.CloneParams{
+		Callbacks: params.Callbacks,
+	})
+	if err != nil {
+		return api.ReplayForwardtestWorkflowResults{}, fmt.Errorf("creating replay: %w", err)
+	}
+
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.CreateForwardtestActivity, db.CreateForwardtestActivityParams{
+			Forwardtest: ft,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.ReplayForwardtestWorkflowResults{}, fmt.Errorf("saving replay to db: %w", err)
+	}
+
+	// Start the replay of the recorded ticks in the background
+	opts := workflow.ChildWorkflowOptions{
+		// Unique identifier for the replay of the forwardtest
+		WorkflowID: fmt.Sprintf("forwardtest-%s-replay", ft.ID.String()),
+		// The replay continues after this workflow returns
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+	}
+	future := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		replayTicksWorkflowName,
+		replayTicksWorkflowParams{
+			ForwardtestID: ft.ID,
+			SourceID:      source.ID,
+			Speed:         params.Speed,
+		})
+	if err := future.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		return api.ReplayForwardtestWorkflowResults{}, fmt.Errorf("starting replay: %w", err)
+	}
+
+	return api.ReplayForwardtestWorkflowResults{
+		ID: ft.ID,
+	}, nil
+}
+
+// replayTicksWorkflow is a private workflow that runs a replay forwardtest,
+// delivers it the recorded ticks through the same path as live ticks and
+// stops it once all the ticks have been replayed.
+func (wf *workflows) replayTicksWorkflow(
+	ctx workflow.Context,
+	params replayTicksWorkflowParams,
+) error {
+	// Run the forwardtest on the first execution
+	if params.Offset == 0 {
+		_, err := wf.RunForwardtestWorkflow(ctx, api.RunForwardtestWorkflowParams{
+			ForwardtestID: params.ForwardtestID,
+		})
+		if err != nil {
+			return fmt.Errorf("running replay: %w", err)
+		}
+	}
+
+	for page := 0; ; page++ {
+		// Continue as new to keep the history small
+		if page >= replayPagesBeforeContinueAsNew || workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			return workflow.NewContinueAsNewError(ctx, replayTicksWorkflowName, params)
+		}
+
+		// Read the next recorded ticks
+		var res db.ListRecordedTicksActivityResult
+		err := workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+			wf.db.ListRecordedTicksActivity, db.ListRecordedTicksActivityParams{
+				ForwardtestID: params.SourceID,
+				Offset:        params.Offset,
+				Limit:         replayPageSize,
+			}).Get(ctx, &res)
+		if err != nil {
+			return fmt.Errorf("listing recorded ticks: %w", err)
+		}
+
+		// Stop the forwardtest when all ticks have been replayed
+		if len(res.Ticks) == 0 {
+			_, err := wf.StopForwardtestWorkflow(ctx, api.StopForwardtestWorkflowParams{
+				ForwardtestID: params.ForwardtestID,
+			})
+			return err
+		}
+
+		running, err := wf.replayTicks(ctx, &params, res)
+		if err != nil || !running {
+			return err
+		}
+	}
+}
+
+// replayTicks delivers a page of recorded ticks to the replay, waiting
+// between them according to the replay speed. It returns false if the replay
+// has been stopped or deleted.
+func (wf *workflows) replayTicks(
+	ctx workflow.Context,
+	params *replayTicksWorkflowParams,
+	res db.ListRecordedTicksActivityResult,
+) (bool, error) {
+	for _, t := range res.Ticks {
+		// Wait for the time between the ticks, a zero speed replays them
+		// as fast as possible
+		if params.Speed > 0 && params.LastTickTime != nil {
+			wait := time.Duration(float64(t.Time.Sub(*params.LastTickTime)) / params.Speed)
+			if wait > 0 {
+				if err := workflow.Sleep(ctx, wait); err != nil {
+					return false, err
+				}
+			}
+		}
+
+		// Read the replay to check it is still running
+		ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+		if db.IsRecordNotFound(err) {
+			return false, nil
+		} else if err != nil {
+			return false, fmt.Errorf("could not read forwardtest from db: %w", err)
+		} else if ft.Status != forwardtest.StatusRunning {
+			return false, nil
+		}
+
+		sub, err := wf.deliverTick(ctx, ft, t)
+		if err != nil {
+			return false, fmt.Errorf("replaying tick %s: %w", t, err)
+		} else if sub != nil && sub.DeliversCandlesticks() {
+			wf.replayCandlestick(ctx, params, &ft, *sub, t)
+		}
+
+		params.Offset++
+		params.LastTickTime = &t.Time
+	}
+
+	return true, nil
+}
+
+// replayCandlestick adds a replayed tick to the candlestick of its
+// subscription and delivers the candlestick once it is closed, as the
+// candlesticks service can't deliver the candlesticks of a replay.
+func (wf *workflows) replayCandlestick(
+	ctx workflow.Context,
+	params *replayTicksWorkflowParams,
+	ft *forwardtest.Forwardtest,
+	sub forwardtest.Subscription,
+	t tick.Tick,
+) {
+	if params.Candlesticks == nil {
+		params.Candlesticks = make(map[string]*forwardtest.CandlestickBuilder)
+	}
+
+	key := fmt.Sprintf("%s/%s", sub.Exchange, sub.Pair)
+	builder, ok := params.Candlesticks[key]
+	if !ok || builder.Period != *sub.Period {
+		builder = &forwardtest.CandlestickBuilder{Period: *sub.Period}
+		params.Candlesticks[key] = builder
+	}
+
+	cs, closed := builder.Add(t)
+	if !closed {
+		return
+	}
+
+	if err := wf.executeOnNewCandlestickCallback(ctx, ft, sub, cs); err != nil {
+		workflow.GetLogger(ctx).Error("Failed to deliver replayed candlestick to forwardtest",
+			"forwardtest_id", ft.ID.String(),
+			"exchange", sub.Exchange,
+			"pair", sub.Pair,
+			"error", err.Error())
+	}
+}
@@ -0,0 +1,52 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"go.temporal.io/sdk/workflow"
+)
+
+// ResetForwardtestWorkflow restores a forwardtest to its initial state while
+// keeping its ID. It refuses to reset a running forwardtest.
+func (wf *workflows) ResetForwardtestWorkflow(
+	ctx workflow.Context,
+	params api.ResetForwardtestWorkflowParams,
+) (api.ResetForwardtestWorkflowResults, error) {
+	logger := workflow.GetLogger(ctx)
+
+	// Read forwardtest from database
+	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.ResetForwardtestWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// Reset forwardtest
+	logger.Info("Resetting forwardtest",
+		"forwardtest_id", params.ForwardtestID.String(),
+		"status", ft.Status.String())
+	if err := ft.Reset(workflow.Now(ctx)); err != nil {
+		return api.ResetForwardtestWorkflowResults{},
+			fmt.Errorf("resetting forwardtest %s: %w", params.ForwardtestID, err)
+	}
+
+	// Save forwardtest to database
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.UpdateForwardtestActivity, db.UpdateForwardtestActivityParams{
+			Forwardtest: ft,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.ResetForwardtestWorkflowResults{},
+			fmt.Errorf("saving reset forwardtest: %w", err)
+	}
+	wf.recordEvent(ctx, forwardtest.NewStatusChangedEvent(ft, workflow.Now(ctx)))
+
+	// Unsubscribe from all prices, now that the forwardtest is not running
+	wf.unsubscribeAll(ctx, params.ForwardtestID)
+
+	return api.ResetForwardtestWorkflowResults{}, nil
+}
@@ -22,8 +22,21 @@ func (wf *workflows) RunForwardtestWorkflow(
 		return forwardtestsapi.RunForwardtestWorkflowResults{}, fmt.Errorf("loading forwardtest from database: %w", err)
 	}
 
+	// Check that the callbacks can be executed, if requested
+	if params.Preflight {
+		if err := wf.checkCallbacks(ctx, forwardtest.ListCallbacks(ft.Callbacks, ft.OptionalCallbacks)); err != nil {
+			return forwardtestsapi.RunForwardtestWorkflowResults{}, err
+		}
+	}
+
+	// Start again without the failures of a previous run
+	if err := wf.resetCallbackFailures(ctx, ft); err != nil {
+		return forwardtestsapi.RunForwardtestWorkflowResults{}, err
+	}
+
 	// Update forwardtest status to running
 	ft.Status = forwardtest.StatusRunning
+	ft.StatusReason = ""
 	err = workflow.ExecuteActivity(
 		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
 		wf.db.UpdateForwardtestActivity, db.UpdateForwardtestActivityParams{
@@ -32,6 +45,7 @@ func (wf *workflows) RunForwardtestWorkflow(
 	if err != nil {
 		return forwardtestsapi.RunForwardtestWorkflowResults{}, fmt.Errorf("updating forwardtest status to running: %w", err)
 	}
+	wf.recordEvent(ctx, forwardtest.NewStatusChangedEvent(ft, workflow.Now(ctx)))
 
 	// Execute the init callback workflow
 	childWorkflowOptions := workflow.ChildWorkflowOptions{
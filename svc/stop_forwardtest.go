@@ -12,6 +12,8 @@ import (
 )
 
 // StopForwardtestWorkflow stops a forwardtest by executing the exit callback.
+// Stopping a finished forwardtest only stops what may be left of its prices
+// and timers, so the stop is idempotent.
 func (wf *workflows) StopForwardtestWorkflow(
 	ctx workflow.Context,
 	params forwardtestsapi.StopForwardtestWorkflowParams,
@@ -20,10 +22,14 @@ func (wf *workflows) StopForwardtestWorkflow(
 	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
 	if err != nil {
 		return forwardtestsapi.StopForwardtestWorkflowResults{}, fmt.Errorf("loading forwardtest from database: %w", err)
+	} else if ft.Status == forwardtest.StatusFinished {
+		wf.teardown(ctx, params.ForwardtestID)
+		return forwardtestsapi.StopForwardtestWorkflowResults{}, nil
 	}
 
 	// Update forwardtest status to finished
 	ft.Status = forwardtest.StatusFinished
+	ft.StatusReason = params.Reason
 	err = workflow.ExecuteActivity(
 		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
 		wf.db.UpdateForwardtestActivity, db.UpdateForwardtestActivityParams{
@@ -33,6 +39,10 @@ func (wf *workflows) StopForwardtestWorkflow(
 		return forwardtestsapi.StopForwardtestWorkflowResults{},
 			fmt.Errorf("updating forwardtest status to finished: %w", err)
 	}
+	wf.recordEvent(ctx, forwardtest.NewStatusChangedEvent(ft, workflow.Now(ctx)))
+
+	// Stop prices and timers, now that the forwardtest is finished
+	wf.teardown(ctx, params.ForwardtestID)
 
 	// Execute the exit callback workflow
 	childWorkflowOptions := workflow.ChildWorkflowOptions{
@@ -6,18 +6,94 @@ import (
 
 	"github.com/cryptellation/forwardtests/api"
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
 	"github.com/cryptellation/runtime"
 	ticksapi "github.com/cryptellation/ticks/api"
 	"github.com/cryptellation/ticks/pkg/tick"
+	"github.com/google/uuid"
 	"go.temporal.io/api/enums/v1"
 	"go.temporal.io/sdk/workflow"
 )
 
 // SubscribeToPriceWorkflow subscribes to price updates for a forwardtest.
+// If a period is set, closed candlesticks are delivered instead of ticks.
 func (wf *workflows) SubscribeToPriceWorkflow(
 	ctx workflow.Context,
 	params api.SubscribeToPriceWorkflowParams,
 ) (api.SubscribeToPriceWorkflowResults, error) {
+	sub := forwardtest.Subscription{
+		ForwardtestID:            params.ForwardtestID,
+		Exchange:                 params.Exchange,
+		Pair:                     params.Pair,
+		CreatedAt:                workflow.Now(ctx),
+		Period:                   params.Period,
+		OnNewCandlestickCallback: params.OnNewCandlestickCallback,
+	}
+	if err := sub.Validate(); err != nil {
+		return api.SubscribeToPriceWorkflowResults{}, fmt.Errorf("validating subscription: %w", err)
+	}
+
+	// Check that the pair is not already subscribed with another delivery mode
+	var listRes db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: params.ForwardtestID,
+		}).Get(ctx, &listRes)
+	if err != nil {
+		return api.SubscribeToPriceWorkflowResults{}, fmt.Errorf("listing subscriptions: %w", err)
+	}
+	for _, existing := range listRes.Subscriptions {
+		if existing.Exchange == sub.Exchange && existing.Pair == sub.Pair && !existing.SameDelivery(sub) {
+			return api.SubscribeToPriceWorkflowResults{},
+				fmt.Errorf("subscribing to %s %s: %w", sub.Exchange, sub.Pair, forwardtest.ErrSubscriptionConflict)
+		}
+	}
+
+	// Save subscription to database before registering to ticks, so that a
+	// registration always has a subscription to be reconciled with
+	err = workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.CreateSubscriptionActivity, db.CreateSubscriptionActivityParams{
+			Subscription: sub,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.SubscribeToPriceWorkflowResults{}, fmt.Errorf("saving subscription to db: %w", err)
+	}
+
+	// Start the delivery of prices, unless the ticks are replayed
+	ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.SubscribeToPriceWorkflowResults{}, fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+	if !ft.IsReplay() {
+		if err := wf.startPricesDelivery(ctx, sub); err != nil {
+			return api.SubscribeToPriceWorkflowResults{}, err
+		}
+	}
+
+	// Watch the price feeds to detect when they become stale
+	if err := wf.startFeedHealthWatch(ctx, ft); err != nil {
+		return api.SubscribeToPriceWorkflowResults{}, err
+	}
+
+	return api.SubscribeToPriceWorkflowResults{}, nil
+}
+
+// startPricesDelivery starts the delivery of the prices of a subscription:
+// closed candlesticks are delivered on their own, while ticks require to be
+// registered to the ticks service.
+func (wf *workflows) startPricesDelivery(ctx workflow.Context, sub forwardtest.Subscription) error {
+	if sub.DeliversCandlesticks() {
+		return wf.startCandlesticksDelivery(ctx, sub)
+	}
+
+	return wf.listenToTicks(ctx, sub.ForwardtestID, sub.Exchange, sub.Pair)
+}
+
+// listenToTicks registers the forwardtest to the ticks service, with the
+// proxy workflow as callback.
+func (wf *workflows) listenToTicks(ctx workflow.Context, forwardtestID uuid.UUID, exchange, pair string) error {
 	// Create callback workflow
 	callback := runtime.CallbackWorkflow{
 		Name:          forwardNewPriceToForwardTestWorkflowName,
@@ -25,13 +101,13 @@ func (wf *workflows) SubscribeToPriceWorkflow(
 	}
 
 	_, err := wf.ticks.ListenToTicks(ctx, ticksapi.RegisterForTicksListeningWorkflowParams{
-		RequesterID: params.ForwardtestID,
-		Exchange:    params.Exchange,
-		Pair:        params.Pair,
+		RequesterID: forwardtestID,
+		Exchange:    exchange,
+		Pair:        pair,
 		Callback:    callback,
 	})
 
-	return api.SubscribeToPriceWorkflowResults{}, err
+	return err
 }
 
 const (
@@ -52,7 +128,10 @@ func (wf *workflows) forwardNewPriceToForwardTestWorkflow(
 
 	// Read forwardtest from database to get callbacks
 	ft, err := wf.readForwardtestFromDB(ctx, params.RequesterID)
-	if err != nil {
+	if db.IsRecordNotFound(err) {
+		// Forwardtest has been deleted - unsubscribe from ticks and return
+		return wf.handleFinishedForwardtest(ctx, params)
+	} else if err != nil {
 		return fmt.Errorf("could not read forwardtest from db: %w", err)
 	}
 
@@ -61,24 +140,96 @@ func (wf *workflows) forwardNewPriceToForwardTestWorkflow(
 		return wf.handleFinishedForwardtest(ctx, params)
 	}
 
-	// Execute the OnNewPricesCallback workflow
-	return wf.executeOnNewPricesCallback(ctx, params, ft)
+	// Ignore the tick if the forwardtest is not running yet, but keep its
+	// subscription for when it will be
+	if ft.Status != forwardtest.StatusRunning {
+		return nil
+	}
+
+	_, err = wf.deliverTick(ctx, ft, params.Tick)
+	return err
+}
+
+// deliverTick delivers a tick to a running forwardtest: the tick is filtered,
+// saved on its subscription, recorded if needed and then forwarded to the bot,
+// unless its subscription delivers closed candlesticks. The subscription of
+// the tick is returned, nil if there is none or if the tick is rejected.
+func (wf *workflows) deliverTick(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	t tick.Tick,
+) (*forwardtest.Subscription, error) {
+	// Quarantine the tick if it is rejected by the tick filter
+	if accepted, err := wf.acceptTick(ctx, ft, t); err != nil || !accepted {
+		return nil, err
+	}
+
+	// Update the last tick of the subscription
+	var updateRes db.UpdateSubscriptionLastTickActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.UpdateSubscriptionLastTickActivity, db.UpdateSubscriptionLastTickActivityParams{
+			ForwardtestID: ft.ID,
+			Exchange:      t.Exchange,
+			Pair:          t.Pair,
+			Time:          t.Time,
+			Price:         t.Price,
+		}).Get(ctx, &updateRes)
+	if err != nil {
+		return nil, fmt.Errorf("updating subscription last tick: %w", err)
+	}
+
+	// Only deliver the ticks of the pairs subscribed by the bot: on live
+	// forwardtests, a tick without subscription comes from a registration
+	// left on the ticks service, which is removed
+	sub := updateRes.Subscription
+	if sub == nil {
+		if !ft.IsReplay() {
+			return nil, wf.removeOrphanedRegistration(ctx, ft.ID, t)
+		}
+		return nil, nil
+	}
+
+	// Record the tick to be able to replay it
+	if ft.RecordTicks {
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+			wf.db.RecordTickActivity, db.RecordTickActivityParams{
+				ForwardtestID: ft.ID,
+				Tick:          t,
+			}).Get(ctx, nil)
+		if err != nil {
+			return nil, fmt.Errorf("recording tick: %w", err)
+		}
+	}
+
+	// Closed candlesticks are delivered on their own, not on each tick
+	if sub.DeliversCandlesticks() {
+		return sub, nil
+	}
+
+	// Execute the OnNewPricesCallback workflow right away if there is no
+	// delivery policy, otherwise let the dispatcher gather the ticks
+	if ft.Delivery.IsImmediate() {
+		return sub, wf.executeOnNewPricesCallback(ctx, &ft, tickDeliveryID(t), []tick.Tick{t})
+	}
+	return sub, wf.dispatchTick(ctx, ft.ID, t)
 }
 
-// handleFinishedForwardtest handles the case when a forwardtest is finished.
+// handleFinishedForwardtest handles the case when a forwardtest is finished or deleted.
 func (wf *workflows) handleFinishedForwardtest(
 	ctx workflow.Context,
 	params ticksapi.ListenToTicksCallbackWorkflowParams,
 ) error {
 	logger := workflow.GetLogger(ctx)
-	logger.Debug("Forwardtest is finished, unsubscribing from ticks",
+	logger.Debug("Forwardtest is finished or deleted, unsubscribing from ticks",
 		"forwardtest_id", params.RequesterID)
 
 	// Unsubscribe from ticks using exchange and pair from the tick
-	_, err := wf.ticks.StopListeningToTicks(ctx, ticksapi.UnregisterFromTicksListeningWorkflowParams{
-		RequesterID: params.RequesterID,
-		Exchange:    params.Tick.Exchange,
-		Pair:        params.Tick.Pair,
+	err := wf.unsubscribe(ctx, forwardtest.Subscription{
+		ForwardtestID: params.RequesterID,
+		Exchange:      params.Tick.Exchange,
+		Pair:          params.Tick.Pair,
 	})
 	if err != nil {
 		logger.Error("Failed to unsubscribe from ticks", "error", err)
@@ -88,17 +239,51 @@ func (wf *workflows) handleFinishedForwardtest(
 	return nil
 }
 
-// executeOnNewPricesCallback executes the OnNewPricesCallback workflow.
+// removeOrphanedRegistration unregisters from the ticks service a forwardtest
+// that receives ticks for a pair it has no subscription on. As the ticks
+// service doesn't list its listeners, this is where such registrations are
+// reconciled.
+func (wf *workflows) removeOrphanedRegistration(ctx workflow.Context, forwardtestID uuid.UUID, t tick.Tick) error {
+	logger := workflow.GetLogger(ctx)
+	logger.Warn("Received tick without subscription, unregistering from ticks",
+		"forwardtest_id", forwardtestID.String(),
+		"exchange", t.Exchange,
+		"pair", t.Pair)
+
+	if err := wf.unsubscribe(ctx, forwardtest.Subscription{
+		ForwardtestID: forwardtestID,
+		Exchange:      t.Exchange,
+		Pair:          t.Pair,
+	}); err != nil {
+		logger.Error("Failed to unregister from ticks", "error", err)
+		// Don't return error here as the next tick will try again
+	}
+
+	return nil
+}
+
+// tickDeliveryID returns the ID of the delivery of a single tick, so that the
+// same tick delivered twice executes the callback only once.
+func tickDeliveryID(t tick.Tick) string {
+	return fmt.Sprintf("%s-%s-%s", t.Exchange, t.Pair, t.Time.Format(time.RFC3339Nano))
+}
+
+// executeOnNewPricesCallback executes the OnNewPricesCallback workflow with
+// the given ticks, sorted by time. The delivery ID identifies the delivery
+// among the ones of the forwardtest. Its failures are handled with the
+// failure policy of the forwardtest.
 func (wf *workflows) executeOnNewPricesCallback(
 	ctx workflow.Context,
-	params ticksapi.ListenToTicksCallbackWorkflowParams,
-	ft forwardtest.Forwardtest,
+	ft *forwardtest.Forwardtest,
+	deliveryID string,
+	ticks []tick.Tick,
 ) error {
+	last := ticks[len(ticks)-1]
+
 	// Create child workflow options
 	opts := workflow.ChildWorkflowOptions{
 		// Unique identifier for this child workflow execution
-		WorkflowID: fmt.Sprintf("forwardtest-%s-on-new-prices-%s",
-			params.RequesterID.String(), params.Tick.Time.Format(time.RFC3339Nano)),
+		WorkflowID: fmt.Sprintf("forwardtest-%s-on-new-prices-%s", ft.ID.String(), deliveryID),
 		// Task queue where the child workflow will be executed
 		TaskQueue: ft.Callbacks.OnNewPricesCallback.TaskQueueName,
 		// Maximum time allowed for the child workflow to complete
@@ -117,24 +302,37 @@ func (wf *workflows) executeOnNewPricesCallback(
 	}
 
 	// Execute the OnNewPricesCallback workflow
+	opts = withCallbackRetries(opts, *ft, forwardtest.CallbackKindOnNewPrices)
+	var res api.OnNewPricesCallbackWorkflowResults
 	err := workflow.ExecuteChildWorkflow(
 		workflow.WithChildOptions(ctx, opts),
 		ft.Callbacks.OnNewPricesCallback.Name,
 		runtime.OnNewPricesCallbackWorkflowParams{
 			Context: runtime.Context{
-				ID:              params.RequesterID,
+				ID:              ft.ID,
 				Mode:            runtime.ModeForwardtest,
-				Now:             params.Tick.Time,
+				Now:             last.Time,
 				ParentTaskQueue: workflow.GetInfo(ctx).TaskQueueName,
 			},
-			Ticks: []tick.Tick{params.Tick},
-		}).Get(ctx, nil)
+			Ticks: ticks,
+		}).Get(ctx, &res)
 	if err != nil {
+		wf.callbackFailed(ctx, ft, forwardtest.CallbackKindOnNewPrices, err)
 		return fmt.Errorf("could not execute OnNewPricesCallback workflow: %w", err)
 	}
+	wf.callbackSucceeded(ctx, ft)
+	wf.recordEvent(ctx, forwardtest.NewTicksProcessedEvent(ft.ID, ticks))
+
+	// Execute the orders returned by the callback, if any
+	if len(res.Orders) > 0 {
+		if err := wf.applyCallbackOrders(ctx, ft.ID, ticks, res.Orders); err != nil {
+			return err
+		}
+	}
 
 	logger := workflow.GetLogger(ctx)
 	logger.Debug("Successfully forwarded price update to forwardtest callback",
-		"forwardtest_id", params.RequesterID)
+		"forwardtest_id", ft.ID.String(),
+		"ticks", len(ticks))
 	return nil
 }
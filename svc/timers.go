@@ -0,0 +1,311 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// timerWorkflowName is the name of the TimerWorkflow.
+	timerWorkflowName = "TimerWorkflow"
+	// timerFiresBeforeContinueAsNew is the number of executions of an
+	// interval timer callback before continuing as new.
+	timerFiresBeforeContinueAsNew = 200
+)
+
+// timerWorkflowParams is the input of the TimerWorkflow.
+type timerWorkflowParams struct {
+	Timer forwardtest.Timer
+}
+
+// timerWorkflowID returns the workflow ID of a forwardtest timer.
+func timerWorkflowID(forwardtestID uuid.UUID, name string) string {
+	return fmt.Sprintf("forwardtest-%s-timer-%s", forwardtestID.String(), name)
+}
+
+// RegisterForwardtestTimerWorkflow saves a recurring timer on a forwardtest
+// and starts it.
+func (wf *workflows) RegisterForwardtestTimerWorkflow(
+	ctx workflow.Context,
+	params api.RegisterForwardtestTimerWorkflowParams,
+) (api.RegisterForwardtestTimerWorkflowResults, error) {
+	timer := forwardtest.Timer{
+		ForwardtestID: params.ForwardtestID,
+		Name:          params.Name,
+		Interval:      params.Interval,
+		Cron:          params.Cron,
+		Callback:      params.Callback,
+		CreatedAt:     workflow.Now(ctx),
+	}
+	if err := timer.Validate(); err != nil {
+		return api.RegisterForwardtestTimerWorkflowResults{}, err
+	}
+
+	// Check that the forwardtest exists
+	if _, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID); err != nil {
+		return api.RegisterForwardtestTimerWorkflowResults{},
+			fmt.Errorf("could not read forwardtest from db: %w", err)
+	}
+
+	// Save timer to database
+	var res db.CreateTimerActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.CreateTimerActivity, db.CreateTimerActivityParams{
+			Timer: timer,
+		}).Get(ctx, &res)
+	if err != nil {
+		return api.RegisterForwardtestTimerWorkflowResults{}, fmt.Errorf("saving timer to db: %w", err)
+	} else if !res.Created {
+		return api.RegisterForwardtestTimerWorkflowResults{},
+			fmt.Errorf("registering timer %q: %w", timer.Name, forwardtest.ErrTimerAlreadyExists)
+	}
+
+	// Start the timer, and forget it if it can't be started
+	if err := wf.startTimer(ctx, timer); err != nil {
+		wf.deleteTimer(ctx, timer.ForwardtestID, timer.Name)
+		return api.RegisterForwardtestTimerWorkflowResults{}, err
+	}
+
+	return api.RegisterForwardtestTimerWorkflowResults{}, nil
+}
+
+// UnregisterForwardtestTimerWorkflow cancels a timer of a forwardtest and
+// removes it from the database.
+func (wf *workflows) UnregisterForwardtestTimerWorkflow(
+	ctx workflow.Context,
+	params api.UnregisterForwardtestTimerWorkflowParams,
+) (api.UnregisterForwardtestTimerWorkflowResults, error) {
+	wf.stopTimer(ctx, params.ForwardtestID, params.Name)
+
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.DeleteTimerActivity, db.DeleteTimerActivityParams{
+			ForwardtestID: params.ForwardtestID,
+			Name:          params.Name,
+		}).Get(ctx, nil)
+	if err != nil {
+		return api.UnregisterForwardtestTimerWorkflowResults{}, fmt.Errorf("deleting timer from db: %w", err)
+	}
+
+	return api.UnregisterForwardtestTimerWorkflowResults{}, nil
+}
+
+// ListForwardtestTimersWorkflow lists the timers of a forwardtest.
+func (wf *workflows) ListForwardtestTimersWorkflow(
+	ctx workflow.Context,
+	params api.ListForwardtestTimersWorkflowParams,
+) (api.ListForwardtestTimersWorkflowResults, error) {
+	timers, err := wf.listTimers(ctx, params.ForwardtestID)
+	if err != nil {
+		return api.ListForwardtestTimersWorkflowResults{}, err
+	}
+
+	return api.ListForwardtestTimersWorkflowResults{
+		Timers: timers,
+	}, nil
+}
+
+// timerWorkflow is a private workflow that executes the callback of a timer.
+// Cron timers are scheduled by Temporal and execute the callback once per
+// run, while interval timers loop until they are cancelled.
+func (wf *workflows) timerWorkflow(
+	ctx workflow.Context,
+	params timerWorkflowParams,
+) error {
+	timer := params.Timer
+	if timer.IsCron() {
+		wf.fireTimer(ctx, timer)
+		return nil
+	}
+
+	for i := 0; i < timerFiresBeforeContinueAsNew; i++ {
+		if err := workflow.Sleep(ctx, timer.Interval); err != nil {
+			return err
+		}
+
+		wf.fireTimer(ctx, timer)
+
+		if workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			break
+		}
+	}
+
+	// Continue as new to keep the history small
+	return workflow.NewContinueAsNewError(ctx, timerWorkflowName, params)
+}
+
+// fireTimer executes the callback of the timer if the forwardtest is running.
+// Failures are handled with the failure policy of the forwardtest and do not
+// prevent the next executions.
+func (wf *workflows) fireTimer(ctx workflow.Context, timer forwardtest.Timer) {
+	logger := workflow.GetLogger(ctx)
+
+	// Get the forwardtest for its status and failure policy
+	ft, err := wf.readForwardtestFromDB(ctx, timer.ForwardtestID)
+	if err != nil {
+		logger.Error("Could not read forwardtest of timer from db",
+			"forwardtest_id", timer.ForwardtestID.String(),
+			"timer", timer.Name,
+			"error", err.Error())
+		return
+	} else if ft.Status != forwardtest.StatusRunning {
+		logger.Debug("Skipping timer of a forwardtest that is not running",
+			"forwardtest_id", timer.ForwardtestID.String(),
+			"timer", timer.Name,
+			"status", ft.Status.String())
+		return
+	}
+
+	now := workflow.Now(ctx)
+	opts := workflow.ChildWorkflowOptions{
+		// Unique identifier for this child workflow execution
+		WorkflowID: fmt.Sprintf("%s-%s",
+			timerWorkflowID(timer.ForwardtestID, timer.Name), now.Format(time.RFC3339Nano)),
+		// Task queue where the child workflow will be executed
+		TaskQueue: timer.Callback.TaskQueueName,
+		// Maximum time allowed for the child workflow to complete
+		WorkflowExecutionTimeout: time.Second * 30,
+	}
+
+	// Check if the timeout is set
+	if timer.Callback.ExecutionTimeout > 0 {
+		opts.WorkflowExecutionTimeout = timer.Callback.ExecutionTimeout
+	}
+
+	opts = withCallbackRetries(opts, ft, forwardtest.CallbackKindOnTimer)
+	err = workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		timer.Callback.Name,
+		api.OnTimerCallbackWorkflowParams{
+			Context: runtime.Context{
+				ID:              timer.ForwardtestID,
+				Mode:            runtime.ModeForwardtest,
+				Now:             now,
+				ParentTaskQueue: workflow.GetInfo(ctx).TaskQueueName,
+			},
+			Name: timer.Name,
+		}).Get(ctx, nil)
+	if err != nil {
+		logger.Error("Timer callback failed",
+			"forwardtest_id", timer.ForwardtestID.String(),
+			"timer", timer.Name,
+			"error", err.Error())
+		wf.callbackFailed(ctx, &ft, forwardtest.CallbackKindOnTimer, err)
+		return
+	}
+	wf.callbackSucceeded(ctx, &ft)
+}
+
+// startTimer starts the workflow of a timer. It is not an error if the timer
+// has already been started.
+func (wf *workflows) startTimer(ctx workflow.Context, timer forwardtest.Timer) error {
+	opts := workflow.ChildWorkflowOptions{
+		// Only one workflow per timer
+		WorkflowID: timerWorkflowID(timer.ForwardtestID, timer.Name),
+		// The timer outlives the workflow that starts it
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+		// A new timer can be started after the previous one has been cancelled
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+		// Cron timers are scheduled by Temporal
+		CronSchedule: timer.Cron,
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		timerWorkflowName,
+		timerWorkflowParams{
+			Timer: timer,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		return fmt.Errorf("starting timer %q: %w", timer.Name, err)
+	}
+
+	return nil
+}
+
+// stopTimer cancels the workflow of a timer, if any.
+func (wf *workflows) stopTimer(ctx workflow.Context, forwardtestID uuid.UUID, name string) {
+	err := workflow.RequestCancelExternalWorkflow(ctx, timerWorkflowID(forwardtestID, name), "").Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Debug("No timer to cancel",
+			"forwardtest_id", forwardtestID.String(),
+			"timer", name,
+			"error", err.Error())
+	}
+}
+
+// deleteTimer removes a timer from the database, logging failures.
+func (wf *workflows) deleteTimer(ctx workflow.Context, forwardtestID uuid.UUID, name string) {
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.DeleteTimerActivity, db.DeleteTimerActivityParams{
+			ForwardtestID: forwardtestID,
+			Name:          name,
+		}).Get(ctx, nil)
+	if err != nil {
+		workflow.GetLogger(ctx).Error("Could not delete timer from db",
+			"forwardtest_id", forwardtestID.String(),
+			"timer", name,
+			"error", err.Error())
+	}
+}
+
+// listTimers lists the timers of a forwardtest from the database.
+func (wf *workflows) listTimers(ctx workflow.Context, forwardtestID uuid.UUID) ([]forwardtest.Timer, error) {
+	var res db.ListTimersActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListTimersActivity, db.ListTimersActivityParams{
+			ForwardtestID: forwardtestID,
+		}).Get(ctx, &res)
+	if err != nil {
+		return nil, fmt.Errorf("listing timers: %w", err)
+	}
+
+	return res.Timers, nil
+}
+
+// stopAllTimers cancels every timer of the forwardtest and removes them from
+// the database.
+func (wf *workflows) stopAllTimers(ctx workflow.Context, forwardtestID uuid.UUID) error {
+	timers, err := wf.listTimers(ctx, forwardtestID)
+	if err != nil {
+		return err
+	}
+
+	for _, timer := range timers {
+		wf.stopTimer(ctx, forwardtestID, timer.Name)
+		wf.deleteTimer(ctx, forwardtestID, timer.Name)
+	}
+
+	return nil
+}
+
+// restartTimers starts again the timers of a forwardtest that are not running.
+func (wf *workflows) restartTimers(ctx workflow.Context, forwardtestID uuid.UUID) error {
+	timers, err := wf.listTimers(ctx, forwardtestID)
+	if err != nil {
+		return err
+	}
+
+	for _, timer := range timers {
+		if err := wf.startTimer(ctx, timer); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to restart timer",
+				"forwardtest_id", forwardtestID.String(),
+				"timer", timer.Name,
+				"error", err.Error())
+		}
+	}
+
+	return nil
+}
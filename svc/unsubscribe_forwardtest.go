@@ -0,0 +1,136 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	ticksapi "github.com/cryptellation/ticks/api"
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/workflow"
+)
+
+// UnsubscribeFromPriceWorkflow unsubscribes a forwardtest from price updates
+// of a pair, without stopping the forwardtest.
+func (wf *workflows) UnsubscribeFromPriceWorkflow(
+	ctx workflow.Context,
+	params api.UnsubscribeFromPriceWorkflowParams,
+) (api.UnsubscribeFromPriceWorkflowResults, error) {
+	// Get the subscription to know how its prices are delivered
+	var res db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: params.ForwardtestID,
+		}).Get(ctx, &res)
+	if err != nil {
+		return api.UnsubscribeFromPriceWorkflowResults{}, fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	sub := forwardtest.Subscription{
+		ForwardtestID: params.ForwardtestID,
+		Exchange:      params.Exchange,
+		Pair:          params.Pair,
+	}
+	for _, s := range res.Subscriptions {
+		if s.Exchange == params.Exchange && s.Pair == params.Pair {
+			sub = s
+			break
+		}
+	}
+
+	if err := wf.unsubscribe(ctx, sub); err != nil {
+		return api.UnsubscribeFromPriceWorkflowResults{}, err
+	}
+
+	return api.UnsubscribeFromPriceWorkflowResults{}, nil
+}
+
+// unsubscribe stops the delivery of the prices of the subscription, either
+// by stopping the delivery of candlesticks or by unregistering from the ticks
+// service, and removes the subscription from the database.
+func (wf *workflows) unsubscribe(ctx workflow.Context, sub forwardtest.Subscription) error {
+	if sub.DeliversCandlesticks() {
+		// Stop delivering candlesticks
+		wf.stopCandlesticksDelivery(ctx, sub.ForwardtestID, sub.Exchange, sub.Pair)
+	} else {
+		// Unregister from ticks
+		_, err := wf.ticks.StopListeningToTicks(ctx, ticksapi.UnregisterFromTicksListeningWorkflowParams{
+			RequesterID: sub.ForwardtestID,
+			Exchange:    sub.Exchange,
+			Pair:        sub.Pair,
+		})
+		if err != nil {
+			return fmt.Errorf("unregistering from ticks: %w", err)
+		}
+	}
+
+	// Delete subscription from database
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.DeleteSubscriptionActivity, db.DeleteSubscriptionActivityParams{
+			ForwardtestID: sub.ForwardtestID,
+			Exchange:      sub.Exchange,
+			Pair:          sub.Pair,
+		}).Get(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("deleting subscription from db: %w", err)
+	}
+
+	return nil
+}
+
+// unsubscribeAll removes every persisted subscription of the forwardtest. It
+// is best-effort: failures are logged and the subscriptions left behind are
+// removed when their next ticks reach a finished or deleted forwardtest.
+func (wf *workflows) unsubscribeAll(ctx workflow.Context, forwardtestID uuid.UUID) {
+	logger := workflow.GetLogger(ctx)
+
+	// List forwardtest subscriptions
+	var res db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: forwardtestID,
+		}).Get(ctx, &res)
+	if err != nil {
+		logger.Error("Failed to list subscriptions to remove",
+			"forwardtest_id", forwardtestID.String(),
+			"error", err.Error())
+		return
+	}
+
+	// Unsubscribe from each of them
+	for _, sub := range res.Subscriptions {
+		if err := wf.unsubscribe(ctx, sub); err != nil {
+			logger.Error("Failed to unsubscribe",
+				"forwardtest_id", forwardtestID.String(),
+				"exchange", sub.Exchange,
+				"pair", sub.Pair,
+				"error", err.Error())
+		}
+	}
+}
+
+// teardown removes every subscription and timer of the forwardtest and stops
+// the delivery of gathered ticks and the watch of the price feeds. It is
+// best-effort and must be called once the forwardtest is not running anymore,
+// so that what is left behind is ignored and cleaned up later.
+func (wf *workflows) teardown(ctx workflow.Context, forwardtestID uuid.UUID) {
+	// Unsubscribe from all prices
+	wf.unsubscribeAll(ctx, forwardtestID)
+
+	// Stop delivering gathered ticks
+	wf.stopDispatcher(ctx, forwardtestID)
+
+	// Stop watching the price feeds
+	wf.stopFeedHealthWatch(ctx, forwardtestID)
+
+	// Cancel the timers
+	if err := wf.stopAllTimers(ctx, forwardtestID); err != nil {
+		workflow.GetLogger(ctx).Error("Failed to stop timers",
+			"forwardtest_id", forwardtestID.String(),
+			"error", err.Error())
+	}
+}
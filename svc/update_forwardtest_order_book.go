@@ -0,0 +1,71 @@
+package svc
+
+import (
+	"fmt"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/cryptellation/runtime/order"
+	"go.temporal.io/sdk/workflow"
+)
+
+// UpdateForwardtestOrderBookWorkflow saves an order book snapshot on a
+// subscription of a forwardtest, to fill orders with bid/ask execution.
+func (wf *workflows) UpdateForwardtestOrderBookWorkflow(
+	ctx workflow.Context,
+	params api.UpdateForwardtestOrderBookWorkflowParams,
+) (api.UpdateForwardtestOrderBookWorkflowResults, error) {
+	if err := params.OrderBook.Validate(); err != nil {
+		return api.UpdateForwardtestOrderBookWorkflowResults{}, err
+	}
+
+	// Save the snapshot on the subscription
+	var res db.UpdateSubscriptionOrderBookActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.UpdateSubscriptionOrderBookActivity, db.UpdateSubscriptionOrderBookActivityParams{
+			ForwardtestID: params.ForwardtestID,
+			Exchange:      params.Exchange,
+			Pair:          params.Pair,
+			OrderBook:     params.OrderBook,
+		}).Get(ctx, &res)
+	if err != nil {
+		return api.UpdateForwardtestOrderBookWorkflowResults{},
+			fmt.Errorf("updating subscription order book in db: %w", err)
+	}
+
+	return api.UpdateForwardtestOrderBookWorkflowResults{
+		Updated: res.Updated,
+	}, nil
+}
+
+// getOrderBook gets the last order book snapshot of the order pair, if the
+// forwardtest fills orders from the order book and a snapshot is available.
+func (wf *workflows) getOrderBook(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	o order.Order,
+) (*forwardtest.OrderBook, error) {
+	if !ft.Execution.UsesOrderBook() {
+		return nil, nil
+	}
+
+	var res db.ListSubscriptionsActivityResult
+	err := workflow.ExecuteActivity(
+		workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+		wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+			ForwardtestID: ft.ID,
+		}).Get(ctx, &res)
+	if err != nil {
+		return nil, fmt.Errorf("listing subscriptions: %w", err)
+	}
+
+	for _, sub := range res.Subscriptions {
+		if sub.Exchange == o.Exchange && sub.Pair == o.Pair {
+			return sub.OrderBook, nil
+		}
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,155 @@
+package svc
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/forwardtests/svc/db"
+	"github.com/google/uuid"
+	"go.temporal.io/api/enums/v1"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	// watchFeedHealthWorkflowName is the name of the WatchFeedHealthWorkflow.
+	watchFeedHealthWorkflowName = "WatchFeedHealthWorkflow"
+	// feedChecksBeforeContinueAsNew is the number of checks executed by the
+	// feed health watchdog before continuing as new.
+	feedChecksBeforeContinueAsNew = 200
+)
+
+// watchFeedHealthWorkflowParams is the input of the WatchFeedHealthWorkflow.
+type watchFeedHealthWorkflowParams struct {
+	ForwardtestID uuid.UUID
+}
+
+// feedHealthWorkflowID returns the workflow ID of the feed health watchdog of
+// a forwardtest.
+func feedHealthWorkflowID(forwardtestID uuid.UUID) string {
+	return fmt.Sprintf("forwardtest-%s-feed-health", forwardtestID.String())
+}
+
+// watchFeedHealthWorkflow is a private workflow that marks the price feeds
+// of a forwardtest as stale as soon as they stay silent past the stale
+// duration, by waking up when the next subscription would become stale. It
+// ends when the forwardtest is finished, deleted or has no subscription.
+func (wf *workflows) watchFeedHealthWorkflow(
+	ctx workflow.Context,
+	params watchFeedHealthWorkflowParams,
+) error {
+	for i := 0; ; i++ {
+		ft, err := wf.readForwardtestFromDB(ctx, params.ForwardtestID)
+		if db.IsRecordNotFound(err) {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("could not read forwardtest from db: %w", err)
+		} else if ft.Status == forwardtest.StatusFinished || ft.FeedHealth.StaleAfter == 0 {
+			return nil
+		}
+
+		// List forwardtest subscriptions
+		var res db.ListSubscriptionsActivityResult
+		err = workflow.ExecuteActivity(
+			workflow.WithActivityOptions(ctx, db.DefaultActivityOptions()),
+			wf.db.ListSubscriptionsActivity, db.ListSubscriptionsActivityParams{
+				ForwardtestID: ft.ID,
+			}).Get(ctx, &res)
+		if err != nil {
+			return fmt.Errorf("listing subscriptions: %w", err)
+		} else if len(res.Subscriptions) == 0 {
+			return nil
+		}
+
+		// Wait for the next subscription to become stale
+		next := wf.checkFeedHealth(ctx, ft, res.Subscriptions)
+		if err := workflow.Sleep(ctx, next.Sub(workflow.Now(ctx))); err != nil {
+			return err
+		}
+
+		// Continue as new to keep the history small
+		if i+1 >= feedChecksBeforeContinueAsNew || workflow.GetInfo(ctx).GetContinueAsNewSuggested() {
+			return workflow.NewContinueAsNewError(ctx, watchFeedHealthWorkflowName, params)
+		}
+	}
+}
+
+// checkFeedHealth marks the stale price feeds of a running forwardtest and
+// returns the time of the next check.
+func (wf *workflows) checkFeedHealth(
+	ctx workflow.Context,
+	ft forwardtest.Forwardtest,
+	subs []forwardtest.Subscription,
+) time.Time {
+	now := workflow.Now(ctx)
+	next := now.Add(ft.FeedHealth.StaleAfter)
+	if ft.Status != forwardtest.StatusRunning {
+		return next
+	}
+
+	for _, sub := range subs {
+		// Marked feeds are checked again once they receive ticks
+		if sub.StaleSince != nil {
+			continue
+		}
+
+		if !ft.FeedHealth.IsStale(sub, now) {
+			if staleAt := sub.StalledAt(ft.FeedHealth.StaleAfter); staleAt.Before(next) {
+				next = staleAt
+			}
+			continue
+		}
+
+		if err := wf.handleStaleFeed(ctx, ft, sub); err != nil {
+			workflow.GetLogger(ctx).Error("Failed to handle stale price feed",
+				"forwardtest_id", ft.ID.String(),
+				"exchange", sub.Exchange,
+				"pair", sub.Pair,
+				"error", err.Error())
+		}
+	}
+
+	// Wake up just after the deadline, as the feed is stale once it is passed
+	return next.Add(time.Millisecond)
+}
+
+// startFeedHealthWatch starts the feed health watchdog of the forwardtest, if
+// it detects stale feeds. It is not an error if it is already running.
+func (wf *workflows) startFeedHealthWatch(ctx workflow.Context, ft forwardtest.Forwardtest) error {
+	if ft.FeedHealth.StaleAfter == 0 || ft.IsReplay() {
+		return nil
+	}
+
+	opts := workflow.ChildWorkflowOptions{
+		// Only one watchdog per forwardtest
+		WorkflowID: feedHealthWorkflowID(ft.ID),
+		// The watchdog outlives the workflow that starts it
+		ParentClosePolicy: enums.PARENT_CLOSE_POLICY_ABANDON,
+		// A new watchdog can be started after the previous one has ended
+		WorkflowIDReusePolicy: enums.WORKFLOW_ID_REUSE_POLICY_ALLOW_DUPLICATE,
+	}
+
+	err := workflow.ExecuteChildWorkflow(
+		workflow.WithChildOptions(ctx, opts),
+		watchFeedHealthWorkflowName,
+		watchFeedHealthWorkflowParams{
+			ForwardtestID: ft.ID,
+		}).GetChildWorkflowExecution().Get(ctx, nil)
+	if err != nil && !temporal.IsWorkflowExecutionAlreadyStartedError(err) {
+		return fmt.Errorf("starting feed health watch: %w", err)
+	}
+
+	return nil
+}
+
+// stopFeedHealthWatch cancels the feed health watchdog of the forwardtest, if any.
+func (wf *workflows) stopFeedHealthWatch(ctx workflow.Context, forwardtestID uuid.UUID) {
+	err := workflow.RequestCancelExternalWorkflow(ctx, feedHealthWorkflowID(forwardtestID), "").Get(ctx, nil)
+	if err != nil {
+		// The watchdog is only started for forwardtests detecting stale feeds
+		workflow.GetLogger(ctx).Debug("No feed health watch to cancel",
+			"forwardtest_id", forwardtestID.String(),
+			"error", err.Error())
+	}
+}
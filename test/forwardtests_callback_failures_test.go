@@ -0,0 +1,138 @@
+//go:build e2e
+// +build e2e
+
+package test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/clients"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/account"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const (
+	failingRunnerOnTimerName = "ForwardtestE2eFailingRunner-OnTimer"
+	failingRunnerOnErrorName = "ForwardtestE2eFailingRunner-OnError"
+)
+
+type failingRunner struct {
+	testRunner
+
+	TaskQueue string
+
+	mu     sync.Mutex
+	errors []api.OnErrorCallbackWorkflowParams
+}
+
+func (r *failingRunner) Name() string {
+	return "ForwardtestE2eFailingRunner"
+}
+
+func (r *failingRunner) OnInit(ctx workflow.Context, params runtime.OnInitCallbackWorkflowParams) error {
+	checkForwardtestRunContext(r.Suite, params.Context, r.ForwardtestID)
+
+	// Register a timer with the failing callback
+	_, err := r.WfClient.RegisterForwardtestTimer(ctx, api.RegisterForwardtestTimerWorkflowParams{
+		ForwardtestID: r.ForwardtestID,
+		Name:          "failing",
+		Interval:      time.Second,
+		Callback: runtime.CallbackWorkflow{
+			Name:          failingRunnerOnTimerName,
+			TaskQueueName: r.TaskQueue,
+		},
+	})
+	r.Suite.Require().NoError(err)
+
+	r.OnInitCalls++
+	return err
+}
+
+func (r *failingRunner) OnTimer(_ workflow.Context, _ api.OnTimerCallbackWorkflowParams) error {
+	return temporal.NewNonRetryableApplicationError("timer failure", "test", errors.New("timer failure"))
+}
+
+func (r *failingRunner) OnError(_ workflow.Context, params api.OnErrorCallbackWorkflowParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.errors = append(r.errors, params)
+	return nil
+}
+
+func (r *failingRunner) Errors() []api.OnErrorCallbackWorkflowParams {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]api.OnErrorCallbackWorkflowParams(nil), r.errors...)
+}
+
+func (suite *EndToEndSuite) TestForwardtestStoppedOnCallbackFailures() {
+	// GIVEN a running worker
+	tq := "ForwardtestE2eFailingRunner-TaskQueue"
+	w := worker.New(suite.temporalclient, tq, worker.Options{})
+
+	// AND a runner registering a timer whose callback always fails
+
+	accounts := map[string]account.Account{
+		"binance": {Balances: map[string]float64{"USDT": 1000}},
+	}
+	r := &failingRunner{
+		testRunner: testRunner{Accounts: accounts, Suite: suite, WfClient: clients.NewWfClient()},
+		TaskQueue:  tq,
+	}
+	callbacks := runtime.RegisterRunnable(w, tq, r)
+	w.RegisterWorkflowWithOptions(r.OnTimer, workflow.RegisterOptions{Name: failingRunnerOnTimerName})
+	w.RegisterWorkflowWithOptions(r.OnError, workflow.RegisterOptions{Name: failingRunnerOnErrorName})
+	suite.Require().NoError(w.Start())
+	defer w.Stop()
+
+	// WHEN creating and running a forwardtest stopping after 3 failures
+
+	ft, err := suite.client.NewForwardtest(context.Background(), api.CreateForwardtestWorkflowParams{
+		Accounts:  accounts,
+		Callbacks: callbacks,
+		OptionalCallbacks: forwardtest.OptionalCallbacks{
+			OnErrorCallback: &runtime.CallbackWorkflow{Name: failingRunnerOnErrorName, TaskQueueName: tq},
+		},
+		Failures: forwardtest.FailurePolicy{MaxConsecutiveFailures: 3},
+	})
+	suite.Require().NoError(err)
+	r.ForwardtestID = ft.ID
+	suite.Require().NoError(ft.Run(context.Background()))
+
+	// THEN the forwardtest is stopped because of the callback failures
+
+	suite.Require().Eventually(func() bool {
+		got, err := ft.Get(context.Background())
+		return err == nil && got.Status == forwardtest.StatusFinished
+	}, time.Minute, 100*time.Millisecond)
+
+	got, err := ft.Get(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(forwardtest.StatusReasonCallbackFailures, got.StatusReason)
+
+	// AND the failures are in the diagnostics
+
+	diag, err := ft.GetDiagnostics(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().GreaterOrEqual(len(diag.CallbackErrors), 3)
+	suite.Require().Equal(forwardtest.CallbackKindOnTimer, diag.CallbackErrors[0].Callback)
+
+	// AND the error callback has been notified of the stop
+
+	suite.Require().Eventually(func() bool {
+		for _, e := range r.Errors() {
+			if e.Stopping && e.ConsecutiveFailures == 3 {
+				return true
+			}
+		}
+		return false
+	}, 30*time.Second, 100*time.Millisecond)
+}
@@ -0,0 +1,126 @@
+//go:build e2e
+// +build e2e
+
+package test
+
+import (
+	"context"
+	"time"
+
+	"github.com/cryptellation/candlesticks/pkg/period"
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/clients"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/account"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const candlesticksRunnerCallbackName = "ForwardtestE2eCandlesticksRunner-OnNewCandlestick"
+
+type candlesticksRunner struct {
+	testRunner
+
+	TaskQueue           string
+	OnNewCandlesticks   int
+	LastCandlestickTime time.Time
+}
+
+func (r *candlesticksRunner) Name() string {
+	return "ForwardtestE2eCandlesticksRunner"
+}
+
+func (r *candlesticksRunner) OnInit(ctx workflow.Context, params runtime.OnInitCallbackWorkflowParams) error {
+	checkForwardtestRunContext(r.Suite, params.Context, r.ForwardtestID)
+
+	// Subscribe to closed candlesticks
+	_, err := r.WfClient.SubscribeToPrice(ctx, api.SubscribeToPriceWorkflowParams{
+		ForwardtestID: r.ForwardtestID,
+		Exchange:      "binance",
+		Pair:          "BTC-USDT",
+		Period:        period.M1.Opt(),
+		OnNewCandlestickCallback: &runtime.CallbackWorkflow{
+			Name:          candlesticksRunnerCallbackName,
+			TaskQueueName: r.TaskQueue,
+		},
+	})
+	r.Suite.Require().NoError(err)
+
+	r.OnInitCalls++
+	return err
+}
+
+func (r *candlesticksRunner) OnNewCandlestick(
+	_ workflow.Context,
+	params api.OnNewCandlestickCallbackWorkflowParams,
+) error {
+	checkForwardtestRunContext(r.Suite, params.Context, r.ForwardtestID)
+	r.Suite.Require().Equal(period.M1, params.Period)
+	r.Suite.Require().True(period.M1.IsAligned(params.Candlestick.Time))
+	r.Suite.Require().True(params.Candlestick.Time.After(r.LastCandlestickTime))
+
+	r.LastCandlestickTime = params.Candlestick.Time
+	r.OnNewCandlesticks++
+	return nil
+}
+
+func (suite *EndToEndSuite) TestForwardtestRunWithCandlesticks() {
+	// GIVEN a running worker
+	tq := "ForwardtestE2eCandlesticksRunner-TaskQueue"
+	w := worker.New(suite.temporalclient, tq, worker.Options{})
+
+	// AND a runner with a candlestick callback
+
+	accounts := map[string]account.Account{
+		"binance": {
+			Balances: map[string]float64{
+				"BTC": 1,
+			},
+		},
+	}
+	r := &candlesticksRunner{
+		testRunner: testRunner{
+			Accounts: accounts,
+			Suite:    suite,
+			WfClient: clients.NewWfClient(),
+		},
+		TaskQueue: tq,
+	}
+	callbacks := runtime.RegisterRunnable(w, tq, r)
+	w.RegisterWorkflowWithOptions(r.OnNewCandlestick, workflow.RegisterOptions{
+		Name: candlesticksRunnerCallbackName,
+	})
+
+	go func() {
+		if err := w.Run(nil); err != nil {
+			suite.Require().NoError(err)
+		}
+	}()
+	defer w.Stop()
+
+	// WHEN creating and running a new forwardtest
+
+	ft, err := suite.client.NewForwardtest(context.Background(), api.CreateForwardtestWorkflowParams{
+		Accounts:  accounts,
+		Callbacks: callbacks,
+	})
+	suite.Require().NoError(err)
+	r.ForwardtestID = ft.ID
+	suite.Require().NoError(ft.Run(context.Background()))
+
+	// THEN the candlestick callback is called with closed candlesticks
+
+	suite.Require().Eventually(func() bool {
+		return r.OnNewCandlesticks >= 2
+	}, 10*time.Minute, time.Second)
+
+	// AND ticks are not delivered to the OnNewPrices callback
+	suite.Require().Zero(r.OnNewPricesCalls)
+
+	// WHEN stopping the forwardtest
+	err = ft.Stop(context.Background())
+
+	// THEN no error is returned
+
+	suite.Require().NoError(err)
+}
@@ -0,0 +1,113 @@
+//go:build e2e
+// +build e2e
+
+package test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/account"
+	"github.com/cryptellation/runtime/order"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const orderUpdateCallbackName = "ForwardtestE2eOrderUpdate-OnOrderUpdate"
+
+type orderUpdateRecorder struct {
+	mu      sync.Mutex
+	updates []api.OnOrderUpdateCallbackWorkflowParams
+}
+
+func (r *orderUpdateRecorder) OnOrderUpdate(_ workflow.Context, params api.OnOrderUpdateCallbackWorkflowParams) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.updates = append(r.updates, params)
+	return nil
+}
+
+func (r *orderUpdateRecorder) Updates() []api.OnOrderUpdateCallbackWorkflowParams {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]api.OnOrderUpdateCallbackWorkflowParams(nil), r.updates...)
+}
+
+func (suite *EndToEndSuite) TestCreateOrderWithOrderUpdateCallback() {
+	// GIVEN a running worker with an order update callback
+
+	tq := "ForwardtestE2eOrderUpdate-TaskQueue"
+	w := worker.New(suite.temporalclient, tq, worker.Options{})
+	r := &orderUpdateRecorder{}
+	w.RegisterWorkflowWithOptions(r.OnOrderUpdate, workflow.RegisterOptions{
+		Name: orderUpdateCallbackName,
+	})
+	suite.Require().NoError(w.Start())
+	defer w.Stop()
+
+	// AND a forwardtest with a pair whitelist and the callback
+
+	ft, err := suite.client.NewForwardtest(context.Background(), api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {Balances: map[string]float64{"USDT": 1000000}},
+		},
+		Callbacks: createTestCallbacks(),
+		OptionalCallbacks: forwardtest.OptionalCallbacks{
+			OnOrderUpdateCallback: &runtime.CallbackWorkflow{
+				Name:          orderUpdateCallbackName,
+				TaskQueueName: tq,
+			},
+		},
+		Risk: forwardtest.RiskLimits{
+			AllowedPairs: map[string][]string{"binance": {"BTC-USDT"}},
+		},
+	})
+	suite.Require().NoError(err)
+
+	// WHEN creating an allowed order and a rejected one
+
+	_, err = ft.CreateOrder(context.Background(), order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	})
+	suite.Require().NoError(err)
+	_, err = ft.CreateOrder(context.Background(), order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "ETH-USDT",
+		Quantity: 1,
+	})
+	suite.Require().Error(err)
+
+	// THEN the callback is executed for both orders
+
+	suite.Require().Eventually(func() bool {
+		return len(r.Updates()) == 2
+	}, 30*time.Second, 100*time.Millisecond)
+
+	statuses := map[string]api.OnOrderUpdateCallbackWorkflowParams{}
+	for _, u := range r.Updates() {
+		suite.Require().Equal(ft.ID, u.Context.ID)
+		statuses[u.Order.Pair] = u
+	}
+
+	// AND the filled order carries its price and the resulting balances
+	filled := statuses["BTC-USDT"]
+	suite.Require().Equal(api.OrderUpdateStatusFilled, filled.Status)
+	suite.Require().NotZero(filled.Order.Price)
+	suite.Require().NotNil(filled.Order.ExecutionTime)
+	suite.Require().Equal(1.0, filled.Accounts["binance"].Balances["BTC"])
+
+	// AND the rejected order carries the reason
+	rejected := statuses["ETH-USDT"]
+	suite.Require().Equal(api.OrderUpdateStatusRejected, rejected.Status)
+	suite.Require().NotEmpty(rejected.Reason)
+}
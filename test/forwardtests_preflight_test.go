@@ -0,0 +1,71 @@
+//go:build e2e
+// +build e2e
+
+package test
+
+import (
+	"context"
+	"errors"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/forwardtest"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/account"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+func (suite *EndToEndSuite) TestCreateForwardtestWithPreflight() {
+	// GIVEN a running worker polling a task queue
+	tq := "ForwardtestE2ePreflight-TaskQueue"
+	w := worker.New(suite.temporalclient, tq, worker.Options{})
+	w.RegisterWorkflowWithOptions(func() error { return nil }, workflow.RegisterOptions{
+		Name: "ForwardtestE2ePreflight-Noop",
+	})
+	suite.Require().NoError(w.Start())
+	defer w.Stop()
+
+	// AND callbacks on this task queue, except the exit callback
+
+	callbacks := runtime.Callbacks{
+		OnInitCallback:      runtime.CallbackWorkflow{Name: "init", TaskQueueName: tq},
+		OnNewPricesCallback: runtime.CallbackWorkflow{Name: "prices", TaskQueueName: tq},
+		OnExitCallback:      runtime.CallbackWorkflow{Name: "exit", TaskQueueName: "ForwardtestE2ePreflight-Typo"},
+	}
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {Balances: map[string]float64{"USDT": 1000}},
+		},
+		Callbacks: callbacks,
+		Preflight: true,
+	}
+
+	// WHEN creating the forwardtest with a preflight
+
+	_, err := suite.client.NewForwardtest(context.Background(), params)
+
+	// THEN an error lists the unreachable callback
+
+	var appErr *temporal.ApplicationError
+	suite.Require().True(errors.As(err, &appErr), err)
+	suite.Require().Equal(api.UnreachableCallbacksErrorType, appErr.Type())
+	suite.Require().Contains(appErr.Error(), "OnExitCallback")
+	suite.Require().NotContains(appErr.Error(), "OnInitCallback")
+
+	var names []string
+	suite.Require().NoError(appErr.Details(&names))
+	suite.Require().Equal([]string{"OnExitCallback"}, names)
+
+	// WHEN fixing the exit callback
+
+	params.Callbacks.OnExitCallback.TaskQueueName = tq
+	params.OptionalCallbacks = forwardtest.OptionalCallbacks{
+		OnErrorCallback: &runtime.CallbackWorkflow{Name: "error", TaskQueueName: tq},
+	}
+	_, err = suite.client.NewForwardtest(context.Background(), params)
+
+	// THEN the forwardtest is created
+
+	suite.Require().NoError(err)
+}
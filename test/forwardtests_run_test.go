@@ -141,6 +141,18 @@ func (suite *EndToEndSuite) TestForwardtestRun() {
 		return r.OnNewPricesCalls >= 2
 	}, 10*time.Minute, time.Millisecond*100)
 
+	// WHEN listing the forwardtest subscriptions
+	subs, err := forwardtest.ListSubscriptions(context.Background())
+
+	// THEN the subscription to the pair is listed with the received ticks
+
+	suite.Require().NoError(err)
+	suite.Require().Len(subs, 1)
+	suite.Require().Equal("binance", subs[0].Exchange)
+	suite.Require().Equal("BTC-USDT", subs[0].Pair)
+	suite.Require().GreaterOrEqual(subs[0].TickCount, int64(2))
+	suite.Require().NotNil(subs[0].LastTickAt)
+
 	// WHEN stopping the forwardtest
 	err = forwardtest.Stop(context.Background())
 
@@ -148,6 +160,11 @@ func (suite *EndToEndSuite) TestForwardtestRun() {
 
 	suite.Require().NoError(err)
 
+	// AND the subscriptions are removed
+	subs, err = forwardtest.ListSubscriptions(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Empty(subs)
+
 	// AND the we wait for the OnExit callback to be called
 	suite.Require().Eventually(func() bool {
 		return r.OnExitCalls >= 1
@@ -159,3 +176,67 @@ func checkForwardtestRunContext(suite *EndToEndSuite, ctx runtime.Context, forwa
 	suite.Require().Equal(runtime.ModeForwardtest, ctx.Mode)
 	suite.Require().NotEmpty(ctx.ParentTaskQueue)
 }
+
+func (suite *EndToEndSuite) TestForwardtestRunWithDeliveryPolicy() {
+	// GIVEN a running worker
+	tq := "ForwardtestE2eDeliveryRunner-TaskQueue"
+	w := worker.New(suite.temporalclient, tq, worker.Options{})
+	go func() {
+		if err := w.Run(nil); err != nil {
+			suite.Require().NoError(err)
+		}
+	}()
+	defer w.Stop()
+
+	// AND a runner
+
+	accounts := map[string]account.Account{
+		"binance": {
+			Balances: map[string]float64{
+				"BTC": 1,
+			},
+		},
+	}
+	r := &testRunner{
+		Accounts: accounts,
+		Suite:    suite,
+		WfClient: clients.NewWfClient(),
+	}
+
+	// WHEN creating a new forwardtest with ticks gathered every 5 seconds
+	// and coalesced while the callback is running
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts:  accounts,
+		Callbacks: runtime.RegisterRunnable(w, tq, r),
+		Delivery: forwardtest.DeliveryPolicy{
+			Window:      5 * time.Second,
+			LatestOnly:  true,
+			Concurrency: forwardtest.ConcurrencyPolicyCoalesce,
+		},
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+
+	// AND running the forwardtest
+
+	r.ForwardtestID = ft.ID
+	err = ft.Run(context.Background())
+	suite.Require().NoError(err)
+
+	// THEN the OnNewPrices callback is called with the gathered ticks
+
+	suite.Require().Eventually(func() bool {
+		return r.OnNewPricesCalls >= 2
+	}, 10*time.Minute, time.Millisecond*100)
+
+	// WHEN stopping the forwardtest
+	err = ft.Stop(context.Background())
+
+	// THEN no error is returned
+
+	suite.Require().NoError(err)
+	suite.Require().Eventually(func() bool {
+		return r.OnExitCalls >= 1
+	}, time.Second*10, time.Millisecond*100)
+}
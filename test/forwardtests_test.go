@@ -5,12 +5,17 @@ package test
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/clients"
 	"github.com/cryptellation/forwardtests/pkg/forwardtest"
 	"github.com/cryptellation/runtime"
 	"github.com/cryptellation/runtime/account"
 	"github.com/cryptellation/runtime/order"
+	"github.com/google/uuid"
+	"go.temporal.io/sdk/temporal"
 )
 
 // createTestCallbacks creates test callbacks for testing
@@ -124,6 +129,140 @@ func (suite *EndToEndSuite) TestCreateOrder() {
 	suite.Require().NotEqual(1000000.0, accounts["binance"].Balances["USDT"])
 }
 
+func (suite *EndToEndSuite) TestCreateOrderIdempotent() {
+	// GIVEN a forwardtest and an order with an ID
+
+	ft, err := suite.client.NewForwardtest(context.Background(), api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {Balances: map[string]float64{"USDT": 1000000}},
+		},
+		Callbacks: createTestCallbacks(),
+	}, clients.WithIdempotencyKey(uuid.New().String()))
+	suite.Require().NoError(err)
+	o := order.Order{
+		ID:       uuid.New(),
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	}
+
+	// WHEN submitting the order three times, the last one with another workflow
+
+	first, err := ft.CreateOrder(context.Background(), o)
+	suite.Require().NoError(err)
+	second, err := ft.CreateOrder(context.Background(), o)
+	suite.Require().NoError(err)
+	third, err := ft.CreateOrder(context.Background(), o, clients.WithWorkflowID(uuid.New().String()))
+	suite.Require().NoError(err)
+
+	// THEN the order is only executed once
+
+	suite.Require().False(first.Duplicate)
+	suite.Require().Equal(first, second)
+	suite.Require().True(third.Duplicate)
+	suite.Require().Equal(first.Order.ID, third.Order.ID)
+	suite.Require().Equal(first.Order.Price, third.Order.Price)
+
+	accounts, err := ft.ListAccounts(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(1.0, accounts["binance"].Balances["BTC"])
+}
+
+func (suite *EndToEndSuite) TestWatchEvents() {
+	// GIVEN a forwardtest with a filled order
+
+	ft, err := suite.client.NewForwardtest(context.Background(), api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {Balances: map[string]float64{"USDT": 1000000}},
+		},
+		Callbacks: createTestCallbacks(),
+	})
+	suite.Require().NoError(err)
+	o := order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	}
+	_, err = ft.CreateOrder(context.Background(), o)
+	suite.Require().NoError(err)
+
+	// WHEN watching the forwardtest from the start
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	events, _ := ft.Watch(ctx, 0)
+
+	// THEN the fill is received
+
+	filled := <-events
+	suite.Require().Equal(forwardtest.EventTypeOrderFilled, filled.Type)
+	suite.Require().Equal(ft.ID, filled.ForwardtestID)
+	suite.Require().NotZero(filled.ID)
+	cancel()
+
+	// WHEN resuming after the fill and submitting another order
+
+	ctx, cancel = context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	events, _ = ft.Watch(ctx, filled.ID)
+	_, err = ft.CreateOrder(context.Background(), o)
+	suite.Require().NoError(err)
+
+	// THEN only the new fill is received
+
+	next := <-events
+	suite.Require().Equal(forwardtest.EventTypeOrderFilled, next.Type)
+	suite.Require().Greater(next.ID, filled.ID)
+	suite.Require().NotEqual(filled.Order.ID, next.Order.ID)
+}
+
+func (suite *EndToEndSuite) TestCreateOrderRejectedByRisk() {
+	// GIVEN a forwardtest with a pair whitelist
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {
+				Balances: map[string]float64{
+					"USDT": 1000000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Risk: forwardtest.RiskLimits{
+			AllowedPairs: map[string][]string{"binance": {"ETH-USDT"}},
+		},
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+
+	// WHEN creating an order on a pair that is not allowed
+
+	_, err = ft.CreateOrder(context.Background(), order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	})
+
+	// THEN a risk rejection error is returned
+
+	var appErr *temporal.ApplicationError
+	suite.Require().True(errors.As(err, &appErr), err)
+	suite.Require().Equal(api.RiskRejectedErrorType, appErr.Type())
+	suite.Require().True(appErr.NonRetryable())
+
+	// AND the balances are unchanged
+
+	accounts, err := ft.ListAccounts(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(1000000.0, accounts["binance"].Balances["USDT"])
+}
+
 func (suite *EndToEndSuite) TestListForwardtestAccounts() {
 	// GIVEN a forwardtest with multiple accounts
 
@@ -196,3 +335,205 @@ func (suite *EndToEndSuite) TestGetForwardtest() {
 	suite.Require().Equal(createTestCallbacks(), retrievedFt.Callbacks)
 	suite.Require().Equal(forwardtest.StatusReady, retrievedFt.Status)
 }
+
+func (suite *EndToEndSuite) TestArchiveForwardtest() {
+	// GIVEN a forwardtest
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {
+				Balances: map[string]float64{
+					"USDT": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+
+	// WHEN archiving the forwardtest
+
+	err = ft.Archive(context.Background())
+	suite.Require().NoError(err)
+
+	// THEN it is not in the default listing
+
+	list, err := suite.client.ListForwardtests(context.Background(), api.ListForwardtestsWorkflowParams{})
+	suite.Require().NoError(err)
+	suite.Require().NotContains(list, ft)
+
+	// AND it is in the listing with archived forwardtests
+
+	list, err = suite.client.ListForwardtests(context.Background(), api.ListForwardtestsWorkflowParams{
+		IncludeArchived: true,
+	})
+	suite.Require().NoError(err)
+	suite.Require().Contains(list, ft)
+
+	// AND its data is kept
+
+	retrievedFt, err := ft.Get(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().True(retrievedFt.Archived)
+	suite.Require().Equal(1000.0, retrievedFt.Accounts["binance"].Balances["USDT"])
+}
+
+func (suite *EndToEndSuite) TestDeleteForwardtest() {
+	// GIVEN a forwardtest
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {
+				Balances: map[string]float64{
+					"USDT": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+
+	// WHEN deleting the forwardtest
+
+	err = ft.Delete(context.Background(), false)
+	suite.Require().NoError(err)
+
+	// THEN it can't be retrieved anymore
+
+	_, err = ft.Get(context.Background())
+	suite.Require().Error(err)
+
+	// AND it is not listed anymore
+
+	list, err := suite.client.ListForwardtests(context.Background(), api.ListForwardtestsWorkflowParams{
+		IncludeArchived: true,
+	})
+	suite.Require().NoError(err)
+	suite.Require().NotContains(list, ft)
+}
+
+func (suite *EndToEndSuite) TestUnsubscribeFromPrice() {
+	// GIVEN a forwardtest subscribed to a pair
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {
+				Balances: map[string]float64{
+					"USDT": 1000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+
+	err = ft.Subscribe(context.Background(), api.SubscribeToPriceWorkflowParams{
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+	})
+	suite.Require().NoError(err)
+
+	// WHEN unsubscribing from the pair
+
+	err = ft.Unsubscribe(context.Background(), "binance", "BTC-USDT")
+
+	// THEN no error is returned
+
+	suite.Require().NoError(err)
+
+	// AND the subscription is removed
+
+	subs, err := ft.ListSubscriptions(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Empty(subs)
+}
+
+func (suite *EndToEndSuite) TestCloneForwardtest() {
+	// GIVEN a forwardtest with an order
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {
+				Balances: map[string]float64{
+					"USDT": 1000000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+		Risk: forwardtest.RiskLimits{
+			MaxPositions: map[string]float64{"BTC": 10},
+		},
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+	_, err = ft.CreateOrder(context.Background(), order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	})
+	suite.Require().NoError(err)
+
+	// WHEN cloning the forwardtest
+
+	clone, err := ft.Clone(context.Background(), api.CloneForwardtestWorkflowParams{})
+	suite.Require().NoError(err)
+
+	// THEN the clone has the initial configuration and the parent ID
+
+	retrievedClone, err := clone.Get(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().NotEqual(ft.ID, retrievedClone.ID)
+	suite.Require().Equal(&ft.ID, retrievedClone.ParentID)
+	suite.Require().Equal(forwardtest.StatusReady, retrievedClone.Status)
+	suite.Require().Equal(1000000.0, retrievedClone.Accounts["binance"].Balances["USDT"])
+	suite.Require().Empty(retrievedClone.Orders)
+	suite.Require().Equal(createTestCallbacks(), retrievedClone.Callbacks)
+	suite.Require().Equal(params.Risk, retrievedClone.Risk)
+}
+
+func (suite *EndToEndSuite) TestResetForwardtest() {
+	// GIVEN a forwardtest with an order
+
+	params := api.CreateForwardtestWorkflowParams{
+		Accounts: map[string]account.Account{
+			"binance": {
+				Balances: map[string]float64{
+					"USDT": 1000000,
+				},
+			},
+		},
+		Callbacks: createTestCallbacks(),
+	}
+	ft, err := suite.client.NewForwardtest(context.Background(), params)
+	suite.Require().NoError(err)
+	_, err = ft.CreateOrder(context.Background(), order.Order{
+		Type:     order.TypeIsMarket,
+		Side:     order.SideIsBuy,
+		Exchange: "binance",
+		Pair:     "BTC-USDT",
+		Quantity: 1,
+	})
+	suite.Require().NoError(err)
+
+	// WHEN resetting the forwardtest
+
+	err = ft.Reset(context.Background())
+	suite.Require().NoError(err)
+
+	// THEN the forwardtest is back to its initial state with an audit entry
+
+	retrievedFt, err := ft.Get(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Equal(ft.ID, retrievedFt.ID)
+	suite.Require().Equal(forwardtest.StatusReady, retrievedFt.Status)
+	suite.Require().Empty(retrievedFt.Orders)
+	suite.Require().Equal(1000000.0, retrievedFt.Accounts["binance"].Balances["USDT"])
+	suite.Require().Zero(retrievedFt.Accounts["binance"].Balances["BTC"])
+	suite.Require().Len(retrievedFt.Audit, 1)
+	suite.Require().Equal(forwardtest.AuditActionReset, retrievedFt.Audit[0].Action)
+}
@@ -0,0 +1,127 @@
+//go:build e2e
+// +build e2e
+
+package test
+
+import (
+	"context"
+	"time"
+
+	"github.com/cryptellation/forwardtests/api"
+	"github.com/cryptellation/forwardtests/pkg/clients"
+	"github.com/cryptellation/runtime"
+	"github.com/cryptellation/runtime/account"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+const timersRunnerCallbackName = "ForwardtestE2eTimersRunner-OnTimer"
+
+type timersRunner struct {
+	testRunner
+
+	TaskQueue string
+	OnTimers  int
+}
+
+func (r *timersRunner) Name() string {
+	return "ForwardtestE2eTimersRunner"
+}
+
+func (r *timersRunner) OnInit(ctx workflow.Context, params runtime.OnInitCallbackWorkflowParams) error {
+	checkForwardtestRunContext(r.Suite, params.Context, r.ForwardtestID)
+
+	// Register a timer
+	_, err := r.WfClient.RegisterForwardtestTimer(ctx, api.RegisterForwardtestTimerWorkflowParams{
+		ForwardtestID: r.ForwardtestID,
+		Name:          "rebalance",
+		Interval:      time.Second,
+		Callback: runtime.CallbackWorkflow{
+			Name:          timersRunnerCallbackName,
+			TaskQueueName: r.TaskQueue,
+		},
+	})
+	r.Suite.Require().NoError(err)
+
+	r.OnInitCalls++
+	return err
+}
+
+func (r *timersRunner) OnTimer(_ workflow.Context, params api.OnTimerCallbackWorkflowParams) error {
+	checkForwardtestRunContext(r.Suite, params.Context, r.ForwardtestID)
+	r.Suite.Require().Equal("rebalance", params.Name)
+
+	r.OnTimers++
+	return nil
+}
+
+func (suite *EndToEndSuite) TestForwardtestRunWithTimer() {
+	// GIVEN a running worker
+	tq := "ForwardtestE2eTimersRunner-TaskQueue"
+	w := worker.New(suite.temporalclient, tq, worker.Options{})
+
+	// AND a runner registering a timer
+
+	accounts := map[string]account.Account{
+		"binance": {
+			Balances: map[string]float64{
+				"USDT": 1000,
+			},
+		},
+	}
+	r := &timersRunner{
+		testRunner: testRunner{
+			Accounts: accounts,
+			Suite:    suite,
+			WfClient: clients.NewWfClient(),
+		},
+		TaskQueue: tq,
+	}
+	callbacks := runtime.RegisterRunnable(w, tq, r)
+	w.RegisterWorkflowWithOptions(r.OnTimer, workflow.RegisterOptions{
+		Name: timersRunnerCallbackName,
+	})
+
+	go func() {
+		if err := w.Run(nil); err != nil {
+			suite.Require().NoError(err)
+		}
+	}()
+	defer w.Stop()
+
+	// WHEN creating and running a new forwardtest
+
+	ft, err := suite.client.NewForwardtest(context.Background(), api.CreateForwardtestWorkflowParams{
+		Accounts:  accounts,
+		Callbacks: callbacks,
+	})
+	suite.Require().NoError(err)
+	r.ForwardtestID = ft.ID
+	suite.Require().NoError(ft.Run(context.Background()))
+
+	// THEN the timer callback is called several times
+
+	suite.Require().Eventually(func() bool {
+		return r.OnTimers >= 3
+	}, time.Minute, 100*time.Millisecond)
+
+	// AND the timer is persisted
+
+	timers, err := ft.ListTimers(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Len(timers, 1)
+
+	// WHEN stopping the forwardtest
+
+	suite.Require().NoError(ft.Stop(context.Background()))
+
+	// THEN the timer is removed and not called anymore
+
+	timers, err = ft.ListTimers(context.Background())
+	suite.Require().NoError(err)
+	suite.Require().Empty(timers)
+
+	calls := r.OnTimers
+	time.Sleep(3 * time.Second)
+	suite.Require().LessOrEqual(r.OnTimers, calls+1)
+}